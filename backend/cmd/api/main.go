@@ -1,18 +1,20 @@
 package main
 
 import (
-	"database/sql"
-	"fmt"
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/company"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/user"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/cache"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/controllers"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/db/postgres"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/geocode"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/middleware"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/scraper"
 
@@ -25,50 +27,31 @@ func main() {
 		log.Fatalf("Failed to initialize Firebase: %v", err)
 	}
 
-	// Retrieve environment variables for database connection
-	dbUser := os.Getenv("DB_USER")
-	dbPass := os.Getenv("DB_PASS")
-	dbName := os.Getenv("DB_NAME")
-	dbHost := os.Getenv("DB_HOST") // Can be Unix socket path or IP address
-	dbPort := os.Getenv("DB_PORT") // Port number (default 5432 for PostgreSQL)
-
-	// Ensure all required environment variables are set
-	if dbUser == "" || dbPass == "" || dbName == "" || dbHost == "" {
-		log.Fatal("Database environment variables are not set properly.")
-	}
-
-	// Default port if not specified
-	if dbPort == "" {
-		dbPort = "5432"
-	}
-
-	// Build DSN - use port if it's a TCP connection (IP address), otherwise Unix socket
-	var dsn string
-	if dbPort != "" && !strings.HasPrefix(dbHost, "/cloudsql") {
-		dsn = fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable", dbUser, dbPass, dbName, dbHost, dbPort)
-	} else {
-		dsn = fmt.Sprintf("user=%s password=%s dbname=%s host=%s sslmode=disable", dbUser, dbPass, dbName, dbHost)
-	}
-
-	db, err := sql.Open("postgres", dsn)
+	// Connect to the database (DB_USER, DB_PASS, DB_NAME, DB_HOST, DB_PORT)
+	db, err := postgres.OpenFromEnv()
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
-
-	// Verify database connection
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
-	}
 	log.Println("Database connection established")
 
 	// Initialize repositories
 	listingRepo := postgres.NewListingRepository(db)
 	userRepo := postgres.NewUserRepository(db)
+	scrapeRunRepo := postgres.NewScrapeRunRepository(db)
+	companyRepo := postgres.NewCompanyRepository(db)
+
+	// Initialize geocoder (offline ZIP centroids, optionally backed by GEOCODER_URL)
+	geocoder, err := geocode.NewGeocoder()
+	if err != nil {
+		log.Fatalf("Failed to initialize geocoder: %v", err)
+	}
 
 	// Initialize services
 	listingService := listing.NewService(listingRepo)
+	listingService.SetGeocoder(geocoder)
 	userService := user.NewService(userRepo)
+	companyService := company.NewService(companyRepo)
 
 	// Initialize Redis cache
 	redisClient := cache.NewRedisClient()
@@ -76,11 +59,33 @@ func main() {
 
 	// Initialize scraper service (with repository for hybrid storage)
 	scraperService := scraper.NewScraperService(redisClient, listingRepo)
+	scraperService.SetGeocoder(geocoder)
+	scraperService.SetRunRepository(scrapeRunRepo)
+	scraperService.SetCompanyRepository(companyRepo)
+	go scraperService.WatchSources(context.Background()) // Hot-reload SCRAPE_SOURCES_FILE
+
+	// SCRAPE_MODE=background serves sales from Redis/PostgreSQL only and refreshes
+	// stale locations asynchronously; the scheduler (RUN_SCHEDULER=true here, or the
+	// cmd/scraper worker) keeps SCRAPE_LOCATIONS fresh
+	runScheduler := os.Getenv("RUN_SCHEDULER") == "true"
+	if os.Getenv("SCRAPE_MODE") == "background" || runScheduler {
+		scraperService.SetBackgroundRefresh(true)
+	}
+	if runScheduler {
+		schedulerConfig, err := scraper.SchedulerConfigFromEnv()
+		if err != nil {
+			log.Fatalf("Invalid scheduler configuration: %v", err)
+		}
+		go scraper.NewScheduler(scraperService, schedulerConfig).Run(context.Background())
+	}
 
 	// Initialize handlers
 	listingHandler := controllers.NewListingHandler(listingService, userService)
 	listingHandler.SetScraperService(scraperService)
 	userHandler := controllers.NewUserHandler(userService)
+	scrapeHandler := controllers.NewScrapeHandler(scrapeRunRepo, os.Getenv("ADMIN_UIDS"))
+	scrapeHandler.SetScrapeStats(scraperService)
+	companyHandler := controllers.NewCompanyHandler(companyService, listingService, userService)
 
 	// Set up the router using stdlib http.ServeMux
 	mux := http.NewServeMux()
@@ -120,6 +125,36 @@ func main() {
 			} else {
 				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 			}
+		} else if strings.HasSuffix(path, "/history") && strings.Count(strings.TrimPrefix(path, "/api/sales/"), "/") == 1 {
+			// Change timeline: /api/sales/{id}/history
+			if r.Method == http.MethodGet {
+				listingHandler.GetHistory(w, r)
+			} else {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		} else {
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	})))
+
+	// Estate sale companies - Public browsing
+	mux.Handle("/api/companies", corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			companyHandler.List(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
+	// Individual company and its sales: /api/companies/{id}, /api/companies/{id}/sales
+	mux.Handle("/api/companies/", corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/api/companies/")
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		} else if !strings.Contains(rest, "/") {
+			companyHandler.GetByID(w, r)
+		} else if strings.HasSuffix(rest, "/sales") && strings.Count(rest, "/") == 1 {
+			companyHandler.GetSales(w, r)
 		} else {
 			http.Error(w, "Not found", http.StatusNotFound)
 		}
@@ -150,6 +185,18 @@ func main() {
 		}
 	})))
 
+	// My Company - The seller's estate sale company (linked to all their sales)
+	mux.Handle("/api/my-company", corsMiddleware(authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			companyHandler.GetMyCompany(w, r)
+		case http.MethodPut:
+			companyHandler.SaveMyCompany(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
 	// Update sale
 	mux.Handle("/api/sales/update/", corsMiddleware(authMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPut {
@@ -177,14 +224,42 @@ func main() {
 		}
 	})))
 
+	// ==========================================
+	// ADMIN ENDPOINTS (authenticated, ADMIN_UIDS only)
+	// ==========================================
+
+	// Scrape runs and per-source health
+	mux.Handle("/api/admin/scrapes", corsMiddleware(authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			scrapeHandler.ListScrapes(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
+	// Listings held back from degraded scrape runs
+	mux.Handle("/api/admin/scrapes/quarantine", corsMiddleware(authMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			scrapeHandler.ListQuarantined(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
 	// Get PORT from environment or default to 8080
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
+	// Per-request deadline (REQUEST_TIMEOUT, e.g. "60s")
+	requestTimeout := middleware.DefaultRequestTimeout
+	if d, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT")); err == nil && d > 0 {
+		requestTimeout = d
+	}
+
 	log.Printf("Server starting on port %s...", port)
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
+	if err := http.ListenAndServe(":"+port, middleware.TimeoutMiddleware(requestTimeout, mux)); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/cache"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/db/postgres"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/geocode"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/scraper"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+)
+
+// One-off scrape of a single source, for debugging parsers and backfills:
+//
+//	go run ./cmd/scrape -source "EstateSale-Finder.com" -city Portland -state OR
+//
+// By default it's a dry run: the parsed listings are printed (-format table or json)
+// and neither PostgreSQL nor Redis is touched. With -commit the listings are saved like
+// a scheduled refresh (recorded as a 'manual' run) and the location's cache is cleared.
+// Without -source it lists the registered sources (including SCRAPE_SOURCES_FILE ones).
+func main() {
+	source := flag.String("source", "", "name of the source to scrape")
+	city := flag.String("city", "Portland", "city to scrape")
+	state := flag.String("state", "OR", "state to scrape")
+	format := flag.String("format", "table", "output format: table or json")
+	commit := flag.Bool("commit", false, "save the listings and invalidate the location's cache")
+	flag.Parse()
+
+	if *format != "table" && *format != "json" {
+		log.Fatalf("Unknown -format %q (want table or json)", *format)
+	}
+
+	// Ctrl-C/SIGTERM cancels the scrape
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var scraperService *scraper.ScraperService
+	if *commit {
+		db, err := postgres.OpenFromEnv()
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+
+		geocoder, err := geocode.NewGeocoder()
+		if err != nil {
+			log.Fatalf("Failed to initialize geocoder: %v", err)
+		}
+
+		redisClient := cache.NewRedisClient()
+		defer redisClient.Close()
+
+		scraperService = scraper.NewScraperService(redisClient, postgres.NewListingRepository(db))
+		scraperService.SetGeocoder(geocoder)
+		scraperService.SetRunRepository(postgres.NewScrapeRunRepository(db))
+		scraperService.SetCompanyRepository(postgres.NewCompanyRepository(db))
+	} else {
+		scraperService = scraper.NewScraperService(nil, nil)
+	}
+
+	if *source == "" {
+		fmt.Println("Registered sources (pass one with -source):")
+		for _, src := range scraperService.Sources() {
+			fmt.Printf("  %s\n", src.Name())
+		}
+		return
+	}
+
+	result, run, err := scraperService.ScrapeSource(ctx, *source, *city, *state, *commit)
+	if result != nil {
+		if *format == "json" {
+			printJSON(result.Listings)
+		} else {
+			printTable(result.Listings)
+		}
+		log.Printf("%d rows, %d listings, %d unparseable", result.RowsSeen, len(result.Listings), len(result.SkippedIDs))
+	}
+	if run != nil {
+		logRun(run, *commit)
+	}
+	if err != nil {
+		log.Fatalf("Scrape of %s for %s, %s failed: %v", *source, *city, *state, err)
+	}
+}
+
+// printJSON writes the listings to stdout as a JSON array
+func printJSON(sales []listing.ScrapedListing) {
+	if sales == nil {
+		sales = []listing.ScrapedListing{}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(sales); err != nil {
+		log.Fatalf("Failed to encode listings: %v", err)
+	}
+}
+
+// printTable writes one line per listing to stdout
+func printTable(sales []listing.ScrapedListing) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "EXTERNAL ID\tSTART\tEND\tSESSIONS\tADDRESS\tCITY\tZIP\tTITLE")
+	for _, sale := range sales {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			sale.ExternalID, formatDate(sale.StartDate), formatDate(sale.EndDate), len(sale.Sessions),
+			sale.Address, sale.City, sale.ZipCode, truncate(sale.Title, 50))
+	}
+	w.Flush()
+}
+
+// logRun reports the fill rates and, for a committed run, what was saved
+func logRun(run *scrape.Run, committed bool) {
+	if r := run.FillRates; r != nil && r.Rows > 0 {
+		log.Printf("Fill rates: date %.0f%%, address %.0f%%, zip %.0f%%, city %.0f%%",
+			r.Date*100, r.Address*100, r.ZipCode*100, r.City*100)
+	}
+	switch {
+	case !committed:
+		log.Printf("Dry run: nothing saved (use -commit to persist)")
+	case run.Degraded:
+		log.Printf("Run %d degraded (%s): %d listings quarantined", run.ID, run.DegradedReason, run.Quarantined)
+	case run.Succeeded():
+		log.Printf("Run %d: %d new, %d updated, %d unchanged, %d failed, %d missing, %d tombstoned",
+			run.ID, run.Inserted, run.Updated, run.Unchanged, run.Failed, run.Missing, run.Tombstoned)
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("Mon Jan 2 15:04")
+}
+
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
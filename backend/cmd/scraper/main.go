@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/cache"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/db/postgres"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/geocode"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/scraper"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+)
+
+// Scrape worker: refreshes SCRAPE_LOCATIONS on SCRAPE_INTERVAL so the API
+// (with SCRAPE_MODE=background) never scrapes on a user's request.
+// Use -once to refresh every location a single time and exit (e.g. from a cron job).
+func main() {
+	once := flag.Bool("once", false, "refresh every location once and exit")
+	flag.Parse()
+
+	db, err := postgres.OpenFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	log.Println("Database connection established")
+
+	config, err := scraper.SchedulerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid scheduler configuration: %v", err)
+	}
+
+	geocoder, err := geocode.NewGeocoder()
+	if err != nil {
+		log.Fatalf("Failed to initialize geocoder: %v", err)
+	}
+
+	redisClient := cache.NewRedisClient()
+	defer redisClient.Close()
+
+	scraperService := scraper.NewScraperService(redisClient, postgres.NewListingRepository(db))
+	scraperService.SetGeocoder(geocoder)
+	scraperService.SetRunRepository(postgres.NewScrapeRunRepository(db))
+	scraperService.SetCompanyRepository(postgres.NewCompanyRepository(db))
+
+	scheduler := scraper.NewScheduler(scraperService, config)
+
+	// Ctrl-C/SIGTERM cancels in-flight scrapes and queries
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		if err := scheduler.RunOnce(ctx); err != nil {
+			log.Fatalf("Refresh failed: %v", err)
+		}
+		return
+	}
+
+	go scraperService.WatchSources(ctx) // Hot-reload SCRAPE_SOURCES_FILE
+	scheduler.Run(ctx)
+}
@@ -0,0 +1,206 @@
+package address
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Address is a free-text US address split into parts. Any part may be empty.
+type Address struct {
+	Street  string // "1234 SE Main St" (empty if withheld)
+	Unit    string // "Apt 4"
+	City    string
+	State   string // Two-letter code, upper case
+	ZipCode string
+
+	// Withheld is set for "TBA" / "address released Thursday" listings;
+	// ReleaseNote keeps the source's wording (e.g. "address released Thursday")
+	Withheld    bool
+	ReleaseNote string
+}
+
+var (
+	// withheldRegex matches withheld-address markers at the start of the text, e.g.
+	// "TBA", "Address TBA", "Address released Thursday", "Address will be posted Friday at 6pm"
+	withheldRegex = regexp.MustCompile(`(?i)^(?:\s*(?:tba|tbd|address\s+(?:tba|tbd|to be announced|(?:will be\s+)?(?:released|posted|available)` +
+		`(?:\s+(?:on\s+)?(?:mon|tues|wednes|thurs|fri|satur|sun)day)?(?:\s+(?:night|morning|evening))?` +
+		`(?:\s+(?:at\s+)?\d{1,2}(?::\d{2})?\s*[ap]\.?m\.?)?))\b[\s.:-]*)+`)
+
+	zipRegex = regexp.MustCompile(`[\s,]*\b(\d{5})(?:-\d{4})?\s*$`)
+
+	// unitRegex matches a unit designator and its number, e.g. "Apt 4", "#12", "Unit B"
+	unitRegex = regexp.MustCompile(`(?i)^(?:apt|apartment|unit|suite|ste|bldg|building|space|spc|lot|#)\.?\s*#?\s*[a-z0-9-]+$`)
+
+	// unitInlineRegex finds a unit inside a street line without commas
+	unitInlineRegex = regexp.MustCompile(`(?i)\s+((?:apt|apartment|unit|suite|ste|bldg|building|space|spc|lot)\.?\s*#?\s*[a-z0-9-]+|#\s*[a-z0-9-]+)\b`)
+)
+
+// streetSuffixes ends the street part of an address without commas
+var streetSuffixes = map[string]bool{
+	"st": true, "street": true, "ave": true, "av": true, "avenue": true, "rd": true, "road": true,
+	"dr": true, "drive": true, "ln": true, "lane": true, "ct": true, "court": true, "pl": true,
+	"place": true, "blvd": true, "boulevard": true, "way": true, "hwy": true, "highway": true,
+	"pkwy": true, "parkway": true, "ter": true, "terrace": true, "cir": true, "circle": true,
+	"loop": true, "trl": true, "trail": true, "sq": true, "square": true, "cres": true, "path": true,
+}
+
+var directionals = map[string]bool{
+	"n": true, "s": true, "e": true, "w": true, "ne": true, "nw": true, "se": true, "sw": true,
+}
+
+// Parse splits a free-text address such as "1234 SE Main St, Apt 4, Tigard, OR 97223",
+// "TBA Hillsboro OR 97124" or "1234 SE Main St Portland OR". ok is false when the text
+// doesn't look like an address at all (no street, state, ZIP or withheld marker).
+func Parse(text string) (addr Address, ok bool) {
+	text = strings.Join(strings.Fields(text), " ")
+
+	if m := withheldRegex.FindStringSubmatch(text); m != nil {
+		addr.Withheld = true
+		addr.ReleaseNote = strings.TrimSpace(strings.TrimRight(m[0], " .:-"))
+		text = strings.TrimSpace(text[len(m[0]):])
+	}
+
+	if m := zipRegex.FindStringSubmatchIndex(text); m != nil {
+		addr.ZipCode = text[m[2]:m[3]]
+		text = strings.TrimSpace(text[:m[0]])
+	}
+
+	text, addr.State = trimState(text)
+	text = strings.Trim(text, " ,")
+
+	parts := splitComma(text)
+	switch {
+	case len(parts) == 0:
+	case len(parts) == 1:
+		addr.Street, addr.Unit, addr.City = splitStreetCity(parts[0], addr.State != "" || addr.ZipCode != "")
+	default:
+		addr.Street, addr.Unit, _ = splitStreetCity(parts[0], false)
+		last := parts[len(parts)-1]
+		middle := parts[1 : len(parts)-1]
+		if unitRegex.MatchString(last) {
+			middle = append(middle, last)
+			last = ""
+		}
+		for _, p := range middle {
+			if unitRegex.MatchString(p) && addr.Unit == "" {
+				addr.Unit = p
+			}
+		}
+		addr.City = last
+	}
+
+	// "TBA" text with the marker stripped may leave the city where the street was
+	if addr.Withheld && addr.Street != "" && !startsWithNumber(addr.Street) && addr.City == "" {
+		addr.Street, addr.City = "", addr.Street
+	}
+	if addr.Withheld {
+		addr.Street = ""
+	}
+
+	ok = addr.Withheld || addr.ZipCode != "" || addr.State != "" || startsWithNumber(addr.Street)
+	return addr, ok
+}
+
+// IsWithheld reports whether a street value is a withheld-address marker ("TBA", blank, ...)
+func IsWithheld(street string) bool {
+	street = strings.TrimSpace(street)
+	return street == "" || withheldRegex.MatchString(street)
+}
+
+// StateCode returns the two-letter code for a state code or name ("" if unknown)
+func StateCode(state string) string {
+	state = strings.TrimSpace(state)
+	if code := strings.ToUpper(state); stateCodes[code] {
+		return code
+	}
+	return stateNames[strings.ToLower(state)]
+}
+
+// Line joins the street and unit, e.g. "1234 SE Main St, Apt 4"
+func (a Address) Line() string {
+	if a.Unit == "" {
+		return a.Street
+	}
+	if a.Street == "" {
+		return a.Unit
+	}
+	return a.Street + ", " + a.Unit
+}
+
+// trimState removes a trailing state (code or name) from text
+func trimState(text string) (string, string) {
+	fields := strings.Fields(strings.TrimRight(text, " ,"))
+	if len(fields) == 0 {
+		return text, ""
+	}
+
+	// Two-word names ("New York"), then one-word names and codes
+	if len(fields) >= 2 {
+		name := strings.ToLower(strings.Trim(fields[len(fields)-2], ",") + " " + fields[len(fields)-1])
+		if code, ok := stateNames[name]; ok {
+			return strings.Join(fields[:len(fields)-2], " "), code
+		}
+	}
+
+	last := strings.Trim(fields[len(fields)-1], ",.")
+	if code, ok := stateNames[strings.ToLower(last)]; ok {
+		return strings.Join(fields[:len(fields)-1], " "), code
+	}
+	// Codes must be upper case: "or" and "in" are ordinary words
+	if len(last) == 2 && last == strings.ToUpper(last) && stateCodes[last] {
+		return strings.Join(fields[:len(fields)-1], " "), last
+	}
+	return text, ""
+}
+
+// splitStreetCity splits a comma-free "1234 SE Main St Apt 4 Tigard" at the street suffix.
+// Text that isn't a numbered street is a bare city when hasRegion is set (it came before a state or ZIP).
+func splitStreetCity(text string, hasRegion bool) (street, unit, city string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", "", ""
+	}
+	if !startsWithNumber(text) {
+		if hasRegion {
+			return "", "", text
+		}
+		return text, "", ""
+	}
+
+	if m := unitInlineRegex.FindStringSubmatchIndex(text); m != nil {
+		unit = strings.TrimSpace(text[m[2]:m[3]])
+		rest := strings.TrimSpace(text[m[1]:])
+		return strings.TrimSpace(text[:m[0]]), unit, rest
+	}
+
+	fields := strings.Fields(text)
+	end := -1
+	for i := 1; i < len(fields); i++ {
+		if streetSuffixes[strings.ToLower(strings.TrimRight(fields[i], "."))] {
+			end = i
+		}
+	}
+	if end < 0 {
+		return text, "", ""
+	}
+
+	// "Hwy 212", "Main St NE"
+	for end+1 < len(fields) && (startsWithNumber(fields[end+1]) || directionals[strings.ToLower(fields[end+1])]) {
+		end++
+	}
+	return strings.Join(fields[:end+1], " "), "", strings.Join(fields[end+1:], " ")
+}
+
+func splitComma(text string) []string {
+	var parts []string
+	for _, p := range strings.Split(text, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func startsWithNumber(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
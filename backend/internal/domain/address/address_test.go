@@ -0,0 +1,70 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParse tests splitting free-text addresses
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Address
+	}{
+		{"full with commas", "1234 SE Main St, Apt 4, Tigard, OR 97223",
+			Address{Street: "1234 SE Main St", Unit: "Apt 4", City: "Tigard", State: "OR", ZipCode: "97223"}},
+		{"no commas", "1234 SE Main St Portland OR 97202",
+			Address{Street: "1234 SE Main St", City: "Portland", State: "OR", ZipCode: "97202"}},
+		{"inline unit", "55 NW 23rd Ave #310 Hillsboro OR 97124",
+			Address{Street: "55 NW 23rd Ave", Unit: "#310", City: "Hillsboro", State: "OR", ZipCode: "97124"}},
+		{"trailing directional", "1234 Main St NE Salem, OR",
+			Address{Street: "1234 Main St NE", City: "Salem", State: "OR"}},
+		{"highway number", "12345 Hwy 212 Boring OR 97009",
+			Address{Street: "12345 Hwy 212", City: "Boring", State: "OR", ZipCode: "97009"}},
+		{"state name and zip+4", "1234 SE Main Street, Portland, Oregon 97202-1234",
+			Address{Street: "1234 SE Main Street", City: "Portland", State: "OR", ZipCode: "97202"}},
+		{"words containing OR", "800 Orchard Way, Floor 2, Vancouver, WA 98661",
+			Address{Street: "800 Orchard Way", City: "Vancouver", State: "WA", ZipCode: "98661"}},
+		{"TBA", "TBA Hillsboro OR 97124",
+			Address{City: "Hillsboro", State: "OR", ZipCode: "97124", Withheld: true, ReleaseNote: "TBA"}},
+		{"release note", "Address released Thursday, Lake Oswego, OR 97034",
+			Address{City: "Lake Oswego", State: "OR", ZipCode: "97034", Withheld: true, ReleaseNote: "Address released Thursday"}},
+		{"TBA with note", "TBA - Address will be posted Friday at 6pm Beaverton OR",
+			Address{City: "Beaverton", State: "OR", Withheld: true, ReleaseNote: "TBA - Address will be posted Friday at 6pm"}},
+		{"city only", "Milwaukie, OR", Address{City: "Milwaukie", State: "OR"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.text)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestParseNotAnAddress tests that other sale text isn't taken for an address
+func TestParseNotAnAddress(t *testing.T) {
+	for _, text := range []string{"Opens 31st Oct 10:00am", "Fri 9am-5pm, Sat 9am-3pm", "Everything must go or we donate it", ""} {
+		_, ok := Parse(text)
+		assert.False(t, ok, text)
+	}
+}
+
+// TestIsWithheld tests withheld-address markers
+func TestIsWithheld(t *testing.T) {
+	assert.True(t, IsWithheld("TBA"))
+	assert.True(t, IsWithheld(" "))
+	assert.True(t, IsWithheld("Address released Thursday"))
+	assert.False(t, IsWithheld("1234 SE Main St"))
+	assert.False(t, IsWithheld("Tbar Ranch Rd"))
+}
+
+// TestStateCode tests state code lookup
+func TestStateCode(t *testing.T) {
+	assert.Equal(t, "OR", StateCode("or"))
+	assert.Equal(t, "WA", StateCode(" Washington "))
+	assert.Equal(t, "", StateCode("Cascadia"))
+}
@@ -0,0 +1,91 @@
+package company
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Company is an estate sale company (or other provider) that runs sales. Companies are
+// created by scrapers from a sale's provider details and by sellers for their own
+// account. Scraped companies are never matched to a seller's, so nobody can take over
+// a scraped company's contact details by registering its name.
+type Company struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Website string `json:"website,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+	Email   string `json:"email,omitempty"`
+
+	// Where the company was found (scraped companies only)
+	Source     string `json:"source,omitempty"`      // e.g. "EstateSale-Finder.com"
+	ProfileURL string `json:"profile_url,omitempty"` // The company's page on that source
+
+	SellerID *int `json:"seller_id,omitempty"` // Set when a seller account manages the company
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Filters represents query parameters for listing companies
+type Filters struct {
+	Query  string // Case-insensitive substring of the name
+	Limit  int
+	Offset int
+}
+
+var (
+	nonAlnumRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+	// Business suffixes that don't tell companies apart ("Grace Estate Sales LLC")
+	suffixRegex = regexp.MustCompile(`(?:\s(?:llc|inc|co|corp|ltd))+$`)
+)
+
+// NormalizeName reduces a company name to the key scraped companies are matched on:
+// lower case, punctuation and business suffixes dropped, "&" read as "and"
+func NormalizeName(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "&", " and ")
+	name = strings.TrimSpace(nonAlnumRegex.ReplaceAllString(name, " "))
+	return strings.TrimSpace(suffixRegex.ReplaceAllString(name, ""))
+}
+
+// Validate checks a seller-submitted company
+func (c *Company) Validate() []string {
+	var errs []string
+	if NormalizeName(c.Name) == "" {
+		errs = append(errs, "name is required")
+	}
+	if c.Website != "" {
+		if u, err := url.Parse(c.Website); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Sprintf("website %q must be an http(s) URL", c.Website))
+		}
+	}
+	if c.Email != "" && !strings.Contains(c.Email, "@") {
+		errs = append(errs, fmt.Sprintf("email %q is not an email address", c.Email))
+	}
+	return errs
+}
+
+// MergeScraped folds a freshly scraped copy of the company into the stored one. Newer
+// values win, but a source that doesn't show a detail (e.g. a list page without the
+// phone) doesn't blank it.
+func (c *Company) MergeScraped(scraped Company) {
+	for _, field := range []struct {
+		stored *string
+		value  string
+	}{
+		{&c.Name, scraped.Name},
+		{&c.Website, scraped.Website},
+		{&c.Phone, scraped.Phone},
+		{&c.Email, scraped.Email},
+	} {
+		if field.value != "" {
+			*field.stored = field.value
+		}
+	}
+	if c.ProfileURL == "" {
+		c.Source, c.ProfileURL = scraped.Source, scraped.ProfileURL
+	}
+}
@@ -0,0 +1,19 @@
+package company
+
+import "context"
+
+// Repository defines the interface for company data operations.
+type Repository interface {
+	GetByID(ctx context.Context, id int) (*Company, error)
+	GetBySellerID(ctx context.Context, sellerID int) (*Company, error) // nil if the seller has none
+	List(ctx context.Context, filters Filters) ([]Company, error)
+
+	// SaveSellerCompany creates or updates the company managed by c.SellerID and links
+	// the seller's listings to it
+	SaveSellerCompany(ctx context.Context, c *Company) error
+
+	// UpsertScraped matches a scraped company to a stored scraped one (by profile URL,
+	// then by normalized name), merging it in (see MergeScraped) or inserting it.
+	// c is updated to the stored company.
+	UpsertScraped(ctx context.Context, c *Company) error
+}
@@ -0,0 +1,48 @@
+package company
+
+import (
+	"context"
+	"strings"
+)
+
+// Service handles business logic for companies
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a new company service
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// ListCompanies retrieves companies, optionally filtered by name
+func (s *Service) ListCompanies(ctx context.Context, filters Filters) ([]Company, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = 50
+	}
+	if filters.Limit > 200 {
+		filters.Limit = 200
+	}
+	filters.Query = strings.TrimSpace(filters.Query)
+
+	return s.repo.List(ctx, filters)
+}
+
+// GetCompany retrieves a company by ID
+func (s *Service) GetCompany(ctx context.Context, id int) (*Company, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetSellerCompany retrieves the company a seller manages (nil if none)
+func (s *Service) GetSellerCompany(ctx context.Context, sellerID int) (*Company, error) {
+	return s.repo.GetBySellerID(ctx, sellerID)
+}
+
+// SaveSellerCompany creates or updates a seller's company (source and profile URL are
+// only set by scrapers)
+func (s *Service) SaveSellerCompany(ctx context.Context, sellerID int, c *Company) error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.SellerID = &sellerID
+
+	return s.repo.SaveSellerCompany(ctx, c)
+}
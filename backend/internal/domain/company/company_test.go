@@ -0,0 +1,45 @@
+package company
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeName tests the key scraped companies are matched on
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"Grace Estate Sales":            "grace estate sales",
+		"  GRACE ESTATE SALES, LLC.":    "grace estate sales",
+		"Rose City Liquidators Inc":     "rose city liquidators",
+		"Smith & Sons Estate Sales Co.": "smith and sons estate sales",
+		"Caring Transitions PDX":        "caring transitions pdx",
+		"!!!":                           "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, NormalizeName(name), name)
+	}
+}
+
+// TestMergeScraped tests that newer details win without blanking stored ones
+func TestMergeScraped(t *testing.T) {
+	stored := Company{ID: 7, Name: "Grace Estate Sales", Phone: "(503) 555-0100", Website: "https://old.example.com"}
+	stored.MergeScraped(Company{
+		Name: "Grace Estate Sales LLC", Website: "https://grace.example.com",
+		Source: "EstateSale-Finder.com", ProfileURL: "https://www.estatesale-finder.com/company.php?id=87",
+	})
+
+	assert.Equal(t, 7, stored.ID)
+	assert.Equal(t, "Grace Estate Sales LLC", stored.Name)
+	assert.Equal(t, "(503) 555-0100", stored.Phone, "not shown this time")
+	assert.Equal(t, "https://grace.example.com", stored.Website)
+	assert.Equal(t, "https://www.estatesale-finder.com/company.php?id=87", stored.ProfileURL)
+	assert.Equal(t, "EstateSale-Finder.com", stored.Source)
+}
+
+// TestValidate tests seller-submitted company checks
+func TestValidate(t *testing.T) {
+	assert.Empty(t, (&Company{Name: "Grace Estate Sales", Website: "https://grace.example.com", Email: "hi@grace.example.com"}).Validate())
+	assert.Len(t, (&Company{Name: " - "}).Validate(), 1)
+	assert.Len(t, (&Company{Name: "A", Website: "grace.example.com", Email: "nope"}).Validate(), 2)
+}
@@ -0,0 +1,91 @@
+package dedup
+
+import (
+	"strings"
+	"unicode"
+)
+
+// streetAbbreviations maps USPS street suffixes and directionals to one spelling
+var streetAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "av": "ave", "boulevard": "blvd", "road": "rd",
+	"drive": "dr", "lane": "ln", "court": "ct", "place": "pl", "terrace": "ter",
+	"circle": "cir", "parkway": "pkwy", "highway": "hwy", "way": "way", "loop": "loop",
+	"north": "n", "south": "s", "east": "e", "west": "w",
+	"northeast": "ne", "northwest": "nw", "southeast": "se", "southwest": "sw",
+}
+
+// unitDesignators start the unit part of an address, which is dropped
+var unitDesignators = map[string]bool{
+	"apt": true, "apartment": true, "unit": true, "ste": true, "suite": true, "#": true,
+}
+
+// NormalizeAddress reduces a street address to a comparable key, e.g.
+// "1234 S.E. Main Street, Apt 2" -> "1234 se main st".
+// Returns "" for withheld ("TBA") or blank addresses, which never match.
+func NormalizeAddress(address string) string {
+	// City/state/zip or unit after the first comma
+	if i := strings.Index(address, ","); i >= 0 {
+		address = address[:i]
+	}
+
+	// "S.E." -> "SE", then punctuation to spaces (keeping '#' for units)
+	address = strings.ReplaceAll(address, ".", "")
+	address = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '#' {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, address)
+	address = strings.ReplaceAll(address, "#", " # ")
+
+	var tokens []string
+	for _, token := range strings.Fields(address) {
+		if unitDesignators[token] {
+			break
+		}
+		if abbr, ok := streetAbbreviations[token]; ok {
+			token = abbr
+		}
+		tokens = append(tokens, token)
+	}
+
+	if len(tokens) == 0 || tokens[0] == "tba" {
+		return ""
+	}
+	return strings.Join(tokens, " ")
+}
+
+// titleStopWords are too common in sale titles to say anything about a match
+var titleStopWords = map[string]bool{
+	"estate": true, "sale": true, "sales": true, "the": true, "a": true, "an": true,
+	"and": true, "of": true, "in": true, "at": true, "by": true, "&": true,
+}
+
+// titleTokens returns the distinctive lowercase words of a title
+func titleTokens(title string) map[string]bool {
+	tokens := map[string]bool{}
+	for _, token := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !titleStopWords[token] {
+			tokens[token] = true
+		}
+	}
+	return tokens
+}
+
+// TitleSimilarity is the Jaccard index of two titles' distinctive words (0 to 1)
+func TitleSimilarity(a, b string) float64 {
+	ta, tb := titleTokens(a), titleTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range ta {
+		if tb[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
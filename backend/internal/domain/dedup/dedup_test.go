@@ -0,0 +1,144 @@
+package dedup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var saleStart = time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC)
+
+func scraped(id, source, title, address string) *listing.AggregatedListing {
+	return &listing.AggregatedListing{
+		ID:        id,
+		Title:     title,
+		Address:   address,
+		City:      "Portland",
+		State:     "OR",
+		ZipCode:   "97202",
+		StartDate: saleStart,
+		EndDate:   saleStart.Add(32 * time.Hour),
+		IsScraped: true,
+		Source:    &listing.SourceLink{Name: source, URL: "https://" + source + "/" + id},
+	}
+}
+
+// TestNormalizeAddress tests reducing street addresses to comparable keys
+func TestNormalizeAddress(t *testing.T) {
+	assert.Equal(t, "1234 se main st", NormalizeAddress("1234 S.E. Main Street"))
+	assert.Equal(t, "1234 se main st", NormalizeAddress("1234 SE Main St., Apt 2"))
+	assert.Equal(t, "1234 se main st", NormalizeAddress("1234 Southeast Main St #2"))
+	assert.Equal(t, "", NormalizeAddress("TBA"))
+	assert.Equal(t, "", NormalizeAddress("TBA - released Thursday"))
+	assert.Equal(t, "", NormalizeAddress("  "))
+}
+
+// TestTitleSimilarity tests title overlap ignoring filler words
+func TestTitleSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, TitleSimilarity("Sellwood Mid-Century Estate Sale", "Mid Century Sellwood Sale"))
+	assert.Equal(t, 0.0, TitleSimilarity("Estate Sale", "Estate Sale"))
+	assert.Less(t, TitleSimilarity("Sellwood Mid-Century", "Gresham Farm Tools"), MinTitleSimilarity)
+}
+
+// TestIsDuplicate tests the matching rules
+func TestIsDuplicate(t *testing.T) {
+	a := scraped("a-1", "site-a", "Sellwood Mid-Century Sale", "1234 SE Main Street")
+	b := scraped("b-9", "site-b", "Huge Estate Sale!", "1234 S.E. Main St")
+	assert.True(t, IsDuplicate(a, b), "same address, overlapping days")
+
+	sameSite := scraped("a-2", "site-a", "Huge Estate Sale!", "1234 SE Main St")
+	assert.False(t, IsDuplicate(a, sameSite), "one site never lists a sale twice")
+
+	nextWeek := scraped("b-10", "site-b", "Sellwood Mid-Century Sale", "1234 SE Main St")
+	nextWeek.StartDate = saleStart.Add(7 * 24 * time.Hour)
+	nextWeek.EndDate = nextWeek.StartDate
+	assert.False(t, IsDuplicate(a, nextWeek), "same house, different weekend")
+
+	withheld := scraped("b-11", "site-b", "Mid Century Sellwood Estate Sale", "TBA")
+	assert.True(t, IsDuplicate(a, withheld), "withheld address, matching title")
+
+	otherTitle := scraped("b-12", "site-b", "Gresham Farm Tools", "TBA")
+	assert.False(t, IsDuplicate(a, otherTitle))
+
+	otherStreet := scraped("b-13", "site-b", "Sellwood Mid-Century Sale", "99 NE Oak St")
+	assert.False(t, IsDuplicate(a, otherStreet), "known addresses disagree")
+}
+
+// TestIsDuplicate_RooftopPins tests coordinates rescuing an address typo, but not ZIP centroids
+func TestIsDuplicate_RooftopPins(t *testing.T) {
+	lat, lng := 45.4760, -122.6490
+	nearLat := 45.4762
+
+	a := scraped("a-1", "site-a", "Sellwood Mid-Century Sale", "1234 SE Main St")
+	a.Latitude, a.Longitude, a.GeoPrecision = &lat, &lng, listing.GeoPrecisionRooftop
+	b := scraped("b-1", "site-b", "Mid-Century Sellwood Sale", "1243 SE Main St")
+	b.Latitude, b.Longitude, b.GeoPrecision = &nearLat, &lng, listing.GeoPrecisionRooftop
+	assert.True(t, IsDuplicate(a, b))
+
+	b.GeoPrecision = listing.GeoPrecisionZip
+	assert.False(t, IsDuplicate(a, b))
+}
+
+// TestMerge tests collapsing duplicates into a canonical listing with all sources
+func TestMerge(t *testing.T) {
+	a := scraped("a-1", "site-a", "Sellwood Mid-Century Sale", "TBA")
+	b := scraped("b-9", "site-b", "Mid Century Sellwood Estate Sale", "1234 SE Main St")
+	b.ImageURLs = []string{"https://img/1.jpg"}
+	a.EventType = listing.EventMovingSale
+	other := scraped("b-10", "site-b", "Gresham Farm Tools", "55 NE Oak St")
+
+	merged := Merge([]*listing.AggregatedListing{a, other, b})
+	require.Len(t, merged, 2)
+
+	// The cluster keeps its first listing's position; the record with an address is canonical
+	canonical := merged[0]
+	assert.Equal(t, "b-9", canonical.ID)
+	assert.Equal(t, []string{"a-1"}, canonical.DuplicateIDs)
+	require.Len(t, canonical.Sources, 2)
+	assert.Equal(t, "site-b", canonical.Sources[0].Name)
+	assert.Equal(t, "site-a", canonical.Sources[1].Name)
+	assert.Equal(t, []string{"https://img/1.jpg"}, canonical.ImageURLs)
+	assert.Equal(t, listing.EventMovingSale, canonical.EventType, "gaps filled from duplicates")
+
+	assert.Same(t, other, merged[1])
+	assert.Empty(t, other.Sources)
+}
+
+// TestMerge_OwnedListingWins tests that a seller's own listing absorbs scraped copies
+func TestMerge_OwnedListingWins(t *testing.T) {
+	owned := &listing.AggregatedListing{
+		ID:        "42",
+		Title:     "Sellwood Mid-Century Sale",
+		Address:   "1234 SE Main St, Unit B",
+		City:      "Portland",
+		State:     "OR",
+		ZipCode:   "97202",
+		StartDate: saleStart,
+		EndDate:   saleStart.Add(8 * time.Hour),
+	}
+	a := scraped("a-1", "site-a", "Huge Sale", "1234 Southeast Main Street")
+	a.ThumbnailURL = "https://img/thumb.jpg"
+	b := scraped("b-9", "site-b", "Huge Sale", "1234 SE Main St")
+
+	merged := Merge([]*listing.AggregatedListing{a, owned, b})
+	require.Len(t, merged, 1)
+	assert.Equal(t, "42", merged[0].ID)
+	assert.False(t, merged[0].IsScraped)
+	assert.Nil(t, merged[0].Source)
+	assert.ElementsMatch(t, []string{"a-1", "b-9"}, merged[0].DuplicateIDs)
+	assert.Len(t, merged[0].Sources, 2)
+	assert.Equal(t, "https://img/thumb.jpg", merged[0].ThumbnailURL, "gaps filled from duplicates")
+}
+
+// TestMerge_NoChaining tests that near matches can't pull two sales from one site together
+func TestMerge_NoChaining(t *testing.T) {
+	a1 := scraped("a-1", "site-a", "Sellwood Mid-Century Sale", "TBA")
+	b := scraped("b-1", "site-b", "Sellwood Mid-Century Sale", "TBA")
+	a2 := scraped("a-2", "site-a", "Sellwood Mid-Century Sale", "TBA")
+
+	merged := Merge([]*listing.AggregatedListing{a1, b, a2})
+	assert.Len(t, merged, 2)
+}
@@ -0,0 +1,94 @@
+package dedup
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+const (
+	// MaxPinDistanceMiles is how close two rooftop pins must be to be the same house (~80m)
+	MaxPinDistanceMiles = 0.05
+
+	// MinTitleSimilarity is the title overlap needed when addresses can't decide
+	MinTitleSimilarity = 0.5
+)
+
+// IsDuplicate reports whether a and b describe the same sale.
+// They must run on overlapping days and come from different sources; then a matching
+// street address decides, falling back to rooftop pins and title similarity when an
+// address is withheld.
+func IsDuplicate(a, b *listing.AggregatedListing) bool {
+	if sameSource(a, b) || !datesOverlap(a, b) {
+		return false
+	}
+
+	addressA, addressB := NormalizeAddress(a.Address), NormalizeAddress(b.Address)
+	titleSimilarity := TitleSimilarity(a.Title, b.Title)
+
+	if addressA != "" && addressB != "" {
+		if addressA == addressB {
+			return sameArea(a, b)
+		}
+		// Different addresses can still be a typo on one source
+		return pinsNear(a, b) && titleSimilarity >= MinTitleSimilarity
+	}
+
+	// At least one address is withheld ("TBA")
+	return sameArea(a, b) && (pinsNear(a, b) || titleSimilarity >= MinTitleSimilarity)
+}
+
+// sameSource reports whether both listings come from one place, where distinct IDs
+// mean distinct sales (two owned listings, or two from the same site)
+func sameSource(a, b *listing.AggregatedListing) bool {
+	return sourceKey(a) == sourceKey(b)
+}
+
+// sourceKey identifies where a listing came from ("" for owned listings)
+func sourceKey(l *listing.AggregatedListing) string {
+	if !l.IsScraped || l.Source == nil {
+		return ""
+	}
+	return strings.ToLower(l.Source.Name)
+}
+
+// datesOverlap compares calendar days, since sources disagree on opening times
+func datesOverlap(a, b *listing.AggregatedListing) bool {
+	aStart, aEnd := saleDays(a)
+	bStart, bEnd := saleDays(b)
+	return aStart <= bEnd && bStart <= aEnd
+}
+
+// saleDays returns the first and last day of a sale as sortable YYYY-MM-DD strings
+func saleDays(l *listing.AggregatedListing) (string, string) {
+	end := l.EndDate
+	if end.Before(l.StartDate) {
+		end = l.StartDate
+	}
+	return day(l.StartDate), day(end)
+}
+
+func day(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// sameArea reports whether the listings share a ZIP code or city
+func sameArea(a, b *listing.AggregatedListing) bool {
+	if a.ZipCode != "" && b.ZipCode != "" {
+		return a.ZipCode == b.ZipCode
+	}
+	return strings.EqualFold(a.City, b.City) && strings.EqualFold(a.State, b.State)
+}
+
+// pinsNear reports whether both listings have rooftop coordinates at the same spot.
+// ZIP and city centroids are shared by many sales, so they never count.
+func pinsNear(a, b *listing.AggregatedListing) bool {
+	if a.GeoPrecision != listing.GeoPrecisionRooftop || b.GeoPrecision != listing.GeoPrecisionRooftop {
+		return false
+	}
+	if a.Latitude == nil || a.Longitude == nil || b.Latitude == nil || b.Longitude == nil {
+		return false
+	}
+	return listing.HaversineMiles(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude) <= MaxPinDistanceMiles
+}
@@ -0,0 +1,213 @@
+package dedup
+
+import (
+	"strings"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// Merge collapses duplicate listings (see IsDuplicate) into one canonical entry each,
+// with every source link attached. Order follows each cluster's first listing.
+func Merge(listings []*listing.AggregatedListing) []*listing.AggregatedListing {
+	c := newClusters(listings)
+	for i := range listings {
+		for j := i + 1; j < len(listings); j++ {
+			if c.find(i) != c.find(j) && IsDuplicate(listings[i], listings[j]) {
+				c.union(i, j)
+			}
+		}
+	}
+
+	var merged []*listing.AggregatedListing
+	for _, members := range c.groups() {
+		if len(members) == 1 {
+			merged = append(merged, listings[members[0]])
+			continue
+		}
+		group := make([]*listing.AggregatedListing, len(members))
+		for k, i := range members {
+			group[k] = listings[i]
+		}
+		merged = append(merged, mergeGroup(group))
+	}
+	return merged
+}
+
+// mergeGroup builds the canonical listing for one cluster of duplicates
+func mergeGroup(group []*listing.AggregatedListing) *listing.AggregatedListing {
+	canonical := group[0]
+	for _, l := range group[1:] {
+		if betterCanonical(l, canonical) {
+			canonical = l
+		}
+	}
+
+	merged := *canonical
+	merged.ImageURLs = append([]string(nil), canonical.ImageURLs...)
+	merged.Sources = nil
+	merged.DuplicateIDs = nil
+	addSource(&merged, canonical)
+
+	for _, l := range group {
+		if l == canonical {
+			continue
+		}
+		merged.DuplicateIDs = append(merged.DuplicateIDs, l.ID)
+		addSource(&merged, l)
+		fillGaps(&merged, l)
+	}
+
+	return &merged
+}
+
+// betterCanonical reports whether a should represent the cluster instead of b.
+// Owned listings always win (the seller is the authority); otherwise the most complete record.
+func betterCanonical(a, b *listing.AggregatedListing) bool {
+	if a.IsScraped != b.IsScraped {
+		return !a.IsScraped
+	}
+	return completeness(a) > completeness(b)
+}
+
+// completeness scores how much of a listing a buyer can use
+func completeness(l *listing.AggregatedListing) int {
+	score := precisionRank(l.GeoPrecision)
+	if NormalizeAddress(l.Address) != "" {
+		score += 3
+	}
+	if len(l.Sessions) > 0 {
+		score++
+	}
+	if l.ThumbnailURL != "" {
+		score++
+	}
+	if l.Description != "" {
+		score++
+	}
+	return score
+}
+
+func precisionRank(precision string) int {
+	switch precision {
+	case listing.GeoPrecisionRooftop:
+		return 3
+	case listing.GeoPrecisionZip:
+		return 2
+	case listing.GeoPrecisionCity:
+		return 1
+	}
+	return 0
+}
+
+// addSource attaches l's source link to merged (owned listings have none)
+func addSource(merged *listing.AggregatedListing, l *listing.AggregatedListing) {
+	if l.Source == nil {
+		return
+	}
+	for _, s := range merged.Sources {
+		if s.URL == l.Source.URL {
+			return
+		}
+	}
+	merged.Sources = append(merged.Sources, *l.Source)
+}
+
+// fillGaps copies what the canonical listing is missing from a duplicate
+func fillGaps(merged *listing.AggregatedListing, l *listing.AggregatedListing) {
+	if NormalizeAddress(merged.Address) == "" && NormalizeAddress(l.Address) != "" {
+		merged.Address = l.Address
+		if l.ZipCode != "" {
+			merged.ZipCode = l.ZipCode
+		}
+	}
+	if precisionRank(l.GeoPrecision) > precisionRank(merged.GeoPrecision) && l.Latitude != nil && l.Longitude != nil {
+		merged.Latitude, merged.Longitude, merged.GeoPrecision = l.Latitude, l.Longitude, l.GeoPrecision
+	}
+	if l.DistanceMiles != nil && (merged.DistanceMiles == nil || *l.DistanceMiles < *merged.DistanceMiles) {
+		merged.DistanceMiles = l.DistanceMiles
+	}
+	if len(merged.Sessions) == 0 {
+		merged.Sessions = l.Sessions
+	}
+	if merged.Description == "" {
+		merged.Description = l.Description
+	}
+	if merged.ThumbnailURL == "" {
+		merged.ThumbnailURL = l.ThumbnailURL
+	}
+	if merged.EventType == "" {
+		merged.EventType = l.EventType
+	}
+	if merged.AddressReleasedAt == nil {
+		merged.AddressReleasedAt = l.AddressReleasedAt
+	}
+	for _, url := range l.ImageURLs {
+		if !containsString(merged.ImageURLs, url) {
+			merged.ImageURLs = append(merged.ImageURLs, url)
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// clusters is a union-find over listing indexes. A union is refused when it would put
+// two listings from the same source in one cluster, so a chain of near matches
+// can't merge distinct sales.
+type clusters struct {
+	parent  []int
+	sources []map[string]bool // Per root
+}
+
+func newClusters(listings []*listing.AggregatedListing) *clusters {
+	c := &clusters{parent: make([]int, len(listings)), sources: make([]map[string]bool, len(listings))}
+	for i, l := range listings {
+		c.parent[i] = i
+		c.sources[i] = map[string]bool{sourceKey(l): true}
+	}
+	return c
+}
+
+func (c *clusters) find(i int) int {
+	for c.parent[i] != i {
+		c.parent[i] = c.parent[c.parent[i]]
+		i = c.parent[i]
+	}
+	return i
+}
+
+func (c *clusters) union(i, j int) {
+	ri, rj := c.find(i), c.find(j)
+	for source := range c.sources[rj] {
+		if c.sources[ri][source] {
+			return
+		}
+	}
+	for source := range c.sources[rj] {
+		c.sources[ri][source] = true
+	}
+	c.parent[rj] = ri
+}
+
+// groups returns member indexes per cluster, ordered by each cluster's first member
+func (c *clusters) groups() [][]int {
+	index := map[int]int{}
+	var groups [][]int
+	for i := range c.parent {
+		root := c.find(i)
+		k, ok := index[root]
+		if !ok {
+			k = len(groups)
+			index[root] = k
+			groups = append(groups, nil)
+		}
+		groups[k] = append(groups[k], i)
+	}
+	return groups
+}
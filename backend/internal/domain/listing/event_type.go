@@ -0,0 +1,53 @@
+package listing
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Sale types (Listing.EventType). Owned listings default to EventEstateSale; scraped
+// listings have one only when their source says which kind of sale it is.
+const (
+	EventEstateSale = "estate_sale"
+	EventMovingSale = "moving_sale"
+	EventGarageSale = "garage_sale" // Garage, yard and rummage sales
+	EventAuction    = "auction"
+)
+
+// categorySeparatorRegex matches what separates words in a category, including the
+// underscores and hyphens of URLs and slugs ("Garage_sale", "moving-sale")
+var categorySeparatorRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// eventTypeRules map words in a source's sale category to a sale type. They're tried
+// in order, so "Estate Auction" is an auction and "Estate Moving Sale" a moving sale.
+var eventTypeRules = []struct {
+	pattern   *regexp.Regexp
+	eventType string
+}{
+	{regexp.MustCompile(`\bauctions?\b`), EventAuction},
+	{regexp.MustCompile(`\b(?:moving|downsizing|relocation)\b`), EventMovingSale},
+	{regexp.MustCompile(`\b(?:garage|yard|rummage|barn)\b`), EventGarageSale},
+	{regexp.MustCompile(`\b(?:estate|tag|liquidation|contents)\b`), EventEstateSale},
+}
+
+// EventTypeFromCategory maps a source's sale category (e.g. "Moving/Downsizing Sale",
+// "Garage/Yard Sale", "Online Auction") to a sale type, or "" if it isn't one
+func EventTypeFromCategory(category string) string {
+	category = categorySeparatorRegex.ReplaceAllString(strings.ToLower(category), " ")
+	for _, rule := range eventTypeRules {
+		if rule.pattern.MatchString(category) {
+			return rule.eventType
+		}
+	}
+	return ""
+}
+
+// EventTypeFromCategories returns the sale type of the first category that has one
+func EventTypeFromCategories(categories []string) string {
+	for _, category := range categories {
+		if eventType := EventTypeFromCategory(category); eventType != "" {
+			return eventType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package listing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEventTypeFromCategory tests mapping source sale categories to sale types
+func TestEventTypeFromCategory(t *testing.T) {
+	tests := []struct {
+		category string
+		expected string
+	}{
+		{"Estate Sale", EventEstateSale},
+		{"ESTATE SALES", EventEstateSale},
+		{"Tag Sale", EventEstateSale},
+		{"Moving/Downsizing Sale", EventMovingSale},
+		{"moving-sale", EventMovingSale},
+		{"Estate Moving Sale", EventMovingSale},
+		{"Garage/Yard Sale", EventGarageSale},
+		{"http://www.productontology.org/id/Garage_sale", EventGarageSale},
+		{"Online Auction", EventAuction},
+		{"Estate Auction", EventAuction},
+		{"Featured", ""},
+		{"Sale", ""},
+		{"Auctioneer news", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EventTypeFromCategory(tt.category))
+		})
+	}
+}
+
+// TestEventTypeFromCategories tests that the first category with a sale type wins
+func TestEventTypeFromCategories(t *testing.T) {
+	assert.Equal(t, EventMovingSale, EventTypeFromCategories([]string{"Featured", "Moving Sale", "Estate Sale"}))
+	assert.Empty(t, EventTypeFromCategories([]string{"Antiques", "Art"}))
+	assert.Empty(t, EventTypeFromCategories(nil))
+}
@@ -0,0 +1,50 @@
+package listing
+
+import "math"
+
+const (
+	// EarthRadiusMiles is the mean radius of the Earth
+	EarthRadiusMiles = 3958.8
+
+	// DefaultRadiusMiles is used when a search gives coordinates but no radius
+	DefaultRadiusMiles = 25.0
+
+	// MaxRadiusMiles caps radius searches
+	MaxRadiusMiles = 100.0
+
+	milesPerDegreeLat = 69.0
+)
+
+// HaversineMiles returns the great-circle distance between two points in miles
+func HaversineMiles(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadiusMiles * math.Asin(math.Sqrt(a))
+}
+
+// BoundingBox returns the lat/lng box that contains every point within radiusMiles
+// of (lat, lng). It's a cheap, index-friendly prefilter for HaversineMiles.
+func BoundingBox(lat, lng, radiusMiles float64) (minLat, maxLat, minLng, maxLng float64) {
+	latDelta := radiusMiles / milesPerDegreeLat
+
+	// Degrees of longitude shrink towards the poles
+	lngDelta := 180.0
+	if cosLat := math.Cos(toRadians(lat)); cosLat > 0.01 {
+		lngDelta = math.Min(radiusMiles/(milesPerDegreeLat*cosLat), 180.0)
+	}
+
+	return lat - latDelta, lat + latDelta, lng - lngDelta, lng + lngDelta
+}
+
+// HasGeo reports whether the filters ask for a radius search
+func (f ListingFilters) HasGeo() bool {
+	return f.Latitude != nil && f.Longitude != nil
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
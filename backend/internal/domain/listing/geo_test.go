@@ -0,0 +1,34 @@
+package listing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHaversineMiles tests great-circle distances between known points
+func TestHaversineMiles(t *testing.T) {
+	// Downtown Portland to downtown Beaverton is roughly 6 miles
+	distance := HaversineMiles(45.5152, -122.6784, 45.4871, -122.8037)
+	assert.InDelta(t, 6.3, distance, 0.3)
+
+	assert.Equal(t, 0.0, HaversineMiles(45.5, -122.6, 45.5, -122.6))
+}
+
+// TestBoundingBox tests that the prefilter box contains points within the radius
+func TestBoundingBox(t *testing.T) {
+	lat, lng, radius := 45.5152, -122.6784, 10.0
+	minLat, maxLat, minLng, maxLng := BoundingBox(lat, lng, radius)
+
+	assert.Less(t, minLat, lat)
+	assert.Greater(t, maxLat, lat)
+	assert.Less(t, minLng, lng)
+	assert.Greater(t, maxLng, lng)
+
+	// Beaverton (~6 miles west) is inside a 10 mile box
+	assert.True(t, -122.8037 > minLng && -122.8037 < maxLng)
+
+	// Box edges are at least radius away from the center
+	assert.GreaterOrEqual(t, HaversineMiles(lat, lng, maxLat, lng), radius*0.99)
+	assert.GreaterOrEqual(t, HaversineMiles(lat, lng, lat, minLng), radius*0.99)
+}
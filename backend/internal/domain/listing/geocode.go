@@ -0,0 +1,87 @@
+package listing
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
+)
+
+// Geocode precision levels, most to least precise
+const (
+	GeoPrecisionRooftop = "rooftop" // Street address match
+	GeoPrecisionZip     = "zip"     // ZIP code centroid
+	GeoPrecisionCity    = "city"    // City centroid
+)
+
+// ErrNotGeocoded is returned when no coordinates could be found for an address
+var ErrNotGeocoded = errors.New("address could not be geocoded")
+
+// GeocodeRequest is the address to look up
+type GeocodeRequest struct {
+	Street  string
+	City    string
+	State   string
+	ZipCode string
+}
+
+// GeocodeResult holds coordinates and how precise they are
+type GeocodeResult struct {
+	Latitude  float64
+	Longitude float64
+	Precision string // GeoPrecisionRooftop, GeoPrecisionZip or GeoPrecisionCity
+}
+
+// Geocoder turns addresses into coordinates
+type Geocoder interface {
+	Geocode(req GeocodeRequest) (*GeocodeResult, error)
+}
+
+// geocodeRequest builds a GeocodeRequest from a listing's address
+func (l *Listing) geocodeRequest() GeocodeRequest {
+	return GeocodeRequest{Street: knownStreet(l.AddressLine1), City: l.City, State: l.State, ZipCode: l.ZipCode}
+}
+
+// geocodeRequest builds a GeocodeRequest from a scraped listing's address
+func (s *ScrapedListing) geocodeRequest() GeocodeRequest {
+	return GeocodeRequest{Street: knownStreet(s.Address), City: s.City, State: s.State, ZipCode: s.ZipCode}
+}
+
+// knownStreet drops "TBA" placeholders (addresses withheld until the sale opens)
+func knownStreet(street string) string {
+	if address.IsWithheld(street) {
+		return ""
+	}
+	return strings.TrimSpace(street)
+}
+
+// HasCoordinates reports whether the scraped listing has been geocoded (0,0 means unset)
+func (s *ScrapedListing) HasCoordinates() bool {
+	return s.Latitude != 0 || s.Longitude != 0
+}
+
+// GeocodeScraped fills in coordinates for a scraped listing that has none.
+// A failed lookup leaves the listing unchanged.
+func GeocodeScraped(g Geocoder, s *ScrapedListing) error {
+	if g == nil || s.HasCoordinates() {
+		return nil
+	}
+
+	result, err := g.Geocode(s.geocodeRequest())
+	if err != nil {
+		return err
+	}
+
+	s.Latitude = result.Latitude
+	s.Longitude = result.Longitude
+	s.GeoPrecision = result.Precision
+	return nil
+}
+
+// coordinates converts scraped float coordinates to nullable ones (0,0 means not geocoded)
+func coordinates(lat, lng float64) (*float64, *float64) {
+	if lat == 0 && lng == 0 {
+		return nil, nil
+	}
+	return &lat, &lng
+}
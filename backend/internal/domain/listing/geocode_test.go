@@ -0,0 +1,83 @@
+package listing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGeocoder struct {
+	requests []GeocodeRequest
+	result   *GeocodeResult
+}
+
+func (g *fakeGeocoder) Geocode(req GeocodeRequest) (*GeocodeResult, error) {
+	g.requests = append(g.requests, req)
+	if g.result == nil {
+		return nil, ErrNotGeocoded
+	}
+	return g.result, nil
+}
+
+// TestUngeocodedScrapedListing tests that 0,0 is not emitted as real coordinates
+func TestUngeocodedScrapedListing(t *testing.T) {
+	scraped := ScrapedListing{ExternalID: "no-coords", City: "Portland", State: "OR"}
+
+	aggregated := scraped.ToAggregatedSale()
+	assert.Nil(t, aggregated.Latitude)
+	assert.Nil(t, aggregated.Longitude)
+
+	sale := scraped.ToSale()
+	assert.Nil(t, sale.Latitude)
+	assert.Nil(t, sale.Longitude)
+	assert.Nil(t, sale.GeoPrecision)
+}
+
+// TestGeocodeScraped tests filling coordinates on scraped listings
+func TestGeocodeScraped(t *testing.T) {
+	g := &fakeGeocoder{result: &GeocodeResult{Latitude: 45.49, Longitude: -122.80, Precision: GeoPrecisionZip}}
+
+	scraped := ScrapedListing{Address: "TBA", City: "Beaverton", State: "OR", ZipCode: "97005"}
+	require.NoError(t, GeocodeScraped(g, &scraped))
+
+	assert.Equal(t, 45.49, scraped.Latitude)
+	assert.Equal(t, GeoPrecisionZip, scraped.GeoPrecision)
+	require.Len(t, g.requests, 1)
+	assert.Equal(t, "", g.requests[0].Street, "TBA is not a street address")
+
+	// Already geocoded listings are left alone
+	require.NoError(t, GeocodeScraped(g, &scraped))
+	assert.Len(t, g.requests, 1)
+
+	sale := scraped.ToSale()
+	require.NotNil(t, sale.GeoPrecision)
+	assert.Equal(t, GeoPrecisionZip, *sale.GeoPrecision)
+}
+
+// TestServiceGeocode tests geocoding on listing create/update
+func TestServiceGeocode(t *testing.T) {
+	g := &fakeGeocoder{result: &GeocodeResult{Latitude: 45.52, Longitude: -122.68, Precision: GeoPrecisionRooftop}}
+	service := &Service{geocoder: g}
+
+	l := &Listing{AddressLine1: "123 Main St", City: "Portland", State: "OR", ZipCode: "97204"}
+	service.geocode(l)
+	require.NotNil(t, l.Latitude)
+	assert.Equal(t, 45.52, *l.Latitude)
+	assert.Equal(t, GeoPrecisionRooftop, *l.GeoPrecision)
+	assert.Equal(t, "123 Main St", g.requests[0].Street)
+
+	// A seller-placed pin (coordinates without a precision) is kept
+	lat, lng := 45.0, -122.0
+	pinned := &Listing{City: "Portland", State: "OR", Latitude: &lat, Longitude: &lng}
+	service.geocode(pinned)
+	assert.Equal(t, 45.0, *pinned.Latitude)
+	assert.Equal(t, GeoPrecisionRooftop, *pinned.GeoPrecision)
+	assert.Len(t, g.requests, 1)
+
+	// A failed lookup clears stale coordinates instead of failing the save
+	g.result = nil
+	service.geocode(l)
+	assert.Nil(t, l.Latitude)
+	assert.Nil(t, l.GeoPrecision)
+}
@@ -17,6 +17,11 @@ type Listing struct {
 	ExternalSource *string    `json:"external_source,omitempty"`  // e.g. "EstateSale-Finder.com"
 	ExternalURL    *string    `json:"external_url,omitempty"`     // Deep link to original
 	LastScrapedAt  *time.Time `json:"last_scraped_at,omitempty"` // When we last scraped
+	ScrapeStatus   string     `json:"scrape_status,omitempty"`   // 'active', 'missing', 'tombstoned' (see MarkMissing)
+	ScrapeCity     string     `json:"-"`                         // Location whose scrape last saw this listing
+	ScrapeState    string     `json:"-"`
+
+	AddressReleasedAt *time.Time `json:"address_released_at,omitempty"` // When a withheld ("TBA") address was published
 
 	// Shared fields (all listings have these)
 	Title       string `json:"title"`
@@ -30,14 +35,20 @@ type Listing struct {
 	ZipCode      string   `json:"zip_code"`
 	Latitude     *float64 `json:"latitude,omitempty"`
 	Longitude    *float64 `json:"longitude,omitempty"`
+	GeoPrecision *string  `json:"geo_precision,omitempty"` // 'rooftop', 'zip', 'city' (NULL if not geocoded)
+
+	// Set by radius searches only
+	DistanceMiles *float64 `json:"distance_miles,omitempty"`
 
 	// Sale dates/times
 	StartDate time.Time `json:"start_date"`
 	EndDate   time.Time `json:"end_date"`
 	EventHours *string   `json:"event_hours,omitempty"` // e.g., "Fri 9am-5pm, Sat 9am-3pm"
+	Sessions   []Session `json:"sessions,omitempty"`    // Structured open periods (parsed from EventHours if not given)
+
+	EventType string `json:"event_type,omitempty"` // See EventEstateSale etc. (external: empty if the source doesn't say)
 
 	// Owned-only fields (NULL for external)
-	EventType      string   `json:"event_type,omitempty"`      // 'estate_sale', 'auction', 'moving_sale'
 	Status        string   `json:"status,omitempty"`         // 'draft', 'published', 'completed', 'cancelled'
 	ListingTier   string   `json:"listing_tier,omitempty"`   // 'basic', 'featured', 'premium'
 	PaymentStatus string   `json:"payment_status,omitempty"` // 'unpaid', 'paid', 'refunded'
@@ -49,6 +60,10 @@ type Listing struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// Company running the sale (scraped provider, or the seller's company for owned listings)
+	CompanyID *int            `json:"company_id,omitempty"`
+	Company   *CompanyContact `json:"company,omitempty"` // Loaded with CompanyID
+
 	// Related data (loaded separately)
 	Images []ListingImage `json:"images,omitempty"`
 }
@@ -63,6 +78,33 @@ func (l *Listing) IsOwned() bool {
 	return l.ListingType == "owned"
 }
 
+// UpsertResult reports what UpsertExternalSale did
+type UpsertResult string
+
+const (
+	UpsertInserted  UpsertResult = "inserted"  // New listing
+	UpsertUpdated   UpsertResult = "updated"   // Content changed
+	UpsertUnchanged UpsertResult = "unchanged" // Only last_scraped_at was bumped
+)
+
+// Scrape statuses for external listings
+const (
+	ScrapeStatusActive     = "active"     // Seen in the latest scrape of its source
+	ScrapeStatusMissing    = "missing"    // Not seen in the latest scrape(s)
+	ScrapeStatusTombstoned = "tombstoned" // Missing for too many scrapes in a row; hidden from public queries
+)
+
+// MissingResult reports what MarkMissing did
+type MissingResult struct {
+	Missing    int // Newly or still missing
+	Tombstoned int // Crossed the tombstone threshold in this call
+}
+
+// SameContent reports whether two listings have the same scraped content (see DiffListings)
+func (l *Listing) SameContent(other *Listing) bool {
+	return len(DiffListings(l, other)) == 0
+}
+
 // ListingImage represents an image associated with a listing
 type ListingImage struct {
 	ID           int       `json:"id"`
@@ -79,13 +121,24 @@ type ListingFilters struct {
 	City      string
 	State     string
 	ZipCode   string
-	EventType  string // 'estate_sale', 'auction', 'moving_sale'
+	EventType  string // 'estate_sale', 'moving_sale', 'garage_sale', 'auction' (untyped scraped sales match any)
 	Status    string // Only 'published' for public, all for sellers
 	StartDate *time.Time
 	EndDate   *time.Time
 	Featured  *bool
 	Limit     int
 	Offset    int
+
+	// Radius search (see HasGeo)
+	Latitude        *float64
+	Longitude       *float64
+	RadiusMiles     float64
+	SortBy          string // '' (featured, start date) or 'distance'
+	IncludeExternal bool   // Include scraped listings (Status then applies to owned listings only)
+
+	ExcludeEnded bool // Hide sales whose end date has passed (public queries)
+
+	CompanyID *int // Only sales run by this company
 }
 
 // ToScrapedListing converts a Listing (external) to ScrapedListing for display
@@ -123,6 +176,24 @@ func (l *Listing) ToScrapedListing() ScrapedListing {
 	if l.Longitude != nil {
 		scraped.Longitude = *l.Longitude
 	}
+	if l.GeoPrecision != nil {
+		scraped.GeoPrecision = *l.GeoPrecision
+	}
+	if l.EventHours != nil {
+		scraped.EventHours = *l.EventHours
+	}
+	scraped.Sessions = l.Sessions
+
+	// Persisted detail-page photos (see Repository.ReplaceExternalImages)
+	for _, img := range l.Images {
+		if img.IsPrimary && scraped.ThumbnailURL == "" {
+			scraped.ThumbnailURL = img.ImageURL
+		}
+		scraped.ImageURLs = append(scraped.ImageURLs, img.ImageURL)
+	}
+	scraped.AddressReleasedAt = l.AddressReleasedAt
+	scraped.Company = l.Company
+	scraped.EventType = l.EventType
 
 	return scraped
 }
@@ -1,24 +1,45 @@
 package listing
 
-// Repository defines the interface for listing data operations
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when a listing lookup matches no row
+var ErrNotFound = errors.New("listing not found")
+
+// Repository defines the interface for listing data operations.
 type Repository interface {
-	// Listing CRUD
-	Create(listing *Listing) error
-	GetByID(id int) (*Listing, error)
-	GetAll(filters ListingFilters) ([]Listing, error)
-	GetBySellerID(sellerID int) ([]Listing, error)
-	Update(listing *Listing) error
-	Delete(id int) error
-	IncrementViewCount(id int) error
+	// Listing CRUD (Create and Update write the listing's Sessions in the same transaction)
+	Create(ctx context.Context, listing *Listing) error
+	GetByID(ctx context.Context, id int) (*Listing, error)
+	GetAll(ctx context.Context, filters ListingFilters) ([]Listing, error)
+	GetWithinRadius(ctx context.Context, filters ListingFilters) ([]Listing, error)
+	GetBySellerID(ctx context.Context, sellerID int) ([]Listing, error)
+	Update(ctx context.Context, listing *Listing) error
+	Delete(ctx context.Context, id int) error
+	IncrementViewCount(ctx context.Context, id int) error
 
 	// Image operations
-	AddImage(image *ListingImage) error
-	GetImagesByListingID(listingID int) ([]ListingImage, error)
-	DeleteImage(imageID int) error
-	SetPrimaryImage(imageID int, listingID int) error
+	AddImage(ctx context.Context, image *ListingImage) error
+	GetImagesByListingID(ctx context.Context, listingID int) ([]ListingImage, error)
+	DeleteImage(ctx context.Context, imageID int) error
+	SetPrimaryImage(ctx context.Context, imageID int, listingID int) error
+	ReplaceExternalImages(ctx context.Context, listingID int, imageURLs []string) error
+
+	// Session operations (structured event hours)
+	ReplaceSessions(ctx context.Context, listingID int, sessions []Session) error
 
 	// External listing operations
-	UpsertExternalSale(listing *Listing) error
-	GetExternalSalesByLocation(city, state string) ([]Listing, error)
-	GetLastScrapedTime(city, state string) (*Listing, error)
+	UpsertExternalSale(ctx context.Context, listing *Listing) (UpsertResult, error)
+	GetExternalSalesByLocation(ctx context.Context, city, state string) ([]Listing, error)
+	GetLastScrapedTime(ctx context.Context, city, state string) (*Listing, error)
+	GetIDByExternalID(ctx context.Context, externalID string) (int, error)
+
+	// GetHistory returns the revisions UpsertExternalSale recorded for a listing
+	GetHistory(ctx context.Context, listingID int) (*ListingHistory, error)
+
+	// MarkMissing flags a source's listings for a scrape location that weren't in seenExternalIDs.
+	// Listings missing tombstoneAfter scrapes in a row are tombstoned.
+	MarkMissing(ctx context.Context, source, city, state string, seenExternalIDs []string, tombstoneAfter int) (MissingResult, error)
 }
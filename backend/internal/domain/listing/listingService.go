@@ -1,13 +1,21 @@
 package listing
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
 )
 
 // Service handles business logic for sales
 type Service struct {
-	repo Repository
+	repo     Repository
+	geocoder Geocoder // Optional, see SetGeocoder
 }
 
 // NewService creates a new listing service
@@ -15,8 +23,15 @@ func NewService(repo Repository) *Service {
 	return &Service{repo: repo}
 }
 
+// SetGeocoder sets the geocoder used to fill coordinates on create/update
+func (s *Service) SetGeocoder(g Geocoder) {
+	s.geocoder = g
+}
+
 // CreateListing creates a new listing listing
-func (s *Service) CreateListing(l *Listing) error {
+func (s *Service) CreateListing(ctx context.Context, l *Listing) error {
+	normalizeAddress(l)
+
 	// Validate required fields
 	if l.Title == "" {
 		return fmt.Errorf("title is required")
@@ -30,13 +45,17 @@ func (s *Service) CreateListing(l *Listing) error {
 	if l.EndDate.Before(l.StartDate) {
 		return fmt.Errorf("end date must be after start date")
 	}
+	if err := s.resolveSessions(l); err != nil {
+		return err
+	}
+	s.geocode(l)
 
 	// Set defaults
 	if l.Status == "" {
 		l.Status = "draft"
 	}
 	if l.EventType == "" {
-		l.EventType = "estate_sale"
+		l.EventType = EventEstateSale
 	}
 	if l.ListingTier == "" {
 		l.ListingTier = "basic"
@@ -48,30 +67,44 @@ func (s *Service) CreateListing(l *Listing) error {
 	l.CreatedAt = time.Now()
 	l.UpdatedAt = time.Now()
 
-	return s.repo.Create(l)
+	return s.repo.Create(ctx, l)
 }
 
 // GetListingByID retrieves a listing by ID and increments view count
-func (s *Service) GetListingByID(id int) (*Listing, error) {
-	l, err := s.repo.GetByID(id)
+func (s *Service) GetListingByID(ctx context.Context, id int) (*Listing, error) {
+	l, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// Load images
-	images, err := s.repo.GetImagesByListingID(id)
+	images, err := s.repo.GetImagesByListingID(ctx, id)
 	if err == nil {
 		l.Images = images
 	}
 
-	// Increment view count asynchronously (don't block on errors)
-	go s.repo.IncrementViewCount(id)
+	// Increment view count asynchronously (don't block on errors; it outlives the request)
+	go s.repo.IncrementViewCount(context.WithoutCancel(ctx), id)
 
 	return l, nil
 }
 
+// GetListingHistory returns a listing's change timeline. ref is a listing ID or, for scraped
+// listings, the external ID aggregated results use.
+func (s *Service) GetListingHistory(ctx context.Context, ref string) (*ListingHistory, error) {
+	id, err := strconv.Atoi(ref)
+	if err != nil {
+		id, err = s.repo.GetIDByExternalID(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetHistory(ctx, id)
+}
+
 // GetAllListings retrieves sales with optional filters
-func (s *Service) GetAllListings(filters ListingFilters) ([]Listing, error) {
+func (s *Service) GetAllListings(ctx context.Context, filters ListingFilters) ([]Listing, error) {
 	// Set default pagination
 	if filters.Limit == 0 {
 		filters.Limit = 20
@@ -80,14 +113,26 @@ func (s *Service) GetAllListings(filters ListingFilters) ([]Listing, error) {
 		filters.Limit = 100
 	}
 
-	listings, err := s.repo.GetAll(filters)
+	var listings []Listing
+	var err error
+	if filters.HasGeo() {
+		if filters.RadiusMiles <= 0 {
+			filters.RadiusMiles = DefaultRadiusMiles
+		}
+		if filters.RadiusMiles > MaxRadiusMiles {
+			filters.RadiusMiles = MaxRadiusMiles
+		}
+		listings, err = s.repo.GetWithinRadius(ctx, filters)
+	} else {
+		listings, err = s.repo.GetAll(ctx, filters)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// Load images for each listing
 	for i := range listings {
-		images, err := s.repo.GetImagesByListingID(listings[i].ID)
+		images, err := s.repo.GetImagesByListingID(ctx, listings[i].ID)
 		if err == nil {
 			listings[i].Images = images
 		}
@@ -97,15 +142,15 @@ func (s *Service) GetAllListings(filters ListingFilters) ([]Listing, error) {
 }
 
 // GetSellerListings retrieves all sales for a specific seller
-func (s *Service) GetSellerListings(sellerID int) ([]Listing, error) {
-	listings, err := s.repo.GetBySellerID(sellerID)
+func (s *Service) GetSellerListings(ctx context.Context, sellerID int) ([]Listing, error) {
+	listings, err := s.repo.GetBySellerID(ctx, sellerID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Load images for each listing
 	for i := range listings {
-		images, err := s.repo.GetImagesByListingID(listings[i].ID)
+		images, err := s.repo.GetImagesByListingID(ctx, listings[i].ID)
 		if err == nil {
 			listings[i].Images = images
 		}
@@ -115,29 +160,110 @@ func (s *Service) GetSellerListings(sellerID int) ([]Listing, error) {
 }
 
 // UpdateListing updates an existing sale
-func (s *Service) UpdateListing(l *Listing) error {
+func (s *Service) UpdateListing(ctx context.Context, l *Listing) error {
 	// Validate
 	if l.ID == 0 {
 		return fmt.Errorf("listing ID is required")
 	}
+	normalizeAddress(l)
 	if l.Title == "" {
 		return fmt.Errorf("title is required")
 	}
 	if l.City == "" || l.State == "" {
 		return fmt.Errorf("city and state are required")
 	}
+	if err := s.resolveSessions(l); err != nil {
+		return err
+	}
+	s.geocode(l)
 
 	l.UpdatedAt = time.Now()
-	return s.repo.Update(l)
+	return s.repo.Update(ctx, l)
+}
+
+// ErrInvalidSessions is returned (wrapped) for sessions the seller sent that can't be saved
+var ErrInvalidSessions = errors.New("invalid sessions")
+
+// resolveSessions fills Sessions from the free-text EventHours when the seller
+// didn't send structured sessions, and checks sessions fall within the sale dates.
+// Hours that can't be read (e.g. "call for times") are kept as text with no sessions.
+func (s *Service) resolveSessions(l *Listing) error {
+	if len(l.Sessions) == 0 && l.EventHours != nil && *l.EventHours != "" {
+		if sessions, err := ParseSessions(*l.EventHours, l.StartDate, l.EndDate); err == nil {
+			l.Sessions = sessions
+		}
+	}
+
+	for _, session := range l.Sessions {
+		if !session.End.After(session.Start) {
+			return fmt.Errorf("%w: session must end after it starts", ErrInvalidSessions)
+		}
+		if session.Start.Before(dayOf(l.StartDate)) || session.End.After(dayOf(l.EndDate).AddDate(0, 0, 1)) {
+			return fmt.Errorf("%w: sessions must fall within the sale's start and end dates", ErrInvalidSessions)
+		}
+	}
+
+	return nil
+}
+
+// normalizeAddress splits a full address typed into AddressLine1
+// ("1234 SE Main St, Apt 4, Tigard, OR 97223") and fills the empty fields from it
+func normalizeAddress(l *Listing) {
+	if parsed, ok := address.Parse(l.AddressLine1); ok && parsed.Street != "" {
+		l.AddressLine1 = parsed.Street
+		if parsed.Unit != "" && (l.AddressLine2 == nil || strings.TrimSpace(*l.AddressLine2) == "") {
+			l.AddressLine2 = &parsed.Unit
+		}
+		if l.City == "" {
+			l.City = parsed.City
+		}
+		if l.State == "" {
+			l.State = parsed.State
+		}
+		if l.ZipCode == "" {
+			l.ZipCode = parsed.ZipCode
+		}
+	}
+
+	l.City = strings.TrimSpace(l.City)
+	if code := address.StateCode(l.State); code != "" {
+		l.State = code
+	}
+	l.ZipCode = strings.TrimSpace(l.ZipCode)
+}
+
+// geocode fills the listing's coordinates from its address.
+// Coordinates sent without a precision are a seller-placed pin and are kept as rooftop.
+// A failed lookup doesn't block saving; the listing just won't show up in radius searches.
+func (s *Service) geocode(l *Listing) {
+	if l.Latitude != nil && l.Longitude != nil && l.GeoPrecision == nil {
+		precision := GeoPrecisionRooftop
+		l.GeoPrecision = &precision
+		return
+	}
+	if s.geocoder == nil {
+		return
+	}
+
+	result, err := s.geocoder.Geocode(l.geocodeRequest())
+	if err != nil {
+		log.Printf("Warning: failed to geocode listing %q (%s, %s): %v", l.Title, l.City, l.State, err)
+		l.Latitude, l.Longitude, l.GeoPrecision = nil, nil, nil
+		return
+	}
+
+	l.Latitude = &result.Latitude
+	l.Longitude = &result.Longitude
+	l.GeoPrecision = &result.Precision
 }
 
 // DeleteListing deletes a sale
-func (s *Service) DeleteListing(id int) error {
-	return s.repo.Delete(id)
+func (s *Service) DeleteListing(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
 }
 
 // AddListingImage adds an image to a listing
-func (s *Service) AddListingImage(image *ListingImage) error {
+func (s *Service) AddListingImage(ctx context.Context, image *ListingImage) error {
 	if image.ListingID == 0 {
 		return fmt.Errorf("listing ID is required")
 	}
@@ -146,15 +272,15 @@ func (s *Service) AddListingImage(image *ListingImage) error {
 	}
 
 	image.UploadedAt = time.Now()
-	return s.repo.AddImage(image)
+	return s.repo.AddImage(ctx, image)
 }
 
 // DeleteListingImage deletes an image
-func (s *Service) DeleteListingImage(imageID int) error {
-	return s.repo.DeleteImage(imageID)
+func (s *Service) DeleteListingImage(ctx context.Context, imageID int) error {
+	return s.repo.DeleteImage(ctx, imageID)
 }
 
 // SetPrimaryImage sets an image as the primary image for a sale
-func (s *Service) SetPrimaryImage(imageID int, listingID int) error {
-	return s.repo.SetPrimaryImage(imageID, listingID)
+func (s *Service) SetPrimaryImage(ctx context.Context, imageID int, listingID int) error {
+	return s.repo.SetPrimaryImage(ctx, imageID, listingID)
 }
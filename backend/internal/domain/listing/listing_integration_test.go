@@ -1,6 +1,7 @@
 package listing_test
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"testing"
@@ -18,6 +19,7 @@ import (
 // ListingIntegrationTestSuite tests the listing domain with real database
 type ListingIntegrationTestSuite struct {
 	suite.Suite
+	ctx     context.Context
 	db      *sql.DB
 	repo    *postgres.ListingRepository
 	service *listing.Service
@@ -25,6 +27,8 @@ type ListingIntegrationTestSuite struct {
 
 // SetupSuite runs once before all tests
 func (suite *ListingIntegrationTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
 	// Get database connection from environment
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -95,7 +99,7 @@ func (suite *ListingIntegrationTestSuite) TestListingCRUD() {
 	}
 
 	// Test CREATE
-	err := suite.service.CreateListing(&testListing)
+	err := suite.service.CreateListing(suite.ctx, &testListing)
 	require.NoError(suite.T(), err, "CreateListing should succeed")
 	assert.Greater(suite.T(), testListing.ID, 0, "Listing should have ID assigned")
 	suite.T().Logf("✓ Created listing with ID: %d", testListing.ID)
@@ -103,7 +107,7 @@ func (suite *ListingIntegrationTestSuite) TestListingCRUD() {
 	createdID := testListing.ID
 
 	// Test READ (GetByID)
-	retrieved, err := suite.service.GetListingByID(createdID)
+	retrieved, err := suite.service.GetListingByID(suite.ctx, createdID)
 	require.NoError(suite.T(), err, "GetListingByID should succeed")
 	assert.Equal(suite.T(), testListing.Title, retrieved.Title, "Title should match")
 	assert.Equal(suite.T(), testListing.City, retrieved.City, "City should match")
@@ -115,24 +119,24 @@ func (suite *ListingIntegrationTestSuite) TestListingCRUD() {
 	retrieved.Title = "Test: UPDATED - Estate Sale"
 	retrieved.Status = "active"
 	retrieved.Description = "Updated description with more details"
-	err = suite.service.UpdateListing(retrieved)
+	err = suite.service.UpdateListing(suite.ctx, retrieved)
 	require.NoError(suite.T(), err, "UpdateListing should succeed")
 	suite.T().Logf("✓ Updated listing")
 
 	// Verify update
-	updated, err := suite.service.GetListingByID(createdID)
+	updated, err := suite.service.GetListingByID(suite.ctx, createdID)
 	require.NoError(suite.T(), err, "GetListingByID after update should succeed")
 	assert.Equal(suite.T(), "Test: UPDATED - Estate Sale", updated.Title, "Title should be updated")
 	assert.Equal(suite.T(), "active", updated.Status, "Status should be updated")
 	suite.T().Logf("✓ Verified update: %s", updated.Title)
 
 	// Test DELETE
-	err = suite.service.DeleteListing(createdID)
+	err = suite.service.DeleteListing(suite.ctx, createdID)
 	require.NoError(suite.T(), err, "DeleteListing should succeed")
 	suite.T().Logf("✓ Deleted listing")
 
 	// Verify deletion
-	_, err = suite.service.GetListingByID(createdID)
+	_, err = suite.service.GetListingByID(suite.ctx, createdID)
 	assert.Error(suite.T(), err, "GetListingByID after delete should fail")
 	suite.T().Logf("✓ Verified deletion")
 }
@@ -159,7 +163,7 @@ func (suite *ListingIntegrationTestSuite) TestListingWithImages() {
 		Status:       "active",
 	}
 
-	err := suite.service.CreateListing(&testListing)
+	err := suite.service.CreateListing(suite.ctx, &testListing)
 	require.NoError(suite.T(), err)
 	suite.T().Logf("✓ Created listing: %s (ID: %d)", testListing.Title, testListing.ID)
 
@@ -170,7 +174,7 @@ func (suite *ListingIntegrationTestSuite) TestListingWithImages() {
 		IsPrimary:    false,
 		DisplayOrder: 1,
 	}
-	err = suite.service.AddListingImage(&image1)
+	err = suite.service.AddListingImage(suite.ctx, &image1)
 	require.NoError(suite.T(), err, "AddListingImage should succeed")
 	suite.T().Logf("✓ Added image 1")
 
@@ -180,17 +184,17 @@ func (suite *ListingIntegrationTestSuite) TestListingWithImages() {
 		IsPrimary:    false,
 		DisplayOrder: 2,
 	}
-	err = suite.service.AddListingImage(&image2)
+	err = suite.service.AddListingImage(suite.ctx, &image2)
 	require.NoError(suite.T(), err)
 	suite.T().Logf("✓ Added image 2")
 
 	// Set primary image
-	err = suite.service.SetPrimaryImage(image1.ID, testListing.ID)
+	err = suite.service.SetPrimaryImage(suite.ctx, image1.ID, testListing.ID)
 	require.NoError(suite.T(), err, "SetPrimaryImage should succeed")
 	suite.T().Logf("✓ Set image 1 as primary")
 
 	// Retrieve listing with images
-	retrieved, err := suite.service.GetListingByID(testListing.ID)
+	retrieved, err := suite.service.GetListingByID(suite.ctx, testListing.ID)
 	require.NoError(suite.T(), err)
 	assert.Len(suite.T(), retrieved.Images, 2, "Should have 2 images")
 
@@ -206,18 +210,18 @@ func (suite *ListingIntegrationTestSuite) TestListingWithImages() {
 	suite.T().Logf("✓ Verified images (primary: %s)", image1.ImageURL)
 
 	// Delete image
-	err = suite.service.DeleteListingImage(image2.ID)
+	err = suite.service.DeleteListingImage(suite.ctx, image2.ID)
 	require.NoError(suite.T(), err, "DeleteListingImage should succeed")
 	suite.T().Logf("✓ Deleted image 2")
 
 	// Verify deletion
-	retrieved, err = suite.service.GetListingByID(testListing.ID)
+	retrieved, err = suite.service.GetListingByID(suite.ctx, testListing.ID)
 	require.NoError(suite.T(), err)
 	assert.Len(suite.T(), retrieved.Images, 1, "Should have 1 image after deletion")
 	suite.T().Logf("✓ Verified image deletion")
 
 	// Cleanup
-	suite.service.DeleteListing(testListing.ID)
+	suite.service.DeleteListing(suite.ctx, testListing.ID)
 }
 
 // TestListingFilters tests filtering and pagination
@@ -274,7 +278,7 @@ func (suite *ListingIntegrationTestSuite) TestListingFilters() {
 	}
 
 	for i := range listings {
-		err := suite.service.CreateListing(&listings[i])
+		err := suite.service.CreateListing(suite.ctx, &listings[i])
 		require.NoError(suite.T(), err)
 		suite.T().Logf("✓ Created: %s", listings[i].Title)
 	}
@@ -284,7 +288,7 @@ func (suite *ListingIntegrationTestSuite) TestListingFilters() {
 		City:  "Portland",
 		Limit: 10,
 	}
-	results, err := suite.service.GetAllListings(filters)
+	results, err := suite.service.GetAllListings(suite.ctx, filters)
 	require.NoError(suite.T(), err)
 	assert.GreaterOrEqual(suite.T(), len(results), 2, "Should find at least 2 Portland listings")
 	for _, l := range results {
@@ -299,7 +303,7 @@ func (suite *ListingIntegrationTestSuite) TestListingFilters() {
 		State: "OR",
 		Limit: 10,
 	}
-	results, err = suite.service.GetAllListings(filters)
+	results, err = suite.service.GetAllListings(suite.ctx, filters)
 	require.NoError(suite.T(), err)
 	assert.GreaterOrEqual(suite.T(), len(results), 2, "Should find at least 2 OR listings")
 	suite.T().Logf("✓ State filter: Found %d Oregon listings", len(results))
@@ -309,7 +313,7 @@ func (suite *ListingIntegrationTestSuite) TestListingFilters() {
 		EventType: "estate_sale",
 		Limit:    10,
 	}
-	results, err = suite.service.GetAllListings(filters)
+	results, err = suite.service.GetAllListings(suite.ctx, filters)
 	require.NoError(suite.T(), err)
 	assert.GreaterOrEqual(suite.T(), len(results), 2, "Should find at least 2 estate sales")
 	suite.T().Logf("✓ Sale type filter: Found %d estate sales", len(results))
@@ -320,7 +324,7 @@ func (suite *ListingIntegrationTestSuite) TestListingFilters() {
 		Featured: &featured,
 		Limit:    10,
 	}
-	results, err = suite.service.GetAllListings(filters)
+	results, err = suite.service.GetAllListings(suite.ctx, filters)
 	require.NoError(suite.T(), err)
 	assert.GreaterOrEqual(suite.T(), len(results), 1, "Should find at least 1 featured listing")
 	suite.T().Logf("✓ Featured filter: Found %d featured listings", len(results))
@@ -330,20 +334,20 @@ func (suite *ListingIntegrationTestSuite) TestListingFilters() {
 		Limit:  1,
 		Offset: 0,
 	}
-	page1, err := suite.service.GetAllListings(filters)
+	page1, err := suite.service.GetAllListings(suite.ctx, filters)
 	require.NoError(suite.T(), err)
 	assert.LessOrEqual(suite.T(), len(page1), 1, "Should return at most 1 result")
 	suite.T().Logf("✓ Pagination: Page 1 has %d results", len(page1))
 
 	filters.Offset = 1
-	page2, err := suite.service.GetAllListings(filters)
+	page2, err := suite.service.GetAllListings(suite.ctx, filters)
 	require.NoError(suite.T(), err)
 	assert.LessOrEqual(suite.T(), len(page2), 1, "Should return at most 1 result")
 	suite.T().Logf("✓ Pagination: Page 2 has %d results", len(page2))
 
 	// Cleanup
 	for _, l := range listings {
-		suite.service.DeleteListing(l.ID)
+		suite.service.DeleteListing(suite.ctx, l.ID)
 	}
 }
 
@@ -384,13 +388,13 @@ func (suite *ListingIntegrationTestSuite) TestExternalListingConversion() {
 	suite.T().Logf("✓ Converted ScrapedListing to Listing")
 
 	// Persist to database
-	err := suite.repo.UpsertExternalSale(&convertedListing)
+	_, err := suite.repo.UpsertExternalSale(suite.ctx, &convertedListing)
 	require.NoError(suite.T(), err, "UpsertExternalSale should succeed")
 	assert.Greater(suite.T(), convertedListing.ID, 0, "Should have ID assigned")
 	suite.T().Logf("✓ Persisted external listing (ID: %d)", convertedListing.ID)
 
 	// Convert Listing back to ScrapedListing
-	retrieved, err := suite.repo.GetByID(convertedListing.ID)
+	retrieved, err := suite.repo.GetByID(suite.ctx, convertedListing.ID)
 	require.NoError(suite.T(), err)
 
 	convertedBack := retrieved.ToScrapedListing()
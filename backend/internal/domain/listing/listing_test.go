@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestListingCreation tests basic Listing struct creation
@@ -286,3 +287,155 @@ func TestAggregatedListing(t *testing.T) {
 	assert.Len(t, aggregatedScraped.ImageURLs, 2)
 	assert.Equal(t, "https://example.com/thumb.jpg", aggregatedScraped.ThumbnailURL)
 }
+
+// TestListingSameContent tests change detection at database precision
+func TestListingSameContent(t *testing.T) {
+	now := time.Now()
+	lat := 45.123456789
+	a := Listing{Title: "Sale", City: "Portland", State: "OR", StartDate: now, EndDate: now, Latitude: &lat}
+
+	// Round-tripped through the database: microseconds, 8 decimal coordinates
+	storedLat := 45.12345679
+	b := a
+	b.StartDate = now.Truncate(time.Microsecond)
+	b.EndDate = now.Truncate(time.Microsecond)
+	b.Latitude = &storedLat
+	assert.True(t, a.SameContent(&b))
+
+	b.Title = "Sale (cancelled)"
+	assert.False(t, a.SameContent(&b))
+
+	c := a
+	c.Latitude = nil
+	assert.False(t, a.SameContent(&c))
+}
+
+// TestDiffListings tests field-level diffs between scrapes
+func TestDiffListings(t *testing.T) {
+	now := time.Now()
+	hours := "Fri 9am-3pm"
+	old := Listing{Title: "Sale", AddressLine1: "TBA", City: "Portland", State: "OR", StartDate: now, EndDate: now}
+
+	updated := old
+	updated.AddressLine1 = "123 SE Main St"
+	updated.EventHours = &hours
+	updated.EndDate = now.Add(24 * time.Hour)
+
+	changes := DiffListings(&old, &updated)
+	require.Len(t, changes, 3)
+	assert.Equal(t, FieldChange{Field: "address_line1", Old: "TBA", New: "123 SE Main St"}, changes[0])
+	assert.Equal(t, "end_date", changes[1].Field)
+	assert.Equal(t, FieldChange{Field: "event_hours", Old: "", New: "Fri 9am-3pm"}, changes[2])
+
+	assert.Empty(t, DiffListings(&old, &old))
+}
+
+// TestListingContentHash tests the hash follows DiffListings
+func TestListingContentHash(t *testing.T) {
+	now := time.Now()
+	lat := 45.123456789
+	a := Listing{Title: "Sale", City: "Portland", State: "OR", StartDate: now, EndDate: now, Latitude: &lat}
+	assert.Len(t, a.ContentHash(), 64)
+
+	// Same content at database precision, and fields outside the content don't count
+	storedLat := 45.12345679
+	b := a
+	b.StartDate = now.Truncate(time.Microsecond)
+	b.Latitude = &storedLat
+	b.ViewCount = 12
+	assert.Equal(t, a.ContentHash(), b.ContentHash())
+
+	b.Description = "Mid-century furniture"
+	assert.NotEqual(t, a.ContentHash(), b.ContentHash())
+
+	// Sessions in any order, but a changed session or photo is a change
+	fri := Session{Start: now, End: now.Add(6 * time.Hour)}
+	sat := Session{Start: now.Add(24 * time.Hour), End: now.Add(30 * time.Hour)}
+	c, d := a, a
+	c.Sessions = []Session{fri, sat}
+	d.Sessions = []Session{sat, fri}
+	assert.Equal(t, c.ContentHash(), d.ContentHash())
+
+	d.Sessions = []Session{fri}
+	assert.NotEqual(t, c.ContentHash(), d.ContentHash())
+
+	d = c
+	d.Images = []ListingImage{{ImageURL: "https://example.com/1.jpg"}}
+	assert.NotEqual(t, c.ContentHash(), d.ContentHash())
+}
+
+// TestListingKeepStored tests that what a scrape didn't report is kept from the stored listing
+func TestListingKeepStored(t *testing.T) {
+	companyID := 7
+	stored := Listing{EventType: EventMovingSale, CompanyID: &companyID, Images: []ListingImage{{ImageURL: "https://example.com/1.jpg"}}}
+
+	scraped := Listing{Title: "Sale"}
+	scraped.KeepStored(&stored)
+	assert.Equal(t, EventMovingSale, scraped.EventType)
+	assert.Equal(t, &companyID, scraped.CompanyID)
+	assert.Len(t, scraped.Images, 1)
+
+	otherID := 9
+	scraped = Listing{EventType: EventGarageSale, CompanyID: &otherID, Images: []ListingImage{{ImageURL: "https://example.com/2.jpg"}}}
+	scraped.KeepStored(&stored)
+	assert.Equal(t, EventGarageSale, scraped.EventType)
+	assert.Equal(t, &otherID, scraped.CompanyID)
+	assert.Equal(t, "https://example.com/2.jpg", scraped.Images[0].ImageURL)
+}
+
+// TestAddressReleased tests detecting a withheld address being published
+func TestAddressReleased(t *testing.T) {
+	assert.True(t, AddressReleased([]FieldChange{{Field: "address_line1", Old: "TBA", New: "123 SE Main St"}}))
+	assert.True(t, AddressReleased([]FieldChange{{Field: "address_line1", Old: "", New: "123 SE Main St"}}))
+	assert.False(t, AddressReleased([]FieldChange{{Field: "address_line1", Old: "100 Oak St", New: "123 SE Main St"}}))
+	assert.False(t, AddressReleased([]FieldChange{{Field: "address_line1", Old: "100 Oak St", New: "TBA"}}))
+	assert.False(t, AddressReleased([]FieldChange{{Field: "title", Old: "TBA", New: "Sale"}}))
+}
+
+// TestNormalizeAddress tests splitting a full address typed into address_line1
+func TestNormalizeAddress(t *testing.T) {
+	l := Listing{AddressLine1: "1234 SE Main St, Apt 4, Tigard, OR 97223", State: "oregon"}
+	normalizeAddress(&l)
+
+	assert.Equal(t, "1234 SE Main St", l.AddressLine1)
+	require.NotNil(t, l.AddressLine2)
+	assert.Equal(t, "Apt 4", *l.AddressLine2)
+	assert.Equal(t, "Tigard", l.City)
+	assert.Equal(t, "OR", l.State, "state names become codes")
+	assert.Equal(t, "97223", l.ZipCode)
+
+	// Fields the seller filled in aren't overwritten
+	l = Listing{AddressLine1: "1234 SE Main St Portland", City: "Milwaukie", State: "OR"}
+	normalizeAddress(&l)
+	assert.Equal(t, "1234 SE Main St", l.AddressLine1)
+	assert.Equal(t, "Milwaukie", l.City)
+}
+
+// TestResolveSessions tests that unreadable hours are kept as text and bad sessions are
+// validation errors
+func TestResolveSessions(t *testing.T) {
+	s := &Service{}
+	start := time.Date(2025, 10, 31, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 11, 1, 23, 59, 0, 0, time.UTC)
+
+	hours := "Fri 9am-4pm, Sat 9am-3pm"
+	l := Listing{StartDate: start, EndDate: end, EventHours: &hours}
+	require.NoError(t, s.resolveSessions(&l))
+	assert.Len(t, l.Sessions, 2)
+
+	hours = "Fri–Sun, call for times"
+	l = Listing{StartDate: start, EndDate: end, EventHours: &hours}
+	require.NoError(t, s.resolveSessions(&l))
+	assert.Empty(t, l.Sessions)
+	assert.Equal(t, "Fri–Sun, call for times", *l.EventHours)
+
+	l = Listing{StartDate: start, EndDate: end, Sessions: []Session{
+		{Start: start.AddDate(0, 0, 5), End: start.AddDate(0, 0, 5).Add(time.Hour)},
+	}}
+	assert.ErrorIs(t, s.resolveSessions(&l), ErrInvalidSessions)
+
+	l = Listing{StartDate: start, EndDate: end, Sessions: []Session{
+		{Start: start.Add(10 * time.Hour), End: start.Add(9 * time.Hour)},
+	}}
+	assert.ErrorIs(t, s.resolveSessions(&l), ErrInvalidSessions)
+}
@@ -0,0 +1,157 @@
+package listing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldChange is one field's old and new value in a revision
+type FieldChange struct {
+	Field string `json:"field"` // Column name, e.g. "address_line1"
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Revision records what changed in a listing between two scrapes
+type Revision struct {
+	ID        int           `json:"id"`
+	ListingID int           `json:"listing_id"`
+	ChangedAt time.Time     `json:"changed_at"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// ListingHistory is a listing's change timeline, oldest first
+type ListingHistory struct {
+	ListingID         int        `json:"listing_id"`
+	AddressReleasedAt *time.Time `json:"address_released_at,omitempty"`
+	Revisions         []Revision `json:"revisions"`
+}
+
+// DiffListings returns the content fields that differ between old and updated,
+// compared at the precision the database stores
+func DiffListings(old, updated *Listing) []FieldChange {
+	var changes []FieldChange
+	oldFields, newFields := contentFields(old), contentFields(updated)
+	for i, f := range oldFields {
+		if f.value != newFields[i].value {
+			changes = append(changes, FieldChange{Field: f.name, Old: f.value, New: newFields[i].value})
+		}
+	}
+	return changes
+}
+
+// ContentHash returns a SHA-256 of the fields DiffListings compares, so a scrape
+// can tell an unchanged listing apart without loading the stored row
+func (l *Listing) ContentHash() string {
+	h := sha256.New()
+	for _, f := range contentFields(l) {
+		fmt.Fprintf(h, "%s=%q\n", f.name, f.value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type contentField struct {
+	name  string // Column name
+	value string
+}
+
+// contentFields returns a listing's scraped content, formatted at the precision the database stores
+func contentFields(l *Listing) []contentField {
+	return []contentField{
+		{"title", l.Title},
+		{"description", l.Description},
+		{"address_line1", l.AddressLine1},
+		{"address_line2", stringOrEmpty(l.AddressLine2)},
+		{"city", l.City},
+		{"state", l.State},
+		{"zip_code", l.ZipCode},
+		{"latitude", formatCoordinate(l.Latitude)},
+		{"longitude", formatCoordinate(l.Longitude)},
+		{"start_date", formatTime(l.StartDate)},
+		{"end_date", formatTime(l.EndDate)},
+		{"event_hours", stringOrEmpty(l.EventHours)},
+		{"sessions", formatSessions(l.Sessions)},
+		{"event_type", l.EventType},
+		{"external_url", stringOrEmpty(l.ExternalURL)},
+		{"geo_precision", stringOrEmpty(l.GeoPrecision)},
+		{"company_id", formatID(l.CompanyID)},
+		{"image_urls", formatImageURLs(l.Images)},
+	}
+}
+
+// KeepStored fills in what this scrape didn't report from the stored listing: a sale
+// type, company or photos a source stops showing are kept, as UpsertExternalSale keeps them
+func (l *Listing) KeepStored(stored *Listing) {
+	if l.EventType == "" {
+		l.EventType = stored.EventType
+	}
+	if l.CompanyID == nil {
+		l.CompanyID = stored.CompanyID
+	}
+	if len(l.Images) == 0 {
+		l.Images = stored.Images
+	}
+}
+
+// AddressReleased reports whether the changes reveal a street address that was withheld ("TBA" or blank)
+func AddressReleased(changes []FieldChange) bool {
+	for _, c := range changes {
+		if c.Field == "address_line1" && knownStreet(c.Old) == "" && knownStreet(c.New) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+// formatCoordinate rounds to the 8 decimals the database stores
+func formatCoordinate(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', 8, 64)
+}
+
+// formatTime truncates to the microseconds the database stores
+func formatTime(t time.Time) string {
+	return t.Truncate(time.Microsecond).UTC().Format(time.RFC3339Nano)
+}
+
+// formatSessions lists sessions in start order, the order the database returns them
+func formatSessions(sessions []Session) string {
+	sorted := append([]Session(nil), sessions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	formatted := make([]string, len(sorted))
+	for i, session := range sorted {
+		formatted[i] = formatTime(session.Start) + "/" + formatTime(session.End)
+	}
+	return strings.Join(formatted, "\n")
+}
+
+// formatImageURLs lists image URLs in display order (the first is the primary photo)
+func formatImageURLs(images []ListingImage) string {
+	urls := make([]string, len(images))
+	for i, image := range images {
+		urls[i] = image.ImageURL
+	}
+	return strings.Join(urls, "\n")
+}
+
+func formatID(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.Itoa(*id)
+}
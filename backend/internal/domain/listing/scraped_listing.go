@@ -14,6 +14,7 @@ type ScrapedListing struct {
 	// Basic info
 	Title       string `json:"title"`
 	Description string `json:"description,omitempty"` // Optional short snippet
+	EventType   string `json:"event_type,omitempty"`  // Mapped from the source's sale category (see EventTypeFromCategory)
 
 	// Location (for search/filtering)
 	Address  string   `json:"address"`           // Full address string
@@ -22,15 +23,23 @@ type ScrapedListing struct {
 	ZipCode  string   `json:"zip_code"`
 	Latitude  float64 `json:"latitude,omitempty"`
 	Longitude float64 `json:"longitude,omitempty"`
+	GeoPrecision string `json:"geo_precision,omitempty"` // 'rooftop', 'zip' or 'city' (empty if not geocoded)
+
+	AddressReleasedAt *time.Time `json:"address_released_at,omitempty"` // Set once a "TBA" address is published
 
 	// Dates
-	StartDate time.Time `json:"start_date"`
-	EndDate   time.Time `json:"end_date"`
+	StartDate  time.Time `json:"start_date"`
+	EndDate    time.Time `json:"end_date"`
+	EventHours string    `json:"event_hours,omitempty"` // Raw hours text from the source
+	Sessions   []Session `json:"sessions,omitempty"`    // Parsed from EventHours
 
 	// Images (just URLs, we don't download)
 	ThumbnailURL string   `json:"thumbnail_url"`           // Primary thumbnail
 	ImageURLs    []string `json:"image_urls,omitempty"`    // Additional images
 
+	// Company running the sale (from the list or detail page, when the source has one)
+	Company *CompanyContact `json:"company,omitempty"`
+
 	// Source attribution
 	SourceName string `json:"source_name"` // "EstateSales.net"
 	SourceURL  string `json:"source_url"`  // Deep link to original listing
@@ -52,8 +61,12 @@ type AggregatedListing struct {
 	ZipCode      string     `json:"zip_code"`
 	Latitude     *float64   `json:"latitude,omitempty"`
 	Longitude    *float64   `json:"longitude,omitempty"`
+	DistanceMiles *float64  `json:"distance_miles,omitempty"` // Radius searches only
+	GeoPrecision string     `json:"geo_precision,omitempty"`  // How precise Latitude/Longitude are
+	AddressReleasedAt *time.Time `json:"address_released_at,omitempty"` // Scraped only: when a "TBA" address was published
 	StartDate    time.Time  `json:"start_date"`
 	EndDate      time.Time  `json:"end_date"`
+	Sessions     []Session  `json:"sessions,omitempty"`
 	ThumbnailURL string     `json:"thumbnail_url"`
 	ImageURLs    []string   `json:"image_urls,omitempty"`
 
@@ -61,17 +74,49 @@ type AggregatedListing struct {
 	IsScraped bool `json:"is_scraped"` // true = external, false = owned
 
 	// Source (only for scraped)
-	Source *struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"source,omitempty"`
+	Source *SourceLink `json:"source,omitempty"`
+
+	// Set when duplicates from other sources were merged into this listing (see dedup.Merge)
+	Sources      []SourceLink `json:"sources,omitempty"`       // Every source listing this sale, canonical first
+	DuplicateIDs []string     `json:"duplicate_ids,omitempty"` // IDs of the merged duplicates
+
+	Company *CompanyContact `json:"company,omitempty"` // Company running the sale
+
+	EventType string `json:"event_type,omitempty"` // Empty for scraped sales whose source doesn't say
 
 	// Owned sale data (only if IsScraped = false)
-	EventType      string `json:"event_type,omitempty"`
 	Status        string `json:"status,omitempty"`
 	ViewCount     int    `json:"view_count,omitempty"`
 }
 
+// ScrapedFeed is a location's scraped sales and how current they are
+type ScrapedFeed struct {
+	Listings      []ScrapedListing
+	Stale         bool       // Older than the cache TTL, e.g. served while the source is down
+	LastScrapedAt *time.Time // When the location was last scraped (nil if unknown)
+}
+
+// SourceLink attributes a listing to the site it was scraped from
+type SourceLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// CompanyContact is how to reach the company running a sale
+type CompanyContact struct {
+	ID         int    `json:"id,omitempty"` // The stored company (see /api/companies), once saved
+	Name       string `json:"name"`
+	Phone      string `json:"phone,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Website    string `json:"website,omitempty"`
+	ProfileURL string `json:"profile_url,omitempty"` // The company's page on the source
+}
+
+// IsOpenAt reports whether the sale has a session covering t
+func (a *AggregatedListing) IsOpenAt(t time.Time) bool {
+	return OpenAt(a.Sessions, t)
+}
+
 // ToAggregatedSale converts owned Sale to AggregatedListing
 func (s *Listing) ToAggregatedSale() *AggregatedListing {
 	var thumbnailURL string
@@ -90,6 +135,11 @@ func (s *Listing) ToAggregatedSale() *AggregatedListing {
 		address += ", " + *s.AddressLine2
 	}
 
+	geoPrecision := ""
+	if s.GeoPrecision != nil {
+		geoPrecision = *s.GeoPrecision
+	}
+
 	return &AggregatedListing{
 		ID:           fmt.Sprintf("%d", s.ID), // Convert int to string
 		Title:        s.Title,
@@ -100,11 +150,15 @@ func (s *Listing) ToAggregatedSale() *AggregatedListing {
 		ZipCode:      s.ZipCode,
 		Latitude:     s.Latitude,
 		Longitude:    s.Longitude,
+		DistanceMiles: s.DistanceMiles,
+		GeoPrecision: geoPrecision,
 		StartDate:    s.StartDate,
 		EndDate:      s.EndDate,
+		Sessions:     s.Sessions,
 		ThumbnailURL: thumbnailURL,
 		ImageURLs:    imageURLs,
 		IsScraped:    false,
+		Company:      s.Company,
 		EventType:     s.EventType,
 		Status:       s.Status,
 		ViewCount:    s.ViewCount,
@@ -113,6 +167,8 @@ func (s *Listing) ToAggregatedSale() *AggregatedListing {
 
 // ToAggregatedSale converts ScrapedListing to AggregatedListing
 func (s *ScrapedListing) ToAggregatedSale() *AggregatedListing {
+	latitude, longitude := coordinates(s.Latitude, s.Longitude)
+
 	return &AggregatedListing{
 		ID:           s.ExternalID,
 		Title:        s.Title,
@@ -121,17 +177,19 @@ func (s *ScrapedListing) ToAggregatedSale() *AggregatedListing {
 		City:         s.City,
 		State:        s.State,
 		ZipCode:      s.ZipCode,
-		Latitude:     &s.Latitude,
-		Longitude:    &s.Longitude,
+		Latitude:     latitude,
+		Longitude:    longitude,
+		GeoPrecision: s.GeoPrecision,
+		AddressReleasedAt: s.AddressReleasedAt,
+		Company:      s.Company,
+		EventType:    s.EventType,
 		StartDate:    s.StartDate,
 		EndDate:      s.EndDate,
+		Sessions:     s.Sessions,
 		ThumbnailURL: s.ThumbnailURL,
 		ImageURLs:    s.ImageURLs,
 		IsScraped:    true,
-		Source: &struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		}{
+		Source: &SourceLink{
 			Name: s.SourceName,
 			URL:  s.SourceURL,
 		},
@@ -142,6 +200,28 @@ func (s *ScrapedListing) ToAggregatedSale() *AggregatedListing {
 func (s *ScrapedListing) ToSale() Listing {
 	now := time.Now()
 
+	var eventHours *string
+	if s.EventHours != "" {
+		eventHours = &s.EventHours
+	}
+
+	latitude, longitude := coordinates(s.Latitude, s.Longitude)
+	var geoPrecision *string
+	if latitude != nil && s.GeoPrecision != "" {
+		geoPrecision = &s.GeoPrecision
+	}
+
+	var companyID *int
+	if s.Company != nil && s.Company.ID != 0 {
+		id := s.Company.ID
+		companyID = &id
+	}
+
+	images := make([]ListingImage, len(s.ImageURLs))
+	for i, imageURL := range s.ImageURLs {
+		images[i] = ListingImage{ImageURL: imageURL, IsPrimary: i == 0, DisplayOrder: i}
+	}
+
 	return Listing{
 		ListingType:    "external",
 		ExternalID:     &s.ExternalID,
@@ -151,15 +231,22 @@ func (s *ScrapedListing) ToSale() Listing {
 
 		Title:        s.Title,
 		Description:  s.Description,
+		EventType:    s.EventType,
 		AddressLine1: s.Address,
 		City:         s.City,
 		State:        s.State,
 		ZipCode:      s.ZipCode,
-		Latitude:     &s.Latitude,
-		Longitude:    &s.Longitude,
+		Latitude:     latitude,
+		Longitude:    longitude,
+		GeoPrecision: geoPrecision,
+
+		StartDate:  s.StartDate,
+		EndDate:    s.EndDate,
+		EventHours: eventHours,
+		Sessions:   s.Sessions,
 
-		StartDate: s.StartDate,
-		EndDate:   s.EndDate,
+		CompanyID: companyID,
+		Images:    images,
 
 		ViewCount: 0,
 		Featured:  false,
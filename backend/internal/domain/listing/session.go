@@ -0,0 +1,233 @@
+package listing
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session is one open period of a sale (e.g. Saturday 9am-3pm)
+type Session struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether the sale is open at t
+func (s Session) Contains(t time.Time) bool {
+	return !t.Before(s.Start) && t.Before(s.End)
+}
+
+// Overlaps reports whether the session is open at any point in [from, until)
+func (s Session) Overlaps(from, until time.Time) bool {
+	return s.Start.Before(until) && from.Before(s.End)
+}
+
+// OpenAt reports whether any session contains t
+func OpenAt(sessions []Session, t time.Time) bool {
+	for _, s := range sessions {
+		if s.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenDuring reports whether any session overlaps [from, until)
+func OpenDuring(sessions []Session, from, until time.Time) bool {
+	for _, s := range sessions {
+		if s.Overlaps(from, until) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrNoSessions is returned when hours text contains no readable time ranges
+var ErrNoSessions = errors.New("no sale hours found")
+
+var (
+	// "9am-5pm", "10:00 am - 3:00 pm", "9-4pm", "9am to 4pm"
+	hoursRangeRegex = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*([ap]\.?m\.?)?\s*(?:-|–|to)\s*(\d{1,2})(?::(\d{2}))?\s*([ap])\.?m\.?`)
+
+	// "Fri", "Saturday", "Thurs"
+	hoursDayRegex = regexp.MustCompile(`(?i)\b(sun|mon|tue|tues|wed|thu|thur|thurs|fri|sat)(?:day|nesday|rsday|urday|sday)?\b`)
+
+	// "Fri-Sun", "Thursday thru Saturday"
+	hoursDaySpanRegex = regexp.MustCompile(`(?i)\b(sun|mon|tue|wed|thu|fri|sat)[a-z]*\s*(?:-|–|to|thru|through)\s*(sun|mon|tue|wed|thu|fri|sat)[a-z]*\b`)
+
+	// "8th", "Nov 8" - a day of the month
+	hoursDateRegex = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)\b|\b(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?\s+(\d{1,2})\b`)
+)
+
+var sessionWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseSessions turns free-text hours ("Fri 9am-5pm, Sat 9am-3pm") into
+// sessions on the days between from and to (inclusive, in from's location).
+//
+// Each time range applies to the days named just before it - by weekday,
+// weekday span or day of month. A range with no day applies to every day of
+// the sale ("9am-4pm daily").
+func ParseSessions(hours string, from, to time.Time) ([]Session, error) {
+	loc := from.Location()
+	to = to.In(loc)
+
+	ranges := hoursRangeRegex.FindAllStringSubmatchIndex(hours, -1)
+	if len(ranges) == 0 {
+		return nil, ErrNoSessions
+	}
+
+	// Every calendar day of the sale
+	var saleDays []time.Time
+	for d := dayOf(from); !d.After(dayOf(to)); d = d.AddDate(0, 0, 1) {
+		saleDays = append(saleDays, d)
+	}
+
+	var sessions []Session
+	prevEnd := 0
+	for _, idx := range ranges {
+		prefix := hours[prevEnd:idx[0]]
+		prevEnd = idx[1]
+
+		openHour, openMin, closeHour, closeMin, ok := rangeClock(hours, idx)
+		if !ok {
+			continue
+		}
+
+		for _, day := range matchDays(prefix, saleDays) {
+			start := time.Date(day.Year(), day.Month(), day.Day(), openHour, openMin, 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), closeHour, closeMin, 0, 0, loc)
+			if !end.After(start) {
+				continue
+			}
+			sessions = append(sessions, Session{Start: start, End: end})
+		}
+	}
+
+	if len(sessions) == 0 {
+		return nil, ErrNoSessions
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Start.Before(sessions[j].Start)
+	})
+	return sessions, nil
+}
+
+// matchDays returns the sale days named in text (all days if none are named)
+func matchDays(text string, saleDays []time.Time) []time.Time {
+	include := make(map[int]bool) // index into saleDays
+
+	for _, m := range hoursDateRegex.FindAllStringSubmatch(text, -1) {
+		dayStr := m[1]
+		if dayStr == "" {
+			dayStr = m[2]
+		}
+		n, _ := strconv.Atoi(dayStr)
+		for i, d := range saleDays {
+			if d.Day() == n {
+				include[i] = true
+			}
+		}
+	}
+
+	if len(include) == 0 {
+		for _, m := range hoursDaySpanRegex.FindAllStringSubmatch(text, -1) {
+			first := sessionWeekdays[strings.ToLower(m[1])]
+			last := sessionWeekdays[strings.ToLower(m[2])]
+			for i, d := range saleDays {
+				if weekdayBetween(d.Weekday(), first, last) {
+					include[i] = true
+				}
+			}
+		}
+	}
+
+	if len(include) == 0 {
+		for _, m := range hoursDayRegex.FindAllStringSubmatch(text, -1) {
+			wd := sessionWeekdays[strings.ToLower(m[1])[:3]]
+			for i, d := range saleDays {
+				if d.Weekday() == wd {
+					include[i] = true
+				}
+			}
+		}
+	}
+
+	var days []time.Time
+	for i, d := range saleDays {
+		if len(include) == 0 || include[i] {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// rangeClock reads the open/close hour and minute of an hoursRangeRegex match.
+// A bare opening hour takes the closing meridiem unless that puts it after the close ("9-4pm").
+func rangeClock(text string, idx []int) (int, int, int, int, bool) {
+	group := func(n int) string {
+		if idx[2*n] < 0 {
+			return ""
+		}
+		return text[idx[2*n]:idx[2*n+1]]
+	}
+
+	closeHour, closeMin, ok := to24h(group(4), group(5), group(6))
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+
+	openMeridiem := group(3)
+	if openMeridiem == "" {
+		openMeridiem = group(6)
+		if h, _, ok := to24h(group(1), group(2), openMeridiem); ok && h > closeHour {
+			openMeridiem = "a"
+		}
+	}
+	openHour, openMin, ok := to24h(group(1), group(2), openMeridiem)
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+
+	return openHour, openMin, closeHour, closeMin, true
+}
+
+// to24h converts a 12-hour clock reading to 24-hour hour and minute
+func to24h(hourStr, minuteStr, meridiem string) (int, int, bool) {
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil || hour < 1 || hour > 12 {
+		return 0, 0, false
+	}
+	minute := 0
+	if minuteStr != "" {
+		minute, _ = strconv.Atoi(minuteStr)
+		if minute > 59 {
+			return 0, 0, false
+		}
+	}
+
+	hour %= 12
+	if strings.HasPrefix(strings.ToLower(meridiem), "p") {
+		hour += 12
+	}
+	return hour, minute, true
+}
+
+// weekdayBetween reports whether wd falls in the (possibly week-wrapping) span first..last
+func weekdayBetween(wd, first, last time.Weekday) bool {
+	if first <= last {
+		return wd >= first && wd <= last
+	}
+	return wd >= first || wd <= last
+}
+
+// dayOf returns midnight of t's calendar day in t's location
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,91 @@
+package listing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseSessions tests parsing free-text hours into per-day sessions
+func TestParseSessions(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2025, month, day, hour, min, 0, 0, loc)
+	}
+
+	// Thursday Oct 30 - Saturday Nov 1
+	from, to := at(time.October, 30, 0, 0), at(time.November, 1, 23, 59)
+
+	tests := []struct {
+		name     string
+		hours    string
+		expected []Session
+	}{
+		{
+			name:  "weekday per range",
+			hours: "Fri 9am-5pm, Sat 9am-3pm",
+			expected: []Session{
+				{Start: at(time.October, 31, 9, 0), End: at(time.October, 31, 17, 0)},
+				{Start: at(time.November, 1, 9, 0), End: at(time.November, 1, 15, 0)},
+			},
+		},
+		{
+			name:  "weekday span with bare opening hour",
+			hours: "Thurs-Sat 9-4pm",
+			expected: []Session{
+				{Start: at(time.October, 30, 9, 0), End: at(time.October, 30, 16, 0)},
+				{Start: at(time.October, 31, 9, 0), End: at(time.October, 31, 16, 0)},
+				{Start: at(time.November, 1, 9, 0), End: at(time.November, 1, 16, 0)},
+			},
+		},
+		{
+			name:  "no day applies to every day",
+			hours: "10:00am - 3:30pm",
+			expected: []Session{
+				{Start: at(time.October, 30, 10, 0), End: at(time.October, 30, 15, 30)},
+				{Start: at(time.October, 31, 10, 0), End: at(time.October, 31, 15, 30)},
+				{Start: at(time.November, 1, 10, 0), End: at(time.November, 1, 15, 30)},
+			},
+		},
+		{
+			name:  "day of month",
+			hours: "Friday 31st 8am-2pm",
+			expected: []Session{
+				{Start: at(time.October, 31, 8, 0), End: at(time.October, 31, 14, 0)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions, err := ParseSessions(tt.hours, from, to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sessions)
+		})
+	}
+
+	_, err = ParseSessions("By appointment", from, to)
+	assert.ErrorIs(t, err, ErrNoSessions)
+}
+
+// TestOpenAt tests open-now and window checks against sessions
+func TestOpenAt(t *testing.T) {
+	base := time.Date(2025, time.November, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{Start: base.Add(9 * time.Hour), End: base.Add(15 * time.Hour)},
+	}
+
+	assert.True(t, OpenAt(sessions, base.Add(10*time.Hour)))
+	assert.False(t, OpenAt(sessions, base.Add(15*time.Hour)))
+	assert.False(t, OpenAt(sessions, base.Add(8*time.Hour)))
+
+	// "Saturday morning" window 6am-12pm overlaps a 9am opening
+	assert.True(t, OpenDuring(sessions, base.Add(6*time.Hour), base.Add(12*time.Hour)))
+	assert.False(t, OpenDuring(sessions, base.Add(16*time.Hour), base.Add(20*time.Hour)))
+
+	agg := &AggregatedListing{Sessions: sessions}
+	assert.True(t, agg.IsOpenAt(base.Add(12*time.Hour)))
+}
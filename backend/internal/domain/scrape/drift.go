@@ -0,0 +1,94 @@
+package scrape
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Drift detection defaults (SCRAPE_DRIFT_THRESHOLD, SCRAPE_DRIFT_BASELINE_RUNS)
+const (
+	DefaultDriftThreshold    = 0.5 // A field has collapsed below half its baseline rate
+	DefaultDriftBaselineRuns = 5   // Healthy runs averaged into the baseline
+
+	MinDriftRows    = 5    // Runs with fewer sale rows are too small to judge or learn from
+	MinBaselineRuns = 3    // Healthy runs needed before a source can be judged
+	MinBaselineRate = 0.25 // Fields the source rarely has (e.g. ZIPs) aren't judged
+)
+
+// FillRates is the share (0-1) of a run's sale rows that yielded each field.
+// When they collapse against the source's usual rates, its markup has usually
+// changed and the parser is falling back to placeholders.
+type FillRates struct {
+	Rows    int     `json:"rows"` // Sale rows measured (parsed and unparseable)
+	Date    float64 `json:"date"`
+	Address float64 `json:"address"` // A street address, not "TBA"
+	ZipCode float64 `json:"zip_code"`
+	City    float64 `json:"city"`
+}
+
+// QuarantinedListing is a listing from a degraded run, held back instead of
+// overwriting the good data we have
+type QuarantinedListing struct {
+	RunID      int             `json:"run_id"`
+	Source     string          `json:"source"`
+	ExternalID string          `json:"external_id"`
+	Listing    json.RawMessage `json:"listing"` // The scraped listing as parsed
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Baseline averages the fill rates of the latest healthy runs (newest first), up to n
+// of them. Failed, degraded and small runs are left out. ok is false when there are
+// fewer than MinBaselineRuns to go on.
+func Baseline(runs []Run, n int) (baseline FillRates, ok bool) {
+	used := 0
+	for _, run := range runs {
+		if used == n {
+			break
+		}
+		if !run.Succeeded() || run.Degraded || run.FillRates == nil || run.FillRates.Rows < MinDriftRows {
+			continue
+		}
+		baseline.Rows += run.FillRates.Rows
+		baseline.Date += run.FillRates.Date
+		baseline.Address += run.FillRates.Address
+		baseline.ZipCode += run.FillRates.ZipCode
+		baseline.City += run.FillRates.City
+		used++
+	}
+	if used < MinBaselineRuns {
+		return FillRates{}, false
+	}
+
+	baseline.Rows /= used
+	baseline.Date /= float64(used)
+	baseline.Address /= float64(used)
+	baseline.ZipCode /= float64(used)
+	baseline.City /= float64(used)
+	return baseline, true
+}
+
+// Drift describes each field whose rate fell below threshold times its baseline,
+// e.g. "address 4% (usually 96%)". Nil means the run looks like the source's usual
+// output (or is too small to tell).
+func (r FillRates) Drift(baseline FillRates, threshold float64) []string {
+	if r.Rows < MinDriftRows {
+		return nil
+	}
+
+	var drift []string
+	for _, field := range []struct {
+		name          string
+		rate, usually float64
+	}{
+		{"date", r.Date, baseline.Date},
+		{"address", r.Address, baseline.Address},
+		{"zip", r.ZipCode, baseline.ZipCode},
+		{"city", r.City, baseline.City},
+	} {
+		if field.usually >= MinBaselineRate && field.rate < field.usually*threshold {
+			drift = append(drift, fmt.Sprintf("%s %.0f%% (usually %.0f%%)", field.name, field.rate*100, field.usually*100))
+		}
+	}
+	return drift
+}
@@ -0,0 +1,62 @@
+package scrape
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runWithRates(rates FillRates) Run {
+	run := finishedRun("a", rates.Rows, "")
+	run.FillRates = &rates
+	return run
+}
+
+// TestBaseline tests that only healthy runs are averaged
+func TestBaseline(t *testing.T) {
+	good := FillRates{Rows: 20, Date: 1, Address: 0.9, ZipCode: 0.8, City: 1}
+	degraded := runWithRates(FillRates{Rows: 20})
+	degraded.Degraded = true
+
+	runs := []Run{
+		degraded,
+		finishedRun("a", 0, "timeout"),
+		runWithRates(FillRates{Rows: 2}), // Too small
+		runWithRates(good),
+		runWithRates(FillRates{Rows: 10, Date: 1, Address: 0.6, ZipCode: 0.8, City: 1}),
+	}
+	_, ok := Baseline(runs, 5)
+	assert.False(t, ok, "two healthy runs aren't enough")
+
+	runs = append(runs, runWithRates(good), runWithRates(FillRates{Rows: 10}))
+	baseline, ok := Baseline(runs, 3)
+	require.True(t, ok)
+	assert.Equal(t, 16, baseline.Rows)
+	assert.InDelta(t, 0.8, baseline.Address, 0.001)
+	assert.InDelta(t, 1, baseline.Date, 0.001, "runs past n are ignored")
+}
+
+// TestFillRatesDrift tests which fields are reported as collapsed
+func TestFillRatesDrift(t *testing.T) {
+	baseline := FillRates{Rows: 50, Date: 1, Address: 0.96, ZipCode: 0.1, City: 0.9}
+
+	assert.Nil(t, FillRates{Rows: 40, Date: 1, Address: 0.7, ZipCode: 0, City: 0.9}.Drift(baseline, 0.5),
+		"rarely-filled fields aren't judged")
+	assert.Equal(t, []string{"address 4% (usually 96%)"},
+		FillRates{Rows: 40, Date: 1, Address: 0.04, City: 0.9}.Drift(baseline, 0.5))
+	assert.Equal(t, []string{"date 0% (usually 100%)", "city 0% (usually 90%)"},
+		FillRates{Rows: 40, Address: 0.9}.Drift(baseline, 0.5))
+	assert.Nil(t, FillRates{Rows: 3}.Drift(baseline, 0.5), "too few rows to judge")
+}
+
+// TestSummarizeDegraded tests that a degraded run is reported as such
+func TestSummarizeDegraded(t *testing.T) {
+	run := finishedRun("a", 40, "")
+	run.Degraded, run.DegradedReason, run.Quarantined = true, "address 4% (usually 96%)", 40
+
+	summary := Summarize([]Run{run, finishedRun("a", 40, "")})
+	require.Len(t, summary, 1)
+	assert.Equal(t, HealthDegraded, summary[0].Status)
+	assert.Equal(t, "fill rates dropped: address 4% (usually 96%) (40 listings quarantined)", summary[0].Message)
+}
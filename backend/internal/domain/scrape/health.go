@@ -0,0 +1,106 @@
+package scrape
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Health statuses
+const (
+	HealthOK       = "ok"       // Last run found rows
+	HealthEmpty    = "empty"    // Last run found no rows (usually a markup change)
+	HealthFailing  = "failing"  // Last run errored
+	HealthDegraded = "degraded" // Last run's fill rates collapsed; its listings were quarantined
+)
+
+// Health summarizes recent runs of one source for one location
+type Health struct {
+	Source              string `json:"source"`
+	City                string `json:"city"`
+	State               string `json:"state"`
+	Status              string `json:"status"`
+	Message             string `json:"message"` // e.g. "0 rows, previously 140"
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LastRun             Run    `json:"last_run"`
+}
+
+// DedupStats counts location scrapes since the process started. Concurrent requests,
+// revalidations and scheduled refreshes of the same location share one scrape;
+// Deduplicated counts the ones that waited on it.
+type DedupStats struct {
+	Scrapes      int64 `json:"scrapes"`
+	Deduplicated int64 `json:"deduplicated"`
+	TimedOut     int64 `json:"timed_out"` // Gave up waiting (the scrape carried on)
+}
+
+// Summarize builds a Health entry per source and location from runs ordered newest first
+func Summarize(runs []Run) []Health {
+	type key struct{ source, city, state string }
+
+	var order []key
+	grouped := make(map[key][]Run)
+	for _, run := range runs {
+		k := key{run.Source, strings.ToLower(run.City), strings.ToUpper(run.State)}
+		if _, ok := grouped[k]; !ok {
+			order = append(order, k)
+		}
+		grouped[k] = append(grouped[k], run)
+	}
+
+	summary := make([]Health, 0, len(order))
+	for _, k := range order {
+		summary = append(summary, summarizeSource(grouped[k]))
+	}
+	return summary
+}
+
+// summarizeSource summarizes one source/location's runs (newest first)
+func summarizeSource(runs []Run) Health {
+	last := runs[0]
+	h := Health{Source: last.Source, City: last.City, State: last.State, LastRun: last}
+
+	// Previous successful run that saw rows, for "0 rows, previously 140"
+	previousRows := 0
+	for _, run := range runs[1:] {
+		if run.Succeeded() && run.RowsSeen > 0 {
+			previousRows = run.RowsSeen
+			break
+		}
+	}
+
+	switch {
+	case last.Error != nil:
+		for _, run := range runs {
+			if run.Error == nil {
+				break
+			}
+			h.ConsecutiveFailures++
+		}
+		h.Status = HealthFailing
+		h.Message = *last.Error
+		if last.HTTPStatus != nil {
+			h.Message = fmt.Sprintf("HTTP %d: %s", *last.HTTPStatus, h.Message)
+		}
+		if h.ConsecutiveFailures > 1 {
+			h.Message = fmt.Sprintf("%s (%d failures in a row)", h.Message, h.ConsecutiveFailures)
+		}
+
+	case last.Degraded:
+		h.Status = HealthDegraded
+		h.Message = fmt.Sprintf("fill rates dropped: %s (%d listings quarantined)", last.DegradedReason, last.Quarantined)
+
+	case last.RowsSeen == 0:
+		h.Status = HealthEmpty
+		h.Message = "0 rows"
+		if previousRows > 0 {
+			h.Message = fmt.Sprintf("0 rows, previously %d", previousRows)
+		}
+
+	default:
+		h.Status = HealthOK
+		h.Message = fmt.Sprintf("%d rows, %d listings (%d new, %d updated, %d unchanged, %d failed, %d missing, %d tombstoned)",
+			last.RowsSeen, last.ListingsFound, last.Inserted, last.Updated, last.Unchanged, last.Failed, last.Missing, last.Tombstoned)
+	}
+
+	return h
+}
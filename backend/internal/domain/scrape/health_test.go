@@ -0,0 +1,57 @@
+package scrape
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func finishedRun(source string, rows int, errMsg string) Run {
+	now := time.Now()
+	run := Run{Source: source, City: "Portland", State: "OR", StartedAt: now, FinishedAt: &now, RowsSeen: rows, ListingsFound: rows}
+	if errMsg != "" {
+		run.Error = &errMsg
+	}
+	return run
+}
+
+// TestSummarizeEmptyAfterRows tests the "markup changed" case
+func TestSummarizeEmptyAfterRows(t *testing.T) {
+	runs := []Run{
+		finishedRun("EstateSale-Finder.com", 0, ""),
+		finishedRun("EstateSale-Finder.com", 140, ""),
+		finishedRun("EstateSale-Finder.com", 138, ""),
+	}
+
+	summary := Summarize(runs)
+	require.Len(t, summary, 1)
+	assert.Equal(t, HealthEmpty, summary[0].Status)
+	assert.Equal(t, "0 rows, previously 140", summary[0].Message)
+}
+
+// TestSummarizePerSource tests grouping and failure counting
+func TestSummarizePerSource(t *testing.T) {
+	status := 503
+	failed := finishedRun("b", 0, "got status code 503")
+	failed.HTTPStatus = &status
+
+	runs := []Run{
+		finishedRun("a", 12, ""),
+		failed,
+		finishedRun("b", 0, "timeout"),
+		finishedRun("b", 20, ""),
+	}
+
+	summary := Summarize(runs)
+	require.Len(t, summary, 2)
+
+	assert.Equal(t, "a", summary[0].Source)
+	assert.Equal(t, HealthOK, summary[0].Status)
+
+	assert.Equal(t, "b", summary[1].Source)
+	assert.Equal(t, HealthFailing, summary[1].Status)
+	assert.Equal(t, 2, summary[1].ConsecutiveFailures)
+	assert.Equal(t, "HTTP 503: got status code 503 (2 failures in a row)", summary[1].Message)
+}
@@ -0,0 +1,61 @@
+package scrape
+
+import "time"
+
+// Run records one fetch of a source for a location (city/state)
+type Run struct {
+	ID         int        `json:"id"`
+	Source     string     `json:"source"`
+	City       string     `json:"city"`
+	State      string     `json:"state"`
+	Trigger    string     `json:"trigger"` // 'scheduler', 'request' or 'manual'
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// What the source returned
+	HTTPStatus    *int `json:"http_status,omitempty"`
+	RowsSeen      int  `json:"rows_seen"`      // Rows/entries on the page before parsing
+	ListingsFound int  `json:"listings_found"` // Rows that parsed into listings
+
+	// What persisting the listings did
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+
+	// Listings this source had for the location but didn't return
+	Missing    int `json:"missing"`
+	Tombstoned int `json:"tombstoned"`
+
+	// Field fill rates; a run whose rates collapsed against the baseline is degraded
+	// and its listings are quarantined rather than upserted (see drift.go)
+	FillRates      *FillRates `json:"fill_rates,omitempty"`
+	Degraded       bool       `json:"degraded"`
+	DegradedReason string     `json:"degraded_reason,omitempty"` // e.g. "address 4% (usually 96%)"
+	Quarantined    int        `json:"quarantined"`
+
+	Error *string `json:"error,omitempty"`
+}
+
+// Run triggers
+const (
+	TriggerScheduler = "scheduler" // Periodic background refresh
+	TriggerRequest   = "request"   // Stale-while-revalidate or scrape-on-request
+	TriggerManual    = "manual"    // One-off run from cmd/scrape -commit
+)
+
+// Finish marks the run as done with its result
+func (r *Run) Finish(listingsFound int, err error) {
+	now := time.Now()
+	r.FinishedAt = &now
+	r.ListingsFound = listingsFound
+	if err != nil {
+		msg := err.Error()
+		r.Error = &msg
+	}
+}
+
+// Succeeded reports whether the run finished without an error
+func (r *Run) Succeeded() bool {
+	return r.FinishedAt != nil && r.Error == nil
+}
@@ -0,0 +1,17 @@
+package scrape
+
+import "context"
+
+// RunRepository defines the interface for scrape run records
+type RunRepository interface {
+	RecordRun(ctx context.Context, run *Run) error
+	ListRuns(ctx context.Context, limit int) ([]Run, error)
+	RecentRunsBySource(ctx context.Context, perSource int) ([]Run, error)
+
+	// RecentRuns returns one source's latest runs for a location, newest first
+	RecentRuns(ctx context.Context, source, city, state string, limit int) ([]Run, error)
+
+	// Quarantine stores a degraded run's listings; ListQuarantined returns them
+	QuarantineListings(ctx context.Context, listings []QuarantinedListing) error
+	ListQuarantined(ctx context.Context, runID int) ([]QuarantinedListing, error)
+}
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/company"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/dedup"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/user"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/api"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/middleware"
+)
+
+// CompanyHandler handles HTTP requests for estate sale companies
+type CompanyHandler struct {
+	companyService *company.Service
+	listingService *listing.Service
+	userService    *user.Service
+}
+
+// NewCompanyHandler creates a new company handler
+func NewCompanyHandler(companyService *company.Service, listingService *listing.Service, userService *user.Service) *CompanyHandler {
+	return &CompanyHandler{
+		companyService: companyService,
+		listingService: listingService,
+		userService:    userService,
+	}
+}
+
+// List handles GET /api/companies (?q= name search, ?limit=, ?offset=)
+func (h *CompanyHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filters := company.Filters{Query: query.Get("q")}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset > 0 {
+		filters.Offset = offset
+	}
+
+	companies, err := h.companyService.ListCompanies(r.Context(), filters)
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to fetch companies")
+		return
+	}
+
+	api.OKResponse(w, companies, "")
+}
+
+// GetByID handles GET /api/companies/:id
+func (h *CompanyHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/companies/"))
+	if err != nil {
+		api.ErrorResponseSingle(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.companyService.GetCompany(r.Context(), id)
+	if err != nil {
+		api.NotFoundResponse(w, "Company not found")
+		return
+	}
+
+	api.OKResponse(w, c, "")
+}
+
+// GetSales handles GET /api/companies/:id/sales: the company's current and upcoming
+// sales, owned and scraped
+func (h *CompanyHandler) GetSales(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/companies/"), "/sales")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		api.ErrorResponseSingle(w, "Invalid company ID", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.companyService.GetCompany(r.Context(), id)
+	if err != nil {
+		api.NotFoundResponse(w, "Company not found")
+		return
+	}
+
+	sales, err := h.listingService.GetAllListings(r.Context(), listing.ListingFilters{
+		CompanyID:       &c.ID,
+		Status:          "published",
+		IncludeExternal: true,
+		ExcludeEnded:    true,
+		Limit:           100,
+	})
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to fetch company sales")
+		return
+	}
+
+	var aggregatedListings []*listing.AggregatedListing
+	for i := range sales {
+		s := &sales[i]
+		if s.ListingType == "external" {
+			scraped := s.ToScrapedListing()
+			aggregatedListings = append(aggregatedListings, scraped.ToAggregatedSale())
+		} else {
+			aggregatedListings = append(aggregatedListings, s.ToAggregatedSale())
+		}
+	}
+
+	// The company's own listing of a sale and a site's copy of it are one sale
+	aggregatedListings = dedup.Merge(aggregatedListings)
+	sort.Slice(aggregatedListings, func(i, j int) bool {
+		return aggregatedListings[i].StartDate.Before(aggregatedListings[j].StartDate)
+	})
+
+	api.OKResponse(w, map[string]interface{}{
+		"company": c,
+		"sales":   aggregatedListings,
+		"total":   len(aggregatedListings),
+	}, "")
+}
+
+// GetMyCompany handles GET /api/my-company (authenticated sellers only)
+func (h *CompanyHandler) GetMyCompany(w http.ResponseWriter, r *http.Request) {
+	uid := r.Context().Value(middleware.ContextKeyUID).(string)
+	u, err := h.userService.GetOrCreateUser(uid, "")
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to get user")
+		return
+	}
+
+	c, err := h.companyService.GetSellerCompany(r.Context(), u.ID)
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to get company")
+		return
+	}
+	if c == nil {
+		api.NotFoundResponse(w, "No company set up for this account")
+		return
+	}
+
+	api.OKResponse(w, c, "")
+}
+
+// SaveMyCompany handles PUT /api/my-company: creates or updates the seller's company
+// and links their sales to it
+func (h *CompanyHandler) SaveMyCompany(w http.ResponseWriter, r *http.Request) {
+	uid := r.Context().Value(middleware.ContextKeyUID).(string)
+	u, err := h.userService.GetOrCreateUser(uid, "")
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to get user")
+		return
+	}
+
+	var c company.Company
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		api.ErrorResponseSingle(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if errs := c.Validate(); len(errs) > 0 {
+		api.ValidationErrorResponse(w, errs)
+		return
+	}
+
+	// Only scrapers set where a company was found
+	c.Source, c.ProfileURL = "", ""
+	if err := h.companyService.SaveSellerCompany(r.Context(), u.ID, &c); err != nil {
+		api.InternalErrorResponse(w, "Failed to save company")
+		return
+	}
+
+	api.OKResponse(w, c, "Company saved")
+}
@@ -1,14 +1,19 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/dedup"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/user"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/api"
@@ -25,7 +30,7 @@ type ListingHandler struct {
 
 // ScraperService is the interface for the scraper
 type ScraperService interface {
-	GetListingsByLocation(city, state string) ([]listing.ScrapedListing, error)
+	GetFeedByLocation(ctx context.Context, city, state string) (*listing.ScrapedFeed, error)
 }
 
 // NewListingHandler creates a new listing handler
@@ -79,7 +84,11 @@ func (h *ListingHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create the sale
-	if err := h.listingService.CreateListing(&s); err != nil {
+	if err := h.listingService.CreateListing(r.Context(), &s); err != nil {
+		if errors.Is(err, listing.ErrInvalidSessions) {
+			api.ValidationErrorResponse(w, []string{err.Error()})
+			return
+		}
 		api.InternalErrorResponse(w, fmt.Sprintf("Failed to create listing: %v", err))
 		return
 	}
@@ -97,7 +106,7 @@ func (h *ListingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s, err := h.listingService.GetListingByID(id)
+	s, err := h.listingService.GetListingByID(r.Context(), id)
 	if err != nil {
 		api.NotFoundResponse(w, "Listing not found")
 		return
@@ -106,6 +115,27 @@ func (h *ListingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	api.OKResponse(w, s, "")
 }
 
+// GetHistory handles GET /api/sales/:id/history (id may be a scraped listing's external ID)
+func (h *ListingHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	ref := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/sales/"), "/history")
+	if ref == "" {
+		api.ErrorResponseSingle(w, "Invalid listing ID", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.listingService.GetListingHistory(r.Context(), ref)
+	if err != nil {
+		if errors.Is(err, listing.ErrNotFound) {
+			api.NotFoundResponse(w, "Listing not found")
+			return
+		}
+		api.InternalErrorResponse(w, "Failed to get listing history")
+		return
+	}
+
+	api.OKResponse(w, history, "")
+}
+
 // GetAll handles GET /api/sales
 func (h *ListingHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
@@ -117,6 +147,7 @@ func (h *ListingHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		ZipCode:  query.Get("zip_code"),
 		EventType: query.Get("event_type"),
 		Status:   "published", // Only show published sales to public
+		ExcludeEnded: true,
 	}
 
 	// Parse pagination
@@ -147,7 +178,13 @@ func (h *ListingHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	sales, err := h.listingService.GetAllListings(filters)
+	// Parse radius search (?lat=&lng=&radius_miles=&sort=distance)
+	if err := parseGeoFilters(query, &filters); err != nil {
+		api.ErrorResponseSingle(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sales, err := h.listingService.GetAllListings(r.Context(), filters)
 	if err != nil {
 		api.InternalErrorResponse(w, "Failed to fetch listings")
 		return
@@ -166,7 +203,7 @@ func (h *ListingHandler) GetMySales(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sales, err := h.listingService.GetSellerListings(u.ID)
+	sales, err := h.listingService.GetSellerListings(r.Context(), u.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -199,7 +236,7 @@ func (h *ListingHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get existing sale to check ownership
-	existingListing, err := h.listingService.GetListingByID(id)
+	existingListing, err := h.listingService.GetListingByID(r.Context(), id)
 	if err != nil {
 		http.Error(w, "Sale not found", http.StatusNotFound)
 		return
@@ -222,7 +259,11 @@ func (h *ListingHandler) Update(w http.ResponseWriter, r *http.Request) {
 	s.SellerID = &u.ID
 	s.ListingType = "owned"
 
-	if err := h.listingService.UpdateListing(&s); err != nil {
+	if err := h.listingService.UpdateListing(r.Context(), &s); err != nil {
+		if errors.Is(err, listing.ErrInvalidSessions) {
+			api.ValidationErrorResponse(w, []string{err.Error()})
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -254,7 +295,7 @@ func (h *ListingHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get existing sale to check ownership
-	existingListing, err := h.listingService.GetListingByID(id)
+	existingListing, err := h.listingService.GetListingByID(r.Context(), id)
 	if err != nil {
 		http.Error(w, "Sale not found", http.StatusNotFound)
 		return
@@ -266,7 +307,7 @@ func (h *ListingHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.listingService.DeleteListing(id); err != nil {
+	if err := h.listingService.DeleteListing(r.Context(), id); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -297,7 +338,7 @@ func (h *ListingHandler) AddImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify ownership
-	existingListing, err := h.listingService.GetListingByID(listingID)
+	existingListing, err := h.listingService.GetListingByID(r.Context(), listingID)
 	if err != nil {
 		http.Error(w, "Sale not found", http.StatusNotFound)
 		return
@@ -315,7 +356,7 @@ func (h *ListingHandler) AddImage(w http.ResponseWriter, r *http.Request) {
 
 	img.ListingID = listingID
 
-	if err := h.listingService.AddListingImage(&img); err != nil {
+	if err := h.listingService.AddListingImage(r.Context(), &img); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -332,6 +373,24 @@ func (h *ListingHandler) GetAggregatedSales(w http.ResponseWriter, r *http.Reque
 	city := query.Get("city")
 	state := query.Get("state")
 
+	// ?event_type= also returns scraped sales whose source doesn't say what kind of sale it is
+	filters := listing.ListingFilters{
+		EventType:    query.Get("event_type"),
+		Status:       "published",
+		ExcludeEnded: true,
+	}
+	if err := parseGeoFilters(query, &filters); err != nil {
+		api.ErrorResponseSingle(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Radius searches cross city lines (e.g. Beaverton buyers see Portland sales),
+	// so owned and scraped sales both come from the database by distance
+	if filters.HasGeo() {
+		h.getAggregatedSalesNear(r.Context(), w, query, filters, city, state)
+		return
+	}
+
 	// Default to Portland, OR if no location provided
 	if city == "" && state == "" {
 		city = "Portland"
@@ -341,13 +400,10 @@ func (h *ListingHandler) GetAggregatedSales(w http.ResponseWriter, r *http.Reque
 	var aggregatedListings []*listing.AggregatedListing
 
 	// 1. Get owned sales from database
-	filters := listing.ListingFilters{
-		City:   city,
-		State:  state,
-		Status: "published",
-	}
+	filters.City = city
+	filters.State = state
 
-	ownedListings, err := h.listingService.GetAllListings(filters)
+	ownedListings, err := h.listingService.GetAllListings(r.Context(), filters)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to fetch owned sales: %v", err), http.StatusInternalServerError)
 		return
@@ -359,26 +415,191 @@ func (h *ListingHandler) GetAggregatedSales(w http.ResponseWriter, r *http.Reque
 	}
 
 	// 2. Get scraped sales (if city and state provided AND scraper is enabled)
+	var feed *listing.ScrapedFeed
 	if city != "" && state != "" && h.scraperService != nil {
-		scrapedListings, err := h.scraperService.GetListingsByLocation(city, state)
+		feed, err = h.scraperService.GetFeedByLocation(r.Context(), city, state)
 		if err != nil {
 			// Log error but don't fail the request
 			fmt.Printf("Warning: Failed to fetch scraped sales: %v\n", err)
 		} else {
-			// Convert scraped sales to aggregated format
-			for _, s := range scrapedListings {
+			// Convert scraped sales to aggregated format (cached results may include ended sales)
+			now := time.Now()
+			for _, s := range feed.Listings {
+				if s.EndDate.Before(now) || (filters.EventType != "" && s.EventType != "" && s.EventType != filters.EventType) {
+					continue
+				}
 				aggregatedListings = append(aggregatedListings, s.ToAggregatedSale())
 			}
 		}
 	}
 
-	// 3. Sort by start_date (most recent/upcoming first)
-	sort.Slice(aggregatedListings, func(i, j int) bool {
-		return aggregatedListings[i].StartDate.After(aggregatedListings[j].StartDate)
-	})
+	h.writeAggregatedSales(w, query, aggregatedListings, "", feed)
+}
+
+// getAggregatedSalesNear serves GetAggregatedSales for a radius search
+func (h *ListingHandler) getAggregatedSalesNear(ctx context.Context, w http.ResponseWriter, query url.Values, filters listing.ListingFilters, city, state string) {
+	// Refresh scraped sales for the named city first; they're read back from the DB below
+	var feed *listing.ScrapedFeed
+	if city != "" && state != "" && h.scraperService != nil {
+		var err error
+		if feed, err = h.scraperService.GetFeedByLocation(ctx, city, state); err != nil {
+			fmt.Printf("Warning: Failed to fetch scraped sales: %v\n", err)
+		}
+	}
+
+	filters.IncludeExternal = true
+	filters.Limit = 100
+
+	nearby, err := h.listingService.GetAllListings(ctx, filters)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to fetch nearby sales: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var aggregatedListings []*listing.AggregatedListing
+	for i := range nearby {
+		s := &nearby[i]
+		if s.ListingType == "external" {
+			scraped := s.ToScrapedListing()
+			aggregated := scraped.ToAggregatedSale()
+			aggregated.DistanceMiles = s.DistanceMiles
+			aggregatedListings = append(aggregatedListings, aggregated)
+		} else {
+			aggregatedListings = append(aggregatedListings, s.ToAggregatedSale())
+		}
+	}
+
+	h.writeAggregatedSales(w, query, aggregatedListings, filters.SortBy, feed)
+}
 
-	api.OKResponse(w, map[string]interface{}{
+// writeAggregatedSales applies the open-at filter and sort, then writes the response.
+// feed is the scraped feed the listings include (nil if none); it sets "stale".
+func (h *ListingHandler) writeAggregatedSales(w http.ResponseWriter, query url.Values, aggregatedListings []*listing.AggregatedListing, sortBy string, feed *listing.ScrapedFeed) {
+	// 3. Collapse the same sale listed by several sources (or by its seller and a scraped site)
+	aggregatedListings = dedup.Merge(aggregatedListings)
+
+	// 4. Optional "open at" filtering against structured sessions
+	//    ?open_now=true, ?open_at=<RFC3339>, ?open_at=...&open_until=... (window, e.g. Saturday morning)
+	openFrom, openUntil, err := parseOpenWindow(query)
+	if err != nil {
+		api.ErrorResponseSingle(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if openFrom != nil {
+		var openListings []*listing.AggregatedListing
+		for _, l := range aggregatedListings {
+			isOpen := l.IsOpenAt(*openFrom)
+			if openUntil != nil {
+				isOpen = listing.OpenDuring(l.Sessions, *openFrom, *openUntil)
+			}
+			if isOpen {
+				openListings = append(openListings, l)
+			}
+		}
+		aggregatedListings = openListings
+	}
+
+	// 5. Sort by distance (nearest first) or start_date (most recent/upcoming first)
+	if sortBy == "distance" {
+		sort.SliceStable(aggregatedListings, func(i, j int) bool {
+			return distanceOf(aggregatedListings[i]) < distanceOf(aggregatedListings[j])
+		})
+	} else {
+		sort.Slice(aggregatedListings, func(i, j int) bool {
+			return aggregatedListings[i].StartDate.After(aggregatedListings[j].StartDate)
+		})
+	}
+
+	response := map[string]interface{}{
 		"sales": aggregatedListings,
 		"total": len(aggregatedListings),
-	}, "")
+		"stale": feed != nil && feed.Stale,
+	}
+	// Lets the frontend say how old last-known sales are
+	if feed != nil && feed.Stale && feed.LastScrapedAt != nil {
+		response["last_scraped_at"] = feed.LastScrapedAt
+	}
+	api.OKResponse(w, response, "")
+}
+
+// distanceOf returns a listing's search distance (listings without one sort last)
+func distanceOf(l *listing.AggregatedListing) float64 {
+	if l.DistanceMiles == nil {
+		return math.MaxFloat64
+	}
+	return *l.DistanceMiles
+}
+
+// parseGeoFilters reads ?lat=&lng=&radius_miles=&sort= into filters.
+// lat and lng must be given together; radius_miles defaults in the service.
+func parseGeoFilters(query url.Values, filters *listing.ListingFilters) error {
+	sortBy := query.Get("sort")
+	if sortBy != "" && sortBy != "distance" {
+		return fmt.Errorf("sort must be 'distance'")
+	}
+
+	latStr, lngStr := query.Get("lat"), query.Get("lng")
+	if latStr == "" && lngStr == "" {
+		if sortBy == "distance" {
+			return fmt.Errorf("sort=distance requires lat and lng")
+		}
+		return nil
+	}
+	if latStr == "" || lngStr == "" {
+		return fmt.Errorf("lat and lng must be given together")
+	}
+
+	lat, err := strconv.ParseFloat(latStr, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return fmt.Errorf("lat must be a number between -90 and 90")
+	}
+	lng, err := strconv.ParseFloat(lngStr, 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return fmt.Errorf("lng must be a number between -180 and 180")
+	}
+
+	if radiusStr := query.Get("radius_miles"); radiusStr != "" {
+		radius, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radius <= 0 {
+			return fmt.Errorf("radius_miles must be a positive number")
+		}
+		filters.RadiusMiles = radius
+	}
+
+	filters.Latitude = &lat
+	filters.Longitude = &lng
+	filters.SortBy = sortBy
+	return nil
+}
+
+// parseOpenWindow reads open_now / open_at / open_until query params.
+// Returns nil times when no open filter was requested.
+func parseOpenWindow(query url.Values) (*time.Time, *time.Time, error) {
+	if query.Get("open_now") == "true" {
+		now := time.Now()
+		return &now, nil, nil
+	}
+
+	openAtStr := query.Get("open_at")
+	if openAtStr == "" {
+		return nil, nil, nil
+	}
+	openAt, err := time.Parse(time.RFC3339, openAtStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open_at must be an RFC3339 timestamp")
+	}
+
+	openUntilStr := query.Get("open_until")
+	if openUntilStr == "" {
+		return &openAt, nil, nil
+	}
+	openUntil, err := time.Parse(time.RFC3339, openUntilStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open_until must be an RFC3339 timestamp")
+	}
+	if !openUntil.After(openAt) {
+		return nil, nil, fmt.Errorf("open_until must be after open_at")
+	}
+
+	return &openAt, &openUntil, nil
 }
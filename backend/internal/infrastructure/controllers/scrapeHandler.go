@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/api"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/middleware"
+)
+
+// ScrapeHandler handles admin HTTP requests for scrape runs
+type ScrapeHandler struct {
+	runs      scrape.RunRepository
+	adminUIDs map[string]bool
+	stats     ScrapeStats // Optional, see SetScrapeStats
+}
+
+// ScrapeStats is the interface for the scraper's request deduplication counters
+type ScrapeStats interface {
+	DedupStats() scrape.DedupStats
+}
+
+// NewScrapeHandler creates a new scrape handler. adminUIDs lists the Firebase UIDs
+// allowed to use admin endpoints (comma-separated, e.g. from ADMIN_UIDS).
+func NewScrapeHandler(runs scrape.RunRepository, adminUIDs string) *ScrapeHandler {
+	h := &ScrapeHandler{runs: runs, adminUIDs: make(map[string]bool)}
+	for _, uid := range strings.Split(adminUIDs, ",") {
+		if uid = strings.TrimSpace(uid); uid != "" {
+			h.adminUIDs[uid] = true
+		}
+	}
+	return h
+}
+
+// SetScrapeStats sets where ListScrapes reads request deduplication counters from
+func (h *ScrapeHandler) SetScrapeStats(stats ScrapeStats) {
+	h.stats = stats
+}
+
+// healthRunsPerSource is how many recent runs per source/location feed the health summary
+const healthRunsPerSource = 20
+
+// ListScrapes handles GET /api/admin/scrapes?limit=50
+// Returns recent runs and a per-source health summary
+func (h *ScrapeHandler) ListScrapes(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 500 {
+			limit = l
+		}
+	}
+
+	runs, err := h.runs.ListRuns(r.Context(), limit)
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to fetch scrape runs")
+		return
+	}
+
+	recent, err := h.runs.RecentRunsBySource(r.Context(), healthRunsPerSource)
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to fetch scrape health")
+		return
+	}
+
+	response := map[string]interface{}{
+		"runs":   runs,
+		"health": scrape.Summarize(recent),
+	}
+	if h.stats != nil {
+		response["dedup"] = h.stats.DedupStats()
+	}
+	api.OKResponse(w, response, "")
+}
+
+// ListQuarantined handles GET /api/admin/scrapes/quarantine?run_id=123
+// Returns the listings a degraded run held back instead of saving
+func (h *ScrapeHandler) ListQuarantined(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	runID, err := strconv.Atoi(r.URL.Query().Get("run_id"))
+	if err != nil || runID <= 0 {
+		api.ErrorResponseSingle(w, "Invalid run_id", http.StatusBadRequest)
+		return
+	}
+
+	listings, err := h.runs.ListQuarantined(r.Context(), runID)
+	if err != nil {
+		api.InternalErrorResponse(w, "Failed to fetch quarantined listings")
+		return
+	}
+
+	api.OKResponse(w, listings, "")
+}
+
+// requireAdmin checks the authenticated user is an admin, writing the error response if not
+func (h *ScrapeHandler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	uid, ok := r.Context().Value(middleware.ContextKeyUID).(string)
+	if !ok || uid == "" {
+		api.UnauthorizedResponse(w, "")
+		return false
+	}
+	if !h.adminUIDs[uid] {
+		api.ForbiddenResponse(w, "Admin access required")
+		return false
+	}
+	return true
+}
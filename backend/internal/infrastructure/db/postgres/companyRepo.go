@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/company"
+)
+
+// CompanyRepository implements the company.Repository interface
+type CompanyRepository struct {
+	db *sql.DB
+}
+
+// NewCompanyRepository creates a new company repository
+func NewCompanyRepository(db *sql.DB) *CompanyRepository {
+	return &CompanyRepository{db: db}
+}
+
+const companyColumns = `id, name, COALESCE(website, ''), COALESCE(phone, ''), COALESCE(email, ''),
+	COALESCE(source, ''), COALESCE(profile_url, ''), seller_id, created_at, updated_at`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCompany(row rowScanner) (*company.Company, error) {
+	c := &company.Company{}
+	err := row.Scan(
+		&c.ID, &c.Name, &c.Website, &c.Phone, &c.Email,
+		&c.Source, &c.ProfileURL, &c.SellerID, &c.CreatedAt, &c.UpdatedAt,
+	)
+	return c, err
+}
+
+// GetByID retrieves a company by ID
+func (r *CompanyRepository) GetByID(ctx context.Context, id int) (*company.Company, error) {
+	c, err := scanCompany(r.db.QueryRowContext(ctx, `SELECT `+companyColumns+` FROM companies WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("company not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company: %w", err)
+	}
+	return c, nil
+}
+
+// GetBySellerID retrieves the company a seller manages (nil if none)
+func (r *CompanyRepository) GetBySellerID(ctx context.Context, sellerID int) (*company.Company, error) {
+	c, err := scanCompany(r.db.QueryRowContext(ctx, `SELECT `+companyColumns+` FROM companies WHERE seller_id = $1`, sellerID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seller company: %w", err)
+	}
+	return c, nil
+}
+
+// List retrieves companies by name
+func (r *CompanyRepository) List(ctx context.Context, filters company.Filters) ([]company.Company, error) {
+	query := `
+		SELECT ` + companyColumns + `
+		FROM companies
+		WHERE $1 = '' OR name ILIKE '%' || $1 || '%'
+		ORDER BY name, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filters.Query, filters.Limit, filters.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query companies: %w", err)
+	}
+	defer rows.Close()
+
+	companies := []company.Company{}
+	for rows.Next() {
+		c, err := scanCompany(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan company: %w", err)
+		}
+		companies = append(companies, *c)
+	}
+
+	return companies, rows.Err()
+}
+
+// SaveSellerCompany creates or updates the company managed by c.SellerID and links the
+// seller's listings to it
+func (r *CompanyRepository) SaveSellerCompany(ctx context.Context, c *company.Company) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	saved, err := scanCompany(tx.QueryRowContext(ctx, `
+		INSERT INTO companies (name, name_norm, website, phone, email, seller_id, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, $7, $7)
+		ON CONFLICT (seller_id) WHERE seller_id IS NOT NULL DO UPDATE SET
+			name = EXCLUDED.name,
+			name_norm = EXCLUDED.name_norm,
+			website = EXCLUDED.website,
+			phone = EXCLUDED.phone,
+			email = EXCLUDED.email,
+			updated_at = EXCLUDED.updated_at
+		RETURNING `+companyColumns,
+		c.Name, company.NormalizeName(c.Name), c.Website, c.Phone, c.Email, c.SellerID, now,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to save company: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE listings SET company_id = $1
+		WHERE seller_id = $2 AND company_id IS DISTINCT FROM $1
+	`, saved.ID, c.SellerID)
+	if err != nil {
+		return fmt.Errorf("failed to link seller listings to company: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit company: %w", err)
+	}
+
+	*c = *saved
+	return nil
+}
+
+// UpsertScraped matches a scraped company to a stored scraped one (by profile URL, then by
+// normalized name), merging it in or inserting it. c is updated to the stored company.
+func (r *CompanyRepository) UpsertScraped(ctx context.Context, c *company.Company) error {
+	nameNorm := company.NormalizeName(c.Name)
+	if nameNorm == "" {
+		return fmt.Errorf("company name %q is empty", c.Name)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize scrapes of the same company so two of them can't both insert it
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('company:' || $1))`, nameNorm); err != nil {
+		return fmt.Errorf("failed to lock company: %w", err)
+	}
+
+	var existing *company.Company
+	if c.ProfileURL != "" {
+		existing, err = scanCompany(tx.QueryRowContext(ctx,
+			`SELECT `+companyColumns+` FROM companies WHERE profile_url = $1 FOR UPDATE`, c.ProfileURL))
+	}
+	if existing == nil || err == sql.ErrNoRows {
+		existing, err = scanCompany(tx.QueryRowContext(ctx, `
+			SELECT `+companyColumns+` FROM companies
+			WHERE name_norm = $1 AND seller_id IS NULL
+			ORDER BY id LIMIT 1
+			FOR UPDATE
+		`, nameNorm))
+	}
+
+	now := time.Now()
+	var saved *company.Company
+	switch {
+	case err == sql.ErrNoRows:
+		saved, err = scanCompany(tx.QueryRowContext(ctx, `
+			INSERT INTO companies (name, name_norm, website, phone, email, source, profile_url, created_at, updated_at)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $8)
+			RETURNING `+companyColumns,
+			c.Name, nameNorm, c.Website, c.Phone, c.Email, c.Source, c.ProfileURL, now,
+		))
+	case err != nil:
+		return fmt.Errorf("failed to find company: %w", err)
+	default:
+		existing.MergeScraped(*c)
+		saved, err = scanCompany(tx.QueryRowContext(ctx, `
+			UPDATE companies SET
+				name = $1, name_norm = $2, website = NULLIF($3, ''), phone = NULLIF($4, ''), email = NULLIF($5, ''),
+				source = NULLIF($6, ''), profile_url = NULLIF($7, ''), updated_at = $8
+			WHERE id = $9
+			RETURNING `+companyColumns,
+			existing.Name, company.NormalizeName(existing.Name), existing.Website, existing.Phone, existing.Email,
+			existing.Source, existing.ProfileURL, now, existing.ID,
+		))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save company: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit company: %w", err)
+	}
+
+	*c = *saved
+	return nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// OpenFromEnv opens and pings the database configured by DB_USER, DB_PASS,
+// DB_NAME, DB_HOST (IP address or Cloud SQL Unix socket path) and DB_PORT
+func OpenFromEnv() (*sql.DB, error) {
+	dbUser := os.Getenv("DB_USER")
+	dbPass := os.Getenv("DB_PASS")
+	dbName := os.Getenv("DB_NAME")
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+
+	// Ensure all required environment variables are set
+	if dbUser == "" || dbPass == "" || dbName == "" || dbHost == "" {
+		return nil, fmt.Errorf("database environment variables are not set properly")
+	}
+
+	// Default port if not specified
+	if dbPort == "" {
+		dbPort = "5432"
+	}
+
+	// Build DSN - use port if it's a TCP connection (IP address), otherwise Unix socket
+	var dsn string
+	if !strings.HasPrefix(dbHost, "/cloudsql") {
+		dsn = fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable", dbUser, dbPass, dbName, dbHost, dbPort)
+	} else {
+		dsn = fmt.Sprintf("user=%s password=%s dbname=%s host=%s sslmode=disable", dbUser, dbPass, dbName, dbHost)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Verify database connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
@@ -1,9 +1,12 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
+	"github.com/lib/pq"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
 )
 
@@ -17,60 +20,73 @@ func NewListingRepository(db *sql.DB) *ListingRepository {
 	return &ListingRepository{db: db}
 }
 
-// Create creates a new listing
-func (r *ListingRepository) Create(s *listing.Listing) error {
+// Create creates a new listing and its sessions in one transaction
+func (r *ListingRepository) Create(ctx context.Context, s *listing.Listing) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO listings (
 			listing_type, seller_id, title, description, event_type, status,
-			address_line1, address_line2, city, state, zip_code, latitude, longitude,
+			address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
 			start_date, end_date, event_hours,
 			listing_tier, payment_status, amount_paid,
-			view_count, featured, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
-		RETURNING id
+			view_count, featured, created_at, updated_at, company_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24,
+			(SELECT id FROM companies WHERE seller_id = $2))
+		RETURNING id, company_id
 	`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRowContext(ctx,
 		query,
 		s.ListingType, s.SellerID, s.Title, s.Description, s.EventType, s.Status,
-		s.AddressLine1, s.AddressLine2, s.City, s.State, s.ZipCode, s.Latitude, s.Longitude,
+		s.AddressLine1, s.AddressLine2, s.City, s.State, s.ZipCode, s.Latitude, s.Longitude, s.GeoPrecision,
 		s.StartDate, s.EndDate, s.EventHours,
 		s.ListingTier, s.PaymentStatus, s.AmountPaid,
 		s.ViewCount, s.Featured, s.CreatedAt, s.UpdatedAt,
-	).Scan(&s.ID)
+	).Scan(&s.ID, &s.CompanyID)
 
 	if err != nil {
 		return fmt.Errorf("failed to create listing: %w", err)
 	}
 
-	return nil
+	if err := replaceSessions(ctx, tx, s.ID, s.Sessions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetByID retrieves a listing by ID
-func (r *ListingRepository) GetByID(id int) (*listing.Listing, error) {
+func (r *ListingRepository) GetByID(ctx context.Context, id int) (*listing.Listing, error) {
 	// Increment view count
 	updateQuery := `UPDATE listings SET view_count = view_count + 1 WHERE id = $1`
-	_, _ = r.db.Exec(updateQuery, id)
+	_, _ = r.db.ExecContext(ctx, updateQuery, id)
 
 	query := `
 		SELECT id, seller_id, title, description, event_type, status,
-			address_line1, address_line2, city, state, zip_code, latitude, longitude,
+			address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
 			start_date, end_date, event_hours,
 			listing_tier, payment_status, amount_paid,
 			view_count, featured, created_at, updated_at,
-			listing_type, external_id, external_source, external_url, last_scraped_at
+			listing_type, external_id, external_source, external_url, last_scraped_at,
+			COALESCE(scrape_status, ''), address_released_at, company_id
 		FROM listings
 		WHERE id = $1
 	`
 
 	s := &listing.Listing{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&s.ID, &s.SellerID, &s.Title, &s.Description, &s.EventType, &s.Status,
-		&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude,
+		&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude, &s.GeoPrecision,
 		&s.StartDate, &s.EndDate, &s.EventHours,
 		&s.ListingTier, &s.PaymentStatus, &s.AmountPaid,
 		&s.ViewCount, &s.Featured, &s.CreatedAt, &s.UpdatedAt,
 		&s.ListingType, &s.ExternalID, &s.ExternalSource, &s.ExternalURL, &s.LastScrapedAt,
+		&s.ScrapeStatus, &s.AddressReleasedAt, &s.CompanyID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -80,17 +96,27 @@ func (r *ListingRepository) GetByID(id int) (*listing.Listing, error) {
 		return nil, fmt.Errorf("failed to get sale: %w", err)
 	}
 
-	return s, nil
+	sales := []listing.Listing{*s}
+	if err := r.attachSessions(ctx, sales); err != nil {
+		return nil, err
+	}
+	if err := r.attachCompanies(ctx, sales); err != nil {
+		return nil, err
+	}
+
+	return &sales[0], nil
 }
 
 // GetAll retrieves sales with optional filters
-func (r *ListingRepository) GetAll(filters listing.ListingFilters) ([]listing.Listing, error) {
+func (r *ListingRepository) GetAll(ctx context.Context, filters listing.ListingFilters) ([]listing.Listing, error) {
 	query := `
-		SELECT id, seller_id, title, description, event_type, status,
-			address_line1, address_line2, city, state, zip_code, latitude, longitude,
+		SELECT id, seller_id, title, description,
+			COALESCE(event_type, ''), COALESCE(status, ''),
+			address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
 			start_date, end_date, event_hours,
-			listing_tier, payment_status, amount_paid,
-			view_count, featured, created_at, updated_at
+			COALESCE(listing_tier, ''), COALESCE(payment_status, ''), amount_paid,
+			view_count, featured, created_at, updated_at,
+			listing_type, external_id, external_source, external_url, last_scraped_at, company_id
 		FROM listings
 		WHERE 1=1
 	`
@@ -114,14 +140,21 @@ func (r *ListingRepository) GetAll(filters listing.ListingFilters) ([]listing.Li
 		argPos++
 	}
 	if filters.EventType != "" {
-		query += fmt.Sprintf(" AND event_type = $%d", argPos)
+		// Scraped sales whose source doesn't say what kind of sale it is still match
+		query += fmt.Sprintf(" AND (event_type = $%d OR (listing_type = 'external' AND event_type IS NULL))", argPos)
 		args = append(args, filters.EventType)
 		argPos++
 	}
 	if filters.Status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argPos)
+		if filters.IncludeExternal {
+			query += fmt.Sprintf(" AND (listing_type = 'external' OR status = $%d)", argPos)
+		} else {
+			query += fmt.Sprintf(" AND status = $%d", argPos)
+		}
 		args = append(args, filters.Status)
 		argPos++
+	} else if !filters.IncludeExternal {
+		query += " AND listing_type = 'owned'"
 	}
 	if filters.Featured != nil {
 		query += fmt.Sprintf(" AND featured = $%d", argPos)
@@ -138,6 +171,17 @@ func (r *ListingRepository) GetAll(filters listing.ListingFilters) ([]listing.Li
 		args = append(args, *filters.EndDate)
 		argPos++
 	}
+	if filters.ExcludeEnded {
+		query += " AND end_date >= NOW()"
+	}
+	if filters.IncludeExternal {
+		query += " AND scrape_status IS DISTINCT FROM 'tombstoned'"
+	}
+	if filters.CompanyID != nil {
+		query += fmt.Sprintf(" AND company_id = $%d", argPos)
+		args = append(args, *filters.CompanyID)
+		argPos++
+	}
 
 	// Order by featured first, then by start date
 	query += " ORDER BY featured DESC, start_date DESC"
@@ -146,7 +190,7 @@ func (r *ListingRepository) GetAll(filters listing.ListingFilters) ([]listing.Li
 	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argPos, argPos+1)
 	args = append(args, filters.Limit, filters.Offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query sales: %w", err)
 	}
@@ -157,25 +201,158 @@ func (r *ListingRepository) GetAll(filters listing.ListingFilters) ([]listing.Li
 		s := listing.Listing{}
 		err := rows.Scan(
 			&s.ID, &s.SellerID, &s.Title, &s.Description, &s.EventType, &s.Status,
-			&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude,
+			&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude, &s.GeoPrecision,
 			&s.StartDate, &s.EndDate, &s.EventHours,
 			&s.ListingTier, &s.PaymentStatus, &s.AmountPaid,
 			&s.ViewCount, &s.Featured, &s.CreatedAt, &s.UpdatedAt,
+			&s.ListingType, &s.ExternalID, &s.ExternalSource, &s.ExternalURL, &s.LastScrapedAt, &s.CompanyID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan listing: %w", err)
 		}
 		sales = append(sales, s)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sales: %w", err)
+	}
+
+	if err := r.attachSessions(ctx, sales); err != nil {
+		return nil, err
+	}
+	if err := r.attachCompanies(ctx, sales); err != nil {
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+// GetWithinRadius retrieves listings within filters.RadiusMiles of filters.Latitude/Longitude.
+// A lat/lng bounding box narrows the candidates (index-friendly) before the exact haversine distance.
+func (r *ListingRepository) GetWithinRadius(ctx context.Context, filters listing.ListingFilters) ([]listing.Listing, error) {
+	if !filters.HasGeo() {
+		return nil, fmt.Errorf("radius search requires latitude and longitude")
+	}
+
+	lat, lng := *filters.Latitude, *filters.Longitude
+	minLat, maxLat, minLng, maxLng := listing.BoundingBox(lat, lng, filters.RadiusMiles)
+
+	query := `
+		SELECT * FROM (
+			SELECT id, seller_id, title, description,
+				COALESCE(event_type, ''), COALESCE(status, ''),
+				address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
+				start_date, end_date, event_hours,
+				COALESCE(listing_tier, ''), COALESCE(payment_status, ''), amount_paid,
+				view_count, featured, created_at, updated_at,
+				listing_type, external_id, external_source, external_url, last_scraped_at, company_id,
+				2 * $1 * ASIN(SQRT(
+					POWER(SIN(RADIANS(latitude::float8 - $2) / 2), 2) +
+					COS(RADIANS($2)) * COS(RADIANS(latitude::float8)) *
+					POWER(SIN(RADIANS(longitude::float8 - $3) / 2), 2)
+				)) AS distance_miles
+			FROM listings
+			WHERE latitude BETWEEN $4 AND $5
+				AND longitude BETWEEN $6 AND $7
+				AND scrape_status IS DISTINCT FROM 'tombstoned'
+	`
+	args := []interface{}{listing.EarthRadiusMiles, lat, lng, minLat, maxLat, minLng, maxLng}
+	argPos := 8
+
+	// Apply filters
+	if filters.Status != "" {
+		if filters.IncludeExternal {
+			query += fmt.Sprintf(" AND (listing_type = 'external' OR status = $%d)", argPos)
+		} else {
+			query += fmt.Sprintf(" AND status = $%d", argPos)
+		}
+		args = append(args, filters.Status)
+		argPos++
+	} else if !filters.IncludeExternal {
+		query += " AND listing_type = 'owned'"
+	}
+	if filters.EventType != "" {
+		// Scraped sales whose source doesn't say what kind of sale it is still match
+		query += fmt.Sprintf(" AND (event_type = $%d OR (listing_type = 'external' AND event_type IS NULL))", argPos)
+		args = append(args, filters.EventType)
+		argPos++
+	}
+	if filters.Featured != nil {
+		query += fmt.Sprintf(" AND featured = $%d", argPos)
+		args = append(args, *filters.Featured)
+		argPos++
+	}
+	if filters.StartDate != nil {
+		query += fmt.Sprintf(" AND start_date >= $%d", argPos)
+		args = append(args, *filters.StartDate)
+		argPos++
+	}
+	if filters.EndDate != nil {
+		query += fmt.Sprintf(" AND end_date <= $%d", argPos)
+		args = append(args, *filters.EndDate)
+		argPos++
+	}
+	if filters.ExcludeEnded {
+		query += " AND end_date >= NOW()"
+	}
+
+	query += fmt.Sprintf(") nearby WHERE distance_miles <= $%d", argPos)
+	args = append(args, filters.RadiusMiles)
+	argPos++
+
+	if filters.SortBy == "distance" {
+		query += " ORDER BY distance_miles ASC, start_date ASC"
+	} else {
+		query += " ORDER BY featured DESC, start_date DESC"
+	}
+
+	// Pagination
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argPos, argPos+1)
+	args = append(args, filters.Limit, filters.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sales by radius: %w", err)
+	}
+	defer rows.Close()
+
+	sales := []listing.Listing{}
+	for rows.Next() {
+		s := listing.Listing{}
+		var distance float64
+		err := rows.Scan(
+			&s.ID, &s.SellerID, &s.Title, &s.Description, &s.EventType, &s.Status,
+			&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude, &s.GeoPrecision,
+			&s.StartDate, &s.EndDate, &s.EventHours,
+			&s.ListingTier, &s.PaymentStatus, &s.AmountPaid,
+			&s.ViewCount, &s.Featured, &s.CreatedAt, &s.UpdatedAt,
+			&s.ListingType, &s.ExternalID, &s.ExternalSource, &s.ExternalURL, &s.LastScrapedAt, &s.CompanyID,
+			&distance,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan listing: %w", err)
+		}
+		s.DistanceMiles = &distance
+		sales = append(sales, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate sales by radius: %w", err)
+	}
+
+	if err := r.attachSessions(ctx, sales); err != nil {
+		return nil, err
+	}
+	if err := r.attachCompanies(ctx, sales); err != nil {
+		return nil, err
+	}
 
 	return sales, nil
 }
 
 // GetBySellerID retrieves all sales for a seller
-func (r *ListingRepository) GetBySellerID(sellerID int) ([]listing.Listing, error) {
+func (r *ListingRepository) GetBySellerID(ctx context.Context, sellerID int) ([]listing.Listing, error) {
 	query := `
 		SELECT id, seller_id, title, description, event_type, status,
-			address_line1, address_line2, city, state, zip_code, latitude, longitude,
+			address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
 			start_date, end_date, event_hours,
 			listing_tier, payment_status, amount_paid,
 			view_count, featured, created_at, updated_at
@@ -184,7 +361,7 @@ func (r *ListingRepository) GetBySellerID(sellerID int) ([]listing.Listing, erro
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query, sellerID)
+	rows, err := r.db.QueryContext(ctx, query, sellerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query sales by seller: %w", err)
 	}
@@ -195,7 +372,7 @@ func (r *ListingRepository) GetBySellerID(sellerID int) ([]listing.Listing, erro
 		s := listing.Listing{}
 		err := rows.Scan(
 			&s.ID, &s.SellerID, &s.Title, &s.Description, &s.EventType, &s.Status,
-			&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude,
+			&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude, &s.GeoPrecision,
 			&s.StartDate, &s.EndDate, &s.EventHours,
 			&s.ListingTier, &s.PaymentStatus, &s.AmountPaid,
 			&s.ViewCount, &s.Featured, &s.CreatedAt, &s.UpdatedAt,
@@ -206,27 +383,37 @@ func (r *ListingRepository) GetBySellerID(sellerID int) ([]listing.Listing, erro
 		sales = append(sales, s)
 	}
 
+	if err := r.attachSessions(ctx, sales); err != nil {
+		return nil, err
+	}
+
 	return sales, nil
 }
 
-// Update updates an existing sale
-func (r *ListingRepository) Update(s *listing.Listing) error {
+// Update updates an existing sale and replaces its sessions in one transaction
+func (r *ListingRepository) Update(ctx context.Context, s *listing.Listing) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE listings SET
 			title = $1, description = $2, event_type = $3, status = $4,
 			address_line1 = $5, address_line2 = $6, city = $7, state = $8, zip_code = $9,
-			latitude = $10, longitude = $11,
-			start_date = $12, end_date = $13, event_hours = $14,
-			listing_tier = $15, payment_status = $16, amount_paid = $17,
-			featured = $18, updated_at = $19
-		WHERE id = $20
+			latitude = $10, longitude = $11, geo_precision = $12,
+			start_date = $13, end_date = $14, event_hours = $15,
+			listing_tier = $16, payment_status = $17, amount_paid = $18,
+			featured = $19, updated_at = $20
+		WHERE id = $21
 	`
 
-	result, err := r.db.Exec(
+	result, err := tx.ExecContext(ctx,
 		query,
 		s.Title, s.Description, s.EventType, s.Status,
 		s.AddressLine1, s.AddressLine2, s.City, s.State, s.ZipCode,
-		s.Latitude, s.Longitude,
+		s.Latitude, s.Longitude, s.GeoPrecision,
 		s.StartDate, s.EndDate, s.EventHours,
 		s.ListingTier, s.PaymentStatus, s.AmountPaid,
 		s.Featured, s.UpdatedAt,
@@ -246,14 +433,18 @@ func (r *ListingRepository) Update(s *listing.Listing) error {
 		return fmt.Errorf("listing not found")
 	}
 
-	return nil
+	if err := replaceSessions(ctx, tx, s.ID, s.Sessions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // Delete deletes a sale
-func (r *ListingRepository) Delete(id int) error {
+func (r *ListingRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM listings WHERE id = $1`
 
-	result, err := r.db.Exec(query, id)
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete listing: %w", err)
 	}
@@ -271,21 +462,21 @@ func (r *ListingRepository) Delete(id int) error {
 }
 
 // IncrementViewCount increments the view count for a sale
-func (r *ListingRepository) IncrementViewCount(id int) error {
+func (r *ListingRepository) IncrementViewCount(ctx context.Context, id int) error {
 	query := `UPDATE listings SET view_count = view_count + 1 WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
 // AddImage adds an image to a listing
-func (r *ListingRepository) AddImage(img *listing.ListingImage) error {
+func (r *ListingRepository) AddImage(ctx context.Context, img *listing.ListingImage) error {
 	query := `
 		INSERT INTO listing_images (listing_id, image_url, thumbnail_url, is_primary, display_order, uploaded_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id
 	`
 
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		query,
 		img.ListingID, img.ImageURL, img.ThumbnailURL, img.IsPrimary, img.DisplayOrder, img.UploadedAt,
 	).Scan(&img.ID)
@@ -298,7 +489,7 @@ func (r *ListingRepository) AddImage(img *listing.ListingImage) error {
 }
 
 // GetImagesByListingID retrieves all images for a listing
-func (r *ListingRepository) GetImagesByListingID(listingID int) ([]listing.ListingImage, error) {
+func (r *ListingRepository) GetImagesByListingID(ctx context.Context, listingID int) ([]listing.ListingImage, error) {
 	query := `
 		SELECT id, listing_id, image_url, thumbnail_url, is_primary, display_order, uploaded_at
 		FROM listing_images
@@ -306,7 +497,7 @@ func (r *ListingRepository) GetImagesByListingID(listingID int) ([]listing.Listi
 		ORDER BY is_primary DESC, display_order ASC
 	`
 
-	rows, err := r.db.Query(query, listingID)
+	rows, err := r.db.QueryContext(ctx, query, listingID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query images: %w", err)
 	}
@@ -329,10 +520,10 @@ func (r *ListingRepository) GetImagesByListingID(listingID int) ([]listing.Listi
 }
 
 // DeleteImage deletes an image
-func (r *ListingRepository) DeleteImage(imageID int) error {
+func (r *ListingRepository) DeleteImage(ctx context.Context, imageID int) error {
 	query := `DELETE FROM listing_images WHERE id = $1`
 
-	result, err := r.db.Exec(query, imageID)
+	result, err := r.db.ExecContext(ctx, query, imageID)
 	if err != nil {
 		return fmt.Errorf("failed to delete image: %w", err)
 	}
@@ -350,22 +541,22 @@ func (r *ListingRepository) DeleteImage(imageID int) error {
 }
 
 // SetPrimaryImage sets an image as primary and unsets all others for that listing
-func (r *ListingRepository) SetPrimaryImage(imageID int, listingID int) error {
+func (r *ListingRepository) SetPrimaryImage(ctx context.Context, imageID int, listingID int) error {
 	// Start a transaction
-	tx, err := r.db.Begin()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback()
 
 	// Unset all primary images for this sale
-	_, err = tx.Exec(`UPDATE listing_images SET is_primary = false WHERE listing_id = $1`, listingID)
+	_, err = tx.ExecContext(ctx, `UPDATE listing_images SET is_primary = false WHERE listing_id = $1`, listingID)
 	if err != nil {
 		return fmt.Errorf("failed to unset primary images: %w", err)
 	}
 
 	// Set the new primary image
-	result, err := tx.Exec(
+	result, err := tx.ExecContext(ctx,
 		`UPDATE listing_images SET is_primary = true WHERE id = $1 AND listing_id = $2`,
 		imageID, listingID,
 	)
@@ -385,16 +576,431 @@ func (r *ListingRepository) SetPrimaryImage(imageID int, listingID int) error {
 	return tx.Commit()
 }
 
-// UpsertExternalSale inserts or updates an external sale (uses external_id for conflict detection)
-func (r *ListingRepository) UpsertExternalSale(s *listing.Listing) error {
+// ReplaceExternalImages replaces a scraped listing's photos with the source's current ones.
+// The first URL becomes the primary image.
+func (r *ListingRepository) ReplaceExternalImages(ctx context.Context, listingID int, imageURLs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM listing_images WHERE listing_id = $1`, listingID); err != nil {
+		return fmt.Errorf("failed to clear images: %w", err)
+	}
+
+	for i, imageURL := range imageURLs {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO listing_images (listing_id, image_url, is_primary, display_order, uploaded_at)
+			VALUES ($1, $2, $3, $4, NOW())
+		`, listingID, imageURL, i == 0, i)
+		if err != nil {
+			return fmt.Errorf("failed to insert image: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceSessions replaces all sessions (structured open hours) for a listing
+func (r *ListingRepository) ReplaceSessions(ctx context.Context, listingID int, sessions []listing.Session) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceSessions(ctx, tx, listingID, sessions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceSessions rewrites a listing's sessions inside the caller's transaction
+func replaceSessions(ctx context.Context, tx *sql.Tx, listingID int, sessions []listing.Session) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM listing_sessions WHERE listing_id = $1`, listingID); err != nil {
+		return fmt.Errorf("failed to clear sessions: %w", err)
+	}
+
+	for _, session := range sessions {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO listing_sessions (listing_id, starts_at, ends_at) VALUES ($1, $2, $3)`,
+			listingID, session.Start, session.End,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert session: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// attachImages loads images for a batch of listings in one query
+func (r *ListingRepository) attachImages(ctx context.Context, sales []listing.Listing) error {
+	if len(sales) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(sales))
+	byID := make(map[int]int, len(sales)) // listing ID -> index in sales
+	for i, s := range sales {
+		ids[i] = int64(s.ID)
+		byID[s.ID] = i
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, listing_id, image_url, thumbnail_url, is_primary, display_order, uploaded_at
+		FROM listing_images
+		WHERE listing_id = ANY($1)
+		ORDER BY is_primary DESC, display_order ASC
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query images: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		img := listing.ListingImage{}
+		err := rows.Scan(
+			&img.ID, &img.ListingID, &img.ImageURL, &img.ThumbnailURL,
+			&img.IsPrimary, &img.DisplayOrder, &img.UploadedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan image: %w", err)
+		}
+		if i, ok := byID[img.ListingID]; ok {
+			sales[i].Images = append(sales[i].Images, img)
+		}
+	}
+
+	return rows.Err()
+}
+
+// attachSessions loads sessions for a batch of listings in one query
+func (r *ListingRepository) attachSessions(ctx context.Context, sales []listing.Listing) error {
+	if len(sales) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(sales))
+	byID := make(map[int]int, len(sales)) // listing ID -> index in sales
+	for i, s := range sales {
+		ids[i] = int64(s.ID)
+		byID[s.ID] = i
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT listing_id, starts_at, ends_at
+		FROM listing_sessions
+		WHERE listing_id = ANY($1)
+		ORDER BY starts_at ASC
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var listingID int
+		var session listing.Session
+		if err := rows.Scan(&listingID, &session.Start, &session.End); err != nil {
+			return fmt.Errorf("failed to scan session: %w", err)
+		}
+		if i, ok := byID[listingID]; ok {
+			sales[i].Sessions = append(sales[i].Sessions, session)
+		}
+	}
+
+	return rows.Err()
+}
+
+// attachCompanies loads the companies running a batch of listings in one query
+func (r *ListingRepository) attachCompanies(ctx context.Context, sales []listing.Listing) error {
+	var ids []int64
+	for _, s := range sales {
+		if s.CompanyID != nil {
+			ids = append(ids, int64(*s.CompanyID))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), COALESCE(website, ''), COALESCE(profile_url, '')
+		FROM companies
+		WHERE id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query companies: %w", err)
+	}
+	defer rows.Close()
+
+	companies := make(map[int]*listing.CompanyContact)
+	for rows.Next() {
+		c := &listing.CompanyContact{}
+		if err := rows.Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.Website, &c.ProfileURL); err != nil {
+			return fmt.Errorf("failed to scan company: %w", err)
+		}
+		companies[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range sales {
+		if sales[i].CompanyID != nil {
+			sales[i].Company = companies[*sales[i].CompanyID]
+		}
+	}
+	return nil
+}
+
+// UpsertExternalSale inserts or updates an external sale (uses external_id for conflict detection).
+// A sale whose content hash hasn't changed only has last_scraped_at bumped; a changed sale gets a revision.
+// The hash covers sessions and images, but the caller writes those (ReplaceSessions, ReplaceExternalImages).
+func (r *ListingRepository) UpsertExternalSale(ctx context.Context, s *listing.Listing) (listing.UpsertResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the existing row (if any) so the comparison and write are atomic
+	var existingID int
+	var existingHash sql.NullString
+	stored := listing.Listing{}
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, content_hash, company_id, COALESCE(event_type, '')
+		FROM listings
+		WHERE external_id = $1
+		FOR UPDATE
+	`, s.ExternalID).Scan(&existingID, &existingHash, &stored.CompanyID, &stored.EventType)
+	found := err == nil
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to load external listing: %w", err)
+	}
+
+	// What this scrape didn't report is kept, so it's part of the content compared
+	if found {
+		if len(s.Images) == 0 {
+			if stored.Images, err = storedImages(ctx, tx, existingID); err != nil {
+				return "", err
+			}
+		}
+		s.KeepStored(&stored)
+	}
+
+	hash := s.ContentHash()
+	existing := listing.Listing{}
+	var result listing.UpsertResult
+	switch {
+	case !found:
+		result = listing.UpsertInserted
+	case existingHash.Valid && existingHash.String == hash:
+		result = listing.UpsertUnchanged
+	default:
+		// Hash differs or predates the current content fields: diff the stored content
+		err = tx.QueryRowContext(ctx, `
+			SELECT id, external_url, title, description,
+				address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
+				start_date, end_date, event_hours, company_id, COALESCE(event_type, '')
+			FROM listings
+			WHERE id = $1
+		`, existingID).Scan(
+			&existing.ID, &existing.ExternalURL, &existing.Title, &existing.Description,
+			&existing.AddressLine1, &existing.AddressLine2, &existing.City, &existing.State, &existing.ZipCode, &existing.Latitude, &existing.Longitude, &existing.GeoPrecision,
+			&existing.StartDate, &existing.EndDate, &existing.EventHours, &existing.CompanyID, &existing.EventType,
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to load external listing: %w", err)
+		}
+		if existing.Sessions, err = storedSessions(ctx, tx, existingID); err != nil {
+			return "", err
+		}
+		if existing.Images, err = storedImages(ctx, tx, existingID); err != nil {
+			return "", err
+		}
+		if existing.SameContent(s) {
+			result = listing.UpsertUnchanged
+		} else {
+			result = listing.UpsertUpdated
+		}
+	}
+
+	if result == listing.UpsertUnchanged {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE listings SET
+				last_scraped_at = $1, scrape_status = 'active', missed_scrapes = 0,
+				scrape_city = $2, scrape_state = $3, content_hash = $4
+			WHERE id = $5
+		`, s.LastScrapedAt, s.ScrapeCity, s.ScrapeState, hash, existingID)
+		if err != nil {
+			return "", fmt.Errorf("failed to touch external listing: %w", err)
+		}
+		s.ID = existingID
+	} else if err := r.upsertExternalSale(ctx, tx, s, hash); err != nil {
+		return "", err
+	}
+
+	if result == listing.UpsertUpdated {
+		if err := r.recordRevision(ctx, tx, s.ID, listing.DiffListings(&existing, s)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit external listing: %w", err)
+	}
+
+	return result, nil
+}
+
+// storedSessions loads a listing's sessions inside an upsert's transaction
+func storedSessions(ctx context.Context, tx *sql.Tx, listingID int) ([]listing.Session, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT starts_at, ends_at
+		FROM listing_sessions
+		WHERE listing_id = $1
+		ORDER BY starts_at ASC
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []listing.Session
+	for rows.Next() {
+		var session listing.Session
+		if err := rows.Scan(&session.Start, &session.End); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// storedImages loads a listing's image URLs inside an upsert's transaction, primary first
+func storedImages(ctx context.Context, tx *sql.Tx, listingID int) ([]listing.ListingImage, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT image_url, is_primary, display_order
+		FROM listing_images
+		WHERE listing_id = $1
+		ORDER BY is_primary DESC, display_order ASC
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query images: %w", err)
+	}
+	defer rows.Close()
+
+	var images []listing.ListingImage
+	for rows.Next() {
+		image := listing.ListingImage{ListingID: listingID}
+		if err := rows.Scan(&image.ImageURL, &image.IsPrimary, &image.DisplayOrder); err != nil {
+			return nil, fmt.Errorf("failed to scan image: %w", err)
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+// recordRevision stores a field-level diff and stamps address_released_at the first time
+// a withheld address is published
+func (r *ListingRepository) recordRevision(ctx context.Context, tx *sql.Tx, listingID int, changes []listing.FieldChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO listing_revisions (listing_id, changed_at, changes)
+		VALUES ($1, NOW(), $2)
+	`, listingID, changesJSON)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+
+	if listing.AddressReleased(changes) {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE listings SET address_released_at = NOW()
+			WHERE id = $1 AND address_released_at IS NULL
+		`, listingID)
+		if err != nil {
+			return fmt.Errorf("failed to mark address released: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetHistory returns a listing's revisions, oldest first
+func (r *ListingRepository) GetHistory(ctx context.Context, listingID int) (*listing.ListingHistory, error) {
+	history := &listing.ListingHistory{ListingID: listingID, Revisions: []listing.Revision{}}
+
+	err := r.db.QueryRowContext(ctx, `SELECT address_released_at FROM listings WHERE id = $1`, listingID).Scan(&history.AddressReleasedAt)
+	if err == sql.ErrNoRows {
+		return nil, listing.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, listing_id, changed_at, changes
+		FROM listing_revisions
+		WHERE listing_id = $1
+		ORDER BY changed_at, id
+	`, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rev := listing.Revision{}
+		var changesJSON []byte
+		if err := rows.Scan(&rev.ID, &rev.ListingID, &rev.ChangedAt, &changesJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		if err := json.Unmarshal(changesJSON, &rev.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode revision %d: %w", rev.ID, err)
+		}
+		history.Revisions = append(history.Revisions, rev)
+	}
+
+	return history, rows.Err()
+}
+
+// GetIDByExternalID resolves a scraped listing's external ID (the ID aggregated results use)
+func (r *ListingRepository) GetIDByExternalID(ctx context.Context, externalID string) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM listings WHERE external_id = $1`, externalID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, listing.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get listing: %w", err)
+	}
+	return id, nil
+}
+
+// upsertExternalSale writes all of an external sale's content and its content hash
+func (r *ListingRepository) upsertExternalSale(ctx context.Context, tx *sql.Tx, s *listing.Listing, contentHash string) error {
 	query := `
 		INSERT INTO listings (
 			listing_type, external_id, external_source, external_url,
 			title, description,
-			address_line1, address_line2, city, state, zip_code, latitude, longitude,
+			address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
 			start_date, end_date, event_hours,
-			view_count, featured, last_scraped_at, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+			view_count, featured, last_scraped_at, created_at, updated_at,
+			scrape_status, missed_scrapes, scrape_city, scrape_state, content_hash, company_id, event_type
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22,
+			'active', 0, $23, $24, $25, $26, NULLIF($27, ''))
 		ON CONFLICT (external_id) DO UPDATE SET
 			title = EXCLUDED.title,
 			description = EXCLUDED.description,
@@ -405,21 +1011,30 @@ func (r *ListingRepository) UpsertExternalSale(s *listing.Listing) error {
 			zip_code = EXCLUDED.zip_code,
 			latitude = EXCLUDED.latitude,
 			longitude = EXCLUDED.longitude,
+			geo_precision = EXCLUDED.geo_precision,
 			start_date = EXCLUDED.start_date,
 			end_date = EXCLUDED.end_date,
 			event_hours = EXCLUDED.event_hours,
 			last_scraped_at = EXCLUDED.last_scraped_at,
-			updated_at = EXCLUDED.updated_at
+			updated_at = EXCLUDED.updated_at,
+			scrape_status = 'active',
+			missed_scrapes = 0,
+			scrape_city = EXCLUDED.scrape_city,
+			scrape_state = EXCLUDED.scrape_state,
+			content_hash = EXCLUDED.content_hash,
+			company_id = COALESCE(EXCLUDED.company_id, listings.company_id),
+			event_type = COALESCE(EXCLUDED.event_type, listings.event_type)
 		RETURNING id
 	`
 
-	err := r.db.QueryRow(
+	err := tx.QueryRowContext(ctx,
 		query,
 		s.ListingType, s.ExternalID, s.ExternalSource, s.ExternalURL,
 		s.Title, s.Description,
-		s.AddressLine1, s.AddressLine2, s.City, s.State, s.ZipCode, s.Latitude, s.Longitude,
+		s.AddressLine1, s.AddressLine2, s.City, s.State, s.ZipCode, s.Latitude, s.Longitude, s.GeoPrecision,
 		s.StartDate, s.EndDate, s.EventHours,
 		s.ViewCount, s.Featured, s.LastScrapedAt, s.CreatedAt, s.UpdatedAt,
+		s.ScrapeCity, s.ScrapeState, contentHash, s.CompanyID, s.EventType,
 	).Scan(&s.ID)
 
 	if err != nil {
@@ -430,21 +1045,24 @@ func (r *ListingRepository) UpsertExternalSale(s *listing.Listing) error {
 }
 
 // GetExternalSalesByLocation retrieves external sales for a city/state
-func (r *ListingRepository) GetExternalSalesByLocation(city, state string) ([]listing.Listing, error) {
+func (r *ListingRepository) GetExternalSalesByLocation(ctx context.Context, city, state string) ([]listing.Listing, error) {
 	query := `
 		SELECT id, listing_type, external_id, external_source, external_url,
 			title, description,
-			address_line1, address_line2, city, state, zip_code, latitude, longitude,
+			address_line1, address_line2, city, state, zip_code, latitude, longitude, geo_precision,
 			start_date, end_date, event_hours,
-			view_count, featured, last_scraped_at, created_at, updated_at
+			view_count, featured, last_scraped_at, created_at, updated_at,
+			address_released_at, company_id, COALESCE(event_type, '')
 		FROM listings
 		WHERE listing_type = 'external'
 			AND LOWER(city) = LOWER($1)
 			AND LOWER(state) = LOWER($2)
+			AND scrape_status IS DISTINCT FROM 'tombstoned'
+			AND end_date >= NOW()
 		ORDER BY start_date DESC
 	`
 
-	rows, err := r.db.Query(query, city, state)
+	rows, err := r.db.QueryContext(ctx, query, city, state)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query external sales: %w", err)
 	}
@@ -456,9 +1074,10 @@ func (r *ListingRepository) GetExternalSalesByLocation(city, state string) ([]li
 		err := rows.Scan(
 			&s.ID, &s.ListingType, &s.ExternalID, &s.ExternalSource, &s.ExternalURL,
 			&s.Title, &s.Description,
-			&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude,
+			&s.AddressLine1, &s.AddressLine2, &s.City, &s.State, &s.ZipCode, &s.Latitude, &s.Longitude, &s.GeoPrecision,
 			&s.StartDate, &s.EndDate, &s.EventHours,
 			&s.ViewCount, &s.Featured, &s.LastScrapedAt, &s.CreatedAt, &s.UpdatedAt,
+			&s.AddressReleasedAt, &s.CompanyID, &s.EventType,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan external sale: %w", err)
@@ -466,11 +1085,21 @@ func (r *ListingRepository) GetExternalSalesByLocation(city, state string) ([]li
 		sales = append(sales, s)
 	}
 
+	if err := r.attachSessions(ctx, sales); err != nil {
+		return nil, err
+	}
+	if err := r.attachImages(ctx, sales); err != nil {
+		return nil, err
+	}
+	if err := r.attachCompanies(ctx, sales); err != nil {
+		return nil, err
+	}
+
 	return sales, nil
 }
 
 // GetLastScrapedTime retrieves the last scraped timestamp for a location
-func (r *ListingRepository) GetLastScrapedTime(city, state string) (*listing.Listing, error) {
+func (r *ListingRepository) GetLastScrapedTime(ctx context.Context, city, state string) (*listing.Listing, error) {
 	query := `
 		SELECT id, last_scraped_at
 		FROM listings
@@ -482,7 +1111,7 @@ func (r *ListingRepository) GetLastScrapedTime(city, state string) (*listing.Lis
 	`
 
 	s := &listing.Listing{}
-	err := r.db.QueryRow(query, city, state).Scan(&s.ID, &s.LastScrapedAt)
+	err := r.db.QueryRowContext(ctx, query, city, state).Scan(&s.ID, &s.LastScrapedAt)
 
 	if err == sql.ErrNoRows {
 		// No external sales found for this location - needs initial scrape
@@ -494,3 +1123,42 @@ func (r *ListingRepository) GetLastScrapedTime(city, state string) (*listing.Lis
 
 	return s, nil
 }
+
+// MarkMissing flags a source's listings for a scrape location that weren't in seenExternalIDs.
+// Each miss increments missed_scrapes; at tombstoneAfter the listing is tombstoned.
+func (r *ListingRepository) MarkMissing(ctx context.Context, source, city, state string, seenExternalIDs []string, tombstoneAfter int) (listing.MissingResult, error) {
+	query := `
+		UPDATE listings SET
+			missed_scrapes = missed_scrapes + 1,
+			scrape_status = CASE WHEN missed_scrapes + 1 >= $5 THEN 'tombstoned' ELSE 'missing' END,
+			updated_at = NOW()
+		WHERE listing_type = 'external'
+			AND external_source = $1
+			AND LOWER(scrape_city) = LOWER($2)
+			AND UPPER(scrape_state) = UPPER($3)
+			AND scrape_status IS DISTINCT FROM 'tombstoned'
+			AND NOT (external_id = ANY($4))
+		RETURNING scrape_status
+	`
+
+	result := listing.MissingResult{}
+	rows, err := r.db.QueryContext(ctx, query, source, city, state, pq.Array(seenExternalIDs), tombstoneAfter)
+	if err != nil {
+		return result, fmt.Errorf("failed to mark missing listings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			return result, fmt.Errorf("failed to scan missing listing: %w", err)
+		}
+		if status == listing.ScrapeStatusTombstoned {
+			result.Tombstoned++
+		} else {
+			result.Missing++
+		}
+	}
+
+	return result, rows.Err()
+}
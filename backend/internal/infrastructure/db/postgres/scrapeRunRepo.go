@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+)
+
+// ScrapeRunRepository implements the scrape.RunRepository interface
+type ScrapeRunRepository struct {
+	db *sql.DB
+}
+
+// NewScrapeRunRepository creates a new scrape run repository
+func NewScrapeRunRepository(db *sql.DB) *ScrapeRunRepository {
+	return &ScrapeRunRepository{db: db}
+}
+
+const scrapeRunColumns = `id, source, city, state, trigger, started_at, finished_at,
+	http_status, rows_seen, listings_found,
+	inserted, updated, unchanged, failed, missing, tombstoned,
+	fill_rates, degraded, COALESCE(degraded_reason, ''), quarantined, error`
+
+// RecordRun stores a finished scrape run
+func (r *ScrapeRunRepository) RecordRun(ctx context.Context, run *scrape.Run) error {
+	var fillRates []byte
+	if run.FillRates != nil {
+		var err error
+		if fillRates, err = json.Marshal(run.FillRates); err != nil {
+			return fmt.Errorf("failed to encode fill rates: %w", err)
+		}
+	}
+
+	var degradedReason *string
+	if run.DegradedReason != "" {
+		degradedReason = &run.DegradedReason
+	}
+
+	query := `
+		INSERT INTO scrape_runs (
+			source, city, state, trigger, started_at, finished_at,
+			http_status, rows_seen, listings_found,
+			inserted, updated, unchanged, failed, missing, tombstoned,
+			fill_rates, degraded, degraded_reason, quarantined, error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx,
+		query,
+		run.Source, run.City, run.State, run.Trigger, run.StartedAt, run.FinishedAt,
+		run.HTTPStatus, run.RowsSeen, run.ListingsFound,
+		run.Inserted, run.Updated, run.Unchanged, run.Failed, run.Missing, run.Tombstoned,
+		fillRates, run.Degraded, degradedReason, run.Quarantined, run.Error,
+	).Scan(&run.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to record scrape run: %w", err)
+	}
+
+	return nil
+}
+
+// ListRuns retrieves the most recent scrape runs
+func (r *ScrapeRunRepository) ListRuns(ctx context.Context, limit int) ([]scrape.Run, error) {
+	query := `
+		SELECT ` + scrapeRunColumns + `
+		FROM scrape_runs
+		ORDER BY started_at DESC
+		LIMIT $1
+	`
+
+	return r.queryRuns(ctx, query, limit)
+}
+
+// RecentRunsBySource retrieves the latest runs of each source/location, newest first
+func (r *ScrapeRunRepository) RecentRunsBySource(ctx context.Context, perSource int) ([]scrape.Run, error) {
+	query := `
+		SELECT ` + scrapeRunColumns + `
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (
+				PARTITION BY source, LOWER(city), UPPER(state) ORDER BY started_at DESC
+			) AS rn
+			FROM scrape_runs
+		) ranked
+		WHERE rn <= $1
+		ORDER BY source, LOWER(city), UPPER(state), started_at DESC
+	`
+
+	return r.queryRuns(ctx, query, perSource)
+}
+
+// RecentRuns retrieves one source's latest runs for a location, newest first
+func (r *ScrapeRunRepository) RecentRuns(ctx context.Context, source, city, state string, limit int) ([]scrape.Run, error) {
+	query := `
+		SELECT ` + scrapeRunColumns + `
+		FROM scrape_runs
+		WHERE source = $1 AND LOWER(city) = LOWER($2) AND UPPER(state) = UPPER($3)
+		ORDER BY started_at DESC
+		LIMIT $4
+	`
+
+	return r.queryRuns(ctx, query, source, city, state, limit)
+}
+
+// QuarantineListings stores listings held back from a degraded run
+func (r *ScrapeRunRepository) QuarantineListings(ctx context.Context, listings []scrape.QuarantinedListing) error {
+	if len(listings) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO scrape_quarantine (run_id, source, external_id, listing, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare quarantine insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, q := range listings {
+		if _, err := stmt.ExecContext(ctx, q.RunID, q.Source, q.ExternalID, []byte(q.Listing)); err != nil {
+			return fmt.Errorf("failed to quarantine listing %s: %w", q.ExternalID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListQuarantined retrieves the listings held back from a run
+func (r *ScrapeRunRepository) ListQuarantined(ctx context.Context, runID int) ([]scrape.QuarantinedListing, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT run_id, source, external_id, listing, created_at
+		FROM scrape_quarantine
+		WHERE run_id = $1
+		ORDER BY id
+	`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quarantined listings: %w", err)
+	}
+	defer rows.Close()
+
+	listings := []scrape.QuarantinedListing{}
+	for rows.Next() {
+		q := scrape.QuarantinedListing{}
+		var listingJSON []byte
+		if err := rows.Scan(&q.RunID, &q.Source, &q.ExternalID, &listingJSON, &q.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan quarantined listing: %w", err)
+		}
+		q.Listing = listingJSON
+		listings = append(listings, q)
+	}
+
+	return listings, rows.Err()
+}
+
+func (r *ScrapeRunRepository) queryRuns(ctx context.Context, query string, args ...interface{}) ([]scrape.Run, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scrape runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []scrape.Run{}
+	for rows.Next() {
+		run := scrape.Run{}
+		var fillRates []byte
+		err := rows.Scan(
+			&run.ID, &run.Source, &run.City, &run.State, &run.Trigger, &run.StartedAt, &run.FinishedAt,
+			&run.HTTPStatus, &run.RowsSeen, &run.ListingsFound,
+			&run.Inserted, &run.Updated, &run.Unchanged, &run.Failed, &run.Missing, &run.Tombstoned,
+			&fillRates, &run.Degraded, &run.DegradedReason, &run.Quarantined, &run.Error,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scrape run: %w", err)
+		}
+		if fillRates != nil {
+			run.FillRates = &scrape.FillRates{}
+			if err := json.Unmarshal(fillRates, run.FillRates); err != nil {
+				return nil, fmt.Errorf("failed to decode fill rates of run %d: %w", run.ID, err)
+			}
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
@@ -0,0 +1,55 @@
+package geocode
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// Chain tries each geocoder in order and returns the first result
+type Chain []listing.Geocoder
+
+// Geocode implements listing.Geocoder
+func (c Chain) Geocode(req listing.GeocodeRequest) (*listing.GeocodeResult, error) {
+	var errs []error
+	for _, g := range c {
+		result, err := g.Geocode(req)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, listing.ErrNotGeocoded) {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(append([]error{listing.ErrNotGeocoded}, errs...)...)
+	}
+	return nil, listing.ErrNotGeocoded
+}
+
+// NewGeocoder builds the app geocoder from environment variables:
+// GEOCODER_URL (optional Nominatim-compatible service for street-level results)
+// and GEOCODER_USER_AGENT. The offline ZIP/city table is always the fallback.
+func NewGeocoder() (listing.Geocoder, error) {
+	zips, err := NewZipGeocoder()
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := os.Getenv("GEOCODER_URL")
+	if baseURL == "" {
+		log.Println("Geocoder: offline ZIP centroids only (set GEOCODER_URL for street-level results)")
+		return zips, nil
+	}
+
+	userAgent := os.Getenv("GEOCODER_USER_AGENT")
+	if userAgent == "" {
+		userAgent = "EstateSaleFinder.ai geocoder"
+	}
+
+	log.Printf("Geocoder: %s with offline ZIP centroid fallback", baseURL)
+	return Chain{NewHTTPGeocoder(baseURL, userAgent), zips}, nil
+}
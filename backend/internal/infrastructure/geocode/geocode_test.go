@@ -0,0 +1,60 @@
+package geocode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// TestZipGeocoder tests offline ZIP and city centroid lookups
+func TestZipGeocoder(t *testing.T) {
+	g, err := NewZipGeocoder()
+	require.NoError(t, err)
+
+	result, err := g.Geocode(listing.GeocodeRequest{City: "Beaverton", State: "OR", ZipCode: "97005-1234"})
+	require.NoError(t, err)
+	assert.Equal(t, listing.GeoPrecisionZip, result.Precision)
+	assert.InDelta(t, 45.49, result.Latitude, 0.01)
+
+	result, err = g.Geocode(listing.GeocodeRequest{City: "portland", State: "or"})
+	require.NoError(t, err)
+	assert.Equal(t, listing.GeoPrecisionCity, result.Precision)
+	assert.InDelta(t, 45.52, result.Latitude, 0.05)
+	assert.InDelta(t, -122.67, result.Longitude, 0.05)
+
+	_, err = g.Geocode(listing.GeocodeRequest{City: "Nowhere", State: "OR"})
+	assert.ErrorIs(t, err, listing.ErrNotGeocoded)
+}
+
+// TestChainFallsBackToZip tests that a street lookup miss falls back to the offline table
+func TestChainFallsBackToZip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		if r.URL.Query().Get("street") == "123 Main St" {
+			w.Write([]byte(`[{"lat":"45.5183","lon":"-122.6793"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	zips, err := NewZipGeocoder()
+	require.NoError(t, err)
+	remote := NewHTTPGeocoder(server.URL, "test-agent")
+	remote.minInterval = 0
+	chain := Chain{remote, zips}
+
+	result, err := chain.Geocode(listing.GeocodeRequest{Street: "123 Main St", City: "Portland", State: "OR", ZipCode: "97204"})
+	require.NoError(t, err)
+	assert.Equal(t, listing.GeoPrecisionRooftop, result.Precision)
+	assert.Equal(t, 45.5183, result.Latitude)
+
+	result, err = chain.Geocode(listing.GeocodeRequest{Street: "1 Unknown Rd", City: "Portland", State: "OR", ZipCode: "97204"})
+	require.NoError(t, err)
+	assert.Equal(t, listing.GeoPrecisionZip, result.Precision)
+}
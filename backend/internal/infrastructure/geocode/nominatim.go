@@ -0,0 +1,106 @@
+package geocode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// HTTPGeocoder geocodes street addresses against a Nominatim-compatible search API
+// (https://nominatim.org/release-docs/latest/api/Search/). Only street addresses
+// are looked up; anything coarser is left to the offline ZipGeocoder.
+type HTTPGeocoder struct {
+	baseURL     string
+	userAgent   string
+	client      *http.Client
+	minInterval time.Duration // Public Nominatim allows 1 request/second
+
+	mu       sync.Mutex
+	lastCall time.Time
+}
+
+// NewHTTPGeocoder creates a geocoder for a Nominatim-compatible base URL
+func NewHTTPGeocoder(baseURL, userAgent string) *HTTPGeocoder {
+	return &HTTPGeocoder{
+		baseURL:     baseURL,
+		userAgent:   userAgent,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		minInterval: time.Second,
+	}
+}
+
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+// Geocode implements listing.Geocoder
+func (g *HTTPGeocoder) Geocode(req listing.GeocodeRequest) (*listing.GeocodeResult, error) {
+	if req.Street == "" {
+		return nil, listing.ErrNotGeocoded
+	}
+
+	params := url.Values{}
+	params.Set("format", "json")
+	params.Set("limit", "1")
+	params.Set("countrycodes", "us")
+	params.Set("street", req.Street)
+	params.Set("city", req.City)
+	params.Set("state", req.State)
+	if req.ZipCode != "" {
+		params.Set("postalcode", req.ZipCode)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodGet, g.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create geocode request: %w", err)
+	}
+	httpReq.Header.Set("User-Agent", g.userAgent)
+
+	g.throttle()
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("geocode request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocode request returned status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode geocode response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, listing.ErrNotGeocoded
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q in geocode response", results[0].Lat)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q in geocode response", results[0].Lon)
+	}
+
+	return &listing.GeocodeResult{Latitude: lat, Longitude: lng, Precision: listing.GeoPrecisionRooftop}, nil
+}
+
+// throttle spaces requests at least minInterval apart
+func (g *HTTPGeocoder) throttle() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if wait := g.minInterval - time.Since(g.lastCall); wait > 0 {
+		time.Sleep(wait)
+	}
+	g.lastCall = time.Now()
+}
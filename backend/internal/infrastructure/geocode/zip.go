@@ -0,0 +1,97 @@
+package geocode
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// zipCentroids is a bundled table of approximate ZIP code centroids
+// (zip,city,state,latitude,longitude) covering the Portland metro and the
+// Oregon / SW Washington cities our sources list sales in
+//
+//go:embed zip_centroids.csv
+var zipCentroids []byte
+
+type point struct {
+	lat, lng float64
+}
+
+// ZipGeocoder is an offline geocoder backed by the bundled ZIP centroid table.
+// It resolves to ZIP precision when the ZIP is known, else to a city centroid
+// (the average of that city's ZIPs). It never returns rooftop precision.
+type ZipGeocoder struct {
+	zips   map[string]point
+	cities map[string]point // key: "city|STATE"
+}
+
+// NewZipGeocoder loads the bundled ZIP centroid table
+func NewZipGeocoder() (*ZipGeocoder, error) {
+	rows, err := csv.NewReader(bytes.NewReader(zipCentroids)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip centroids: %w", err)
+	}
+
+	g := &ZipGeocoder{
+		zips:   make(map[string]point),
+		cities: make(map[string]point),
+	}
+	sums := make(map[string]point)
+	counts := make(map[string]int)
+
+	for i, row := range rows {
+		if i == 0 {
+			continue // header
+		}
+		if len(row) != 5 {
+			return nil, fmt.Errorf("zip centroids line %d: expected 5 columns, got %d", i+1, len(row))
+		}
+		lat, err := strconv.ParseFloat(row[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("zip centroids line %d: invalid latitude: %w", i+1, err)
+		}
+		lng, err := strconv.ParseFloat(row[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("zip centroids line %d: invalid longitude: %w", i+1, err)
+		}
+
+		g.zips[row[0]] = point{lat, lng}
+
+		key := cityKey(row[1], row[2])
+		sums[key] = point{sums[key].lat + lat, sums[key].lng + lng}
+		counts[key]++
+	}
+
+	for key, sum := range sums {
+		n := float64(counts[key])
+		g.cities[key] = point{sum.lat / n, sum.lng / n}
+	}
+
+	return g, nil
+}
+
+// Geocode implements listing.Geocoder
+func (g *ZipGeocoder) Geocode(req listing.GeocodeRequest) (*listing.GeocodeResult, error) {
+	zip := strings.TrimSpace(req.ZipCode)
+	if len(zip) > 5 {
+		zip = zip[:5] // ZIP+4
+	}
+	if p, ok := g.zips[zip]; ok {
+		return &listing.GeocodeResult{Latitude: p.lat, Longitude: p.lng, Precision: listing.GeoPrecisionZip}, nil
+	}
+
+	if p, ok := g.cities[cityKey(req.City, req.State)]; ok {
+		return &listing.GeocodeResult{Latitude: p.lat, Longitude: p.lng, Precision: listing.GeoPrecisionCity}, nil
+	}
+
+	return nil, listing.ErrNotGeocoded
+}
+
+func cityKey(city, state string) string {
+	return strings.ToLower(strings.TrimSpace(city)) + "|" + strings.ToUpper(strings.TrimSpace(state))
+}
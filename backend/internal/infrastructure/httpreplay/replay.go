@@ -0,0 +1,132 @@
+package httpreplay
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Mode selects whether a Transport hits the network
+type Mode int
+
+const (
+	// ModeReplay serves responses from fixture files; a missing fixture is an error
+	ModeReplay Mode = iota
+
+	// ModeRecord fetches through Base and saves each response as a fixture
+	ModeRecord
+)
+
+// Transport is an http.RoundTripper that records responses to fixture files once
+// and replays them offline. Fixtures are raw HTTP responses (see FixtureName).
+type Transport struct {
+	Dir  string
+	Mode Mode
+	Base http.RoundTripper // Used when recording (http.DefaultTransport if nil)
+}
+
+// NewClient returns an http.Client that records to or replays from dir
+func NewClient(dir string, mode Mode) *http.Client {
+	return &http.Client{Transport: &Transport{Dir: dir, Mode: mode}}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(t.Dir, FixtureName(req))
+	if t.Mode == ModeRecord {
+		return t.record(req, path)
+	}
+	return replay(req, path)
+}
+
+// record fetches req and writes the response to path
+func (t *Transport) record(req *http.Request, path string) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for %s: %w", req.URL, err)
+	}
+
+	// Store the decoded body with a plain Content-Length so the fixture replays as-is
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.TransferEncoding = nil
+	resp.Uncompressed = false
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump response for %s: %w", req.URL, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture dir: %w", err)
+	}
+	if err := os.WriteFile(path, dump, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// replay reads the response for req from path
+func replay(req *http.Request, path string) (*http.Response, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no fixture for %s %s (expected %s; record it first)", req.Method, req.URL, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture: %w", err)
+	}
+	defer f.Close()
+
+	resp, err := http.ReadResponse(bufio.NewReader(f), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture %s: %w", path, err)
+	}
+
+	// Read the body now so the file can be closed
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture body %s: %w", path, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// FixtureName names a request's fixture file: a readable slug of the method, host and
+// path plus a hash of the full URL, e.g. "get_www.example.com_list.php_3f2a9c1d.http"
+func FixtureName(req *http.Request) string {
+	key := req.Method + " " + req.URL.String()
+	sum := sha1.Sum([]byte(key))
+
+	slug := strings.ToLower(req.Method + "_" + req.URL.Host + req.URL.Path)
+	slug = strings.Trim(unsafeChars.ReplaceAllString(slug, "_"), "_")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	return slug + "_" + hex.EncodeToString(sum[:4]) + ".http"
+}
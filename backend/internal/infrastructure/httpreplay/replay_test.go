@@ -0,0 +1,52 @@
+package httpreplay
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordThenReplay tests that a recorded response replays without the server
+func TestRecordThenReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("<p>" + r.URL.Query().Get("page") + "</p>"))
+	}))
+	dir := t.TempDir()
+
+	recorder := NewClient(dir, ModeRecord)
+	resp, err := recorder.Get(srv.URL + "/list?page=1")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "<p>1</p>", string(body))
+
+	srv.Close()
+
+	player := NewClient(dir, ModeReplay)
+	resp, err = player.Get(srv.URL + "/list?page=1")
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "<p>1</p>", string(body))
+	assert.Equal(t, `"v1"`, resp.Header.Get("ETag"))
+
+	// Other URLs were never recorded
+	_, err = player.Get(srv.URL + "/list?page=2")
+	assert.ErrorContains(t, err, "no fixture")
+}
+
+// TestFixtureName tests that names are readable and unique per URL
+func TestFixtureName(t *testing.T) {
+	a, _ := http.NewRequest(http.MethodGet, "https://www.example.com/all_sales_list.php?regionsshow=1,2", nil)
+	b, _ := http.NewRequest(http.MethodGet, "https://www.example.com/all_sales_list.php?regionsshow=3", nil)
+
+	assert.Regexp(t, `^get_www\.example\.com_all_sales_list\.php_[0-9a-f]{8}\.http$`, FixtureName(a))
+	assert.NotEqual(t, FixtureName(a), FixtureName(b))
+}
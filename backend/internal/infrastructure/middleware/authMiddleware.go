@@ -56,7 +56,7 @@ func FirebaseMiddleware(next http.Handler) http.Handler {
 		}
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		token, err := firebaseAuth.VerifyIDToken(context.Background(), tokenStr)
+		token, err := firebaseAuth.VerifyIDToken(r.Context(), tokenStr)
 		if err != nil {
 			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
 			return
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultRequestTimeout is used when REQUEST_TIMEOUT is not set
+const DefaultRequestTimeout = 60 * time.Second
+
+// TimeoutMiddleware gives each request's context a deadline. The context is also
+// cancelled when the client disconnects, which stops the request's queries and scrapes.
+func TimeoutMiddleware(timeout time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults (SCRAPE_BREAKER_FAILURES, SCRAPE_BREAKER_COOLDOWN)
+const (
+	DefaultBreakerFailures = 3
+	DefaultBreakerCooldown = 5 * time.Minute
+)
+
+// ErrCircuitOpen is returned for a source that's skipped because it keeps failing
+var ErrCircuitOpen = errors.New("circuit open after repeated failures")
+
+// Breaker states
+const (
+	breakerClosed   = "closed"    // Requests go through
+	breakerOpen     = "open"      // Requests are skipped until the cooldown ends
+	breakerHalfOpen = "half_open" // One probe request is in flight
+)
+
+// circuitBreaker stops calling a source after repeated failures. Once the cooldown
+// has passed, one probe is let through: success closes the circuit, failure reopens it.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	failures int // Consecutive failures while closed
+	state    string
+	openedAt time.Time
+
+	maxFailures int
+	cooldown    time.Duration
+	now         func() time.Time
+}
+
+func newCircuitBreaker(maxFailures int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{state: breakerClosed, maxFailures: maxFailures, cooldown: cooldown, now: time.Now}
+}
+
+// allow reports whether a request may be made, moving an open circuit to half-open
+// once its cooldown has passed
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false // Only the probe
+	default:
+		return true
+	}
+}
+
+// success records a successful request, closing the circuit
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// failure records a failed request. It returns true if this opened the circuit.
+func (b *circuitBreaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.maxFailures {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		b.failures = 0
+		return true
+	}
+	return false
+}
+
+// abandon records a request the caller cancelled. A half-open probe goes back to open
+// with its cooldown already over, so the next request probes again.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
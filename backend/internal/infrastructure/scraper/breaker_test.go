@@ -0,0 +1,37 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCircuitBreaker tests opening, the cooldown, and half-open probes
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(3, 5*time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.False(t, b.failure())
+	b.success() // Resets the count
+	assert.False(t, b.failure())
+	assert.False(t, b.failure())
+	assert.True(t, b.failure(), "third failure in a row opens")
+	assert.False(t, b.allow())
+
+	// Cooldown over: one probe goes through, the rest wait on it
+	now = now.Add(5 * time.Minute)
+	assert.True(t, b.allow())
+	assert.False(t, b.allow())
+
+	// A failed probe reopens straight away
+	assert.True(t, b.failure())
+	assert.False(t, b.allow())
+
+	now = now.Add(5 * time.Minute)
+	assert.True(t, b.allow())
+	b.success()
+	assert.True(t, b.allow())
+	assert.True(t, b.allow())
+}
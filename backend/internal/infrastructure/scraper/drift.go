@@ -0,0 +1,90 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+)
+
+// fillRates measures how many of a fetch's sale rows yielded each field. Rows that
+// couldn't be parsed (SkippedIDs) count as rows without a date.
+func fillRates(result *FetchResult) scrape.FillRates {
+	rates := scrape.FillRates{Rows: len(result.Listings) + len(result.SkippedIDs)}
+	if rates.Rows == 0 {
+		return rates
+	}
+
+	var date, street, zip, city int
+	for _, l := range result.Listings {
+		if !l.StartDate.IsZero() {
+			date++
+		}
+		if !address.IsWithheld(l.Address) {
+			street++
+		}
+		if l.ZipCode != "" {
+			zip++
+		}
+		if l.City != "" {
+			city++
+		}
+	}
+
+	rows := float64(rates.Rows)
+	rates.Date = float64(date) / rows
+	rates.Address = float64(street) / rows
+	rates.ZipCode = float64(zip) / rows
+	rates.City = float64(city) / rows
+	return rates
+}
+
+// checkDrift sets the run's fill rates and compares them against the source's recent
+// healthy runs for the location. It returns why the run looks degraded, or "" if it
+// doesn't (or there's no baseline yet). Degraded runs don't count towards the baseline,
+// so once a source's recent history is all degraded its new output becomes the norm.
+func (s *ScraperService) checkDrift(ctx context.Context, run *scrape.Run, result *FetchResult) string {
+	rates := fillRates(result)
+	run.FillRates = &rates
+
+	if s.runs == nil || s.driftThreshold <= 0 {
+		return ""
+	}
+
+	history, err := s.runs.RecentRuns(ctx, run.Source, run.City, run.State, s.driftBaselineRuns*2)
+	if err != nil {
+		log.Printf("Warning: Failed to load %s runs for drift check: %v", run.Source, err)
+		return ""
+	}
+	baseline, ok := scrape.Baseline(history, s.driftBaselineRuns)
+	if !ok {
+		return ""
+	}
+
+	return strings.Join(rates.Drift(baseline, s.driftThreshold), ", ")
+}
+
+// quarantine stores a degraded run's listings for inspection instead of upserting them
+func (s *ScraperService) quarantine(ctx context.Context, run *scrape.Run, sales []listing.ScrapedListing) {
+	if s.runs == nil || run.ID == 0 {
+		return
+	}
+
+	held := make([]scrape.QuarantinedListing, 0, len(sales))
+	for _, sale := range sales {
+		data, err := json.Marshal(sale)
+		if err != nil {
+			log.Printf("Warning: Failed to encode quarantined sale %s: %v", sale.ExternalID, err)
+			continue
+		}
+		held = append(held, scrape.QuarantinedListing{RunID: run.ID, Source: run.Source, ExternalID: sale.ExternalID, Listing: data})
+	}
+
+	if err := s.runs.QuarantineListings(ctx, held); err != nil {
+		log.Printf("Warning: Failed to quarantine %s sales: %v", run.Source, err)
+	}
+}
@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// driftSales builds n sales, the first withAddress of which have a street address
+func driftSales(n, withAddress int) []listing.ScrapedListing {
+	start := time.Now().Add(24 * time.Hour)
+	sales := make([]listing.ScrapedListing, n)
+	for i := range sales {
+		sales[i] = listing.ScrapedListing{
+			ExternalID: fmt.Sprintf("sale-%d", i), Title: "Estate Sale", Address: "TBA",
+			City: "Portland", State: "OR", ZipCode: "97202", StartDate: start, EndDate: start,
+		}
+		if i < withAddress {
+			sales[i].Address = fmt.Sprintf("%d SE Main St", 100+i)
+		}
+	}
+	return sales
+}
+
+// TestFillRates tests per-field fill rates, with unparseable rows counted as rows
+func TestFillRates(t *testing.T) {
+	result := &FetchResult{Listings: driftSales(6, 3), SkippedIDs: []string{"x", "y"}}
+	result.Listings[0].ZipCode = ""
+
+	rates := fillRates(result)
+	assert.Equal(t, 8, rates.Rows)
+	assert.InDelta(t, 0.75, rates.Date, 0.001)
+	assert.InDelta(t, 0.375, rates.Address, 0.001)
+	assert.InDelta(t, 0.625, rates.ZipCode, 0.001)
+	assert.InDelta(t, 0.75, rates.City, 0.001)
+}
+
+// TestRefreshLocationQuarantinesDrift tests that a run whose addresses vanish is
+// quarantined instead of overwriting the stored listings
+func TestRefreshLocationQuarantinesDrift(t *testing.T) {
+	runs := &fakeRunRepo{}
+	repo := &fakeListingRepo{}
+	src := &fakeSource{name: "a", state: "OR", listings: driftSales(10, 10)}
+	s := &ScraperService{repo: repo, driftThreshold: 0.5, driftBaselineRuns: 5}
+	s.SetRunRepository(runs)
+	s.RegisterSource(src)
+
+	// Build a baseline
+	for i := 0; i < scrape.MinBaselineRuns; i++ {
+		_, err := s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+		require.NoError(t, err)
+	}
+	require.Len(t, repo.upserted, 30)
+
+	// The address selector breaks: every sale falls back to "TBA"
+	src.listings = driftSales(10, 0)
+	_, err := s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	require.Error(t, err, "the only source was degraded")
+	assert.Contains(t, err.Error(), "degraded: address 0% (usually 100%)")
+	assert.Len(t, repo.upserted, 30, "nothing upserted")
+
+	run := runs.runs[len(runs.runs)-1]
+	assert.True(t, run.Degraded)
+	assert.True(t, run.Succeeded())
+	assert.Equal(t, "address 0% (usually 100%)", run.DegradedReason)
+	assert.Equal(t, 10, run.Quarantined)
+	require.Len(t, runs.quarantined, 10)
+	assert.Equal(t, run.ID, runs.quarantined[0].RunID)
+	assert.Contains(t, string(runs.quarantined[0].Listing), `"address":"TBA"`)
+
+	// Degraded runs don't drag the baseline down
+	_, err = s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	require.Error(t, err)
+
+	// A partial dip within the threshold is accepted
+	src.listings = driftSales(10, 6)
+	_, err = s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	require.NoError(t, err)
+	assert.Len(t, repo.upserted, 40)
+}
@@ -0,0 +1,306 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// DefaultDetailTTL is how long a fetched detail page is reused before refetching
+const DefaultDetailTTL = 12 * time.Hour
+
+var (
+	// phoneRegex matches US phone numbers, e.g. "(503) 555-0142" or "503.555.0142"
+	phoneRegex = regexp.MustCompile(`\(?\b\d{3}\)?[-.\s]\d{3}[-.\s]\d{4}\b`)
+
+	// imageExtRegex matches photo URLs (thumbnails link to full-size images)
+	imageExtRegex = regexp.MustCompile(`(?i)\.(jpe?g|png|webp)(\?.*)?$`)
+)
+
+// SaleDetail is what a sale's detail page adds to its list row
+type SaleDetail struct {
+	Description string
+	ImageURLs   []string // Full-size photos, in page order
+	Address     string   // Street address (empty if withheld)
+	City        string
+	ZipCode     string
+	Hours       string
+	Company     *listing.CompanyContact
+}
+
+// detailCache holds fetched detail pages by URL
+type detailCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedDetail
+}
+
+type cachedDetail struct {
+	detail     *SaleDetail
+	fetchedAt  time.Time
+	validators Validators // For a conditional refetch once the TTL is up
+}
+
+func newDetailCache() *detailCache {
+	ttl := DefaultDetailTTL
+	if d, err := time.ParseDuration(os.Getenv("SCRAPE_DETAIL_TTL")); err == nil && d > 0 {
+		ttl = d
+	}
+	return &detailCache{ttl: ttl, entries: map[string]cachedDetail{}}
+}
+
+// detailPagesEnabled reports whether detail pages should be fetched (SCRAPE_DETAIL_PAGES, default true)
+func detailPagesEnabled() bool {
+	return !strings.EqualFold(os.Getenv("SCRAPE_DETAIL_PAGES"), "false")
+}
+
+// enrichListings fills each listing from its detail page. Listings whose page can't
+// be fetched (or that are left when ctx is done) keep their list-view data.
+func (s *EstateSaleFinderScraper) enrichListings(ctx context.Context, sales []listing.ScrapedListing) {
+	if s.details == nil {
+		return
+	}
+
+	enriched := 0
+	for i := range sales {
+		if ctx.Err() != nil {
+			break
+		}
+		detail, err := s.fetchDetail(ctx, sales[i].SourceURL)
+		if err != nil {
+			log.Printf("Warning: %s detail page: %v", sales[i].ExternalID, err)
+			continue
+		}
+		applyDetail(&sales[i], detail)
+		enriched++
+	}
+	log.Printf("✓ Enriched %d/%d sales from detail pages", enriched, len(sales))
+}
+
+// fetchDetail returns a sale's detail page, from the cache if it's fresh.
+// A stale entry is still used if the refetch fails.
+func (s *EstateSaleFinderScraper) fetchDetail(ctx context.Context, pageURL string) (*SaleDetail, error) {
+	c := s.details
+	c.mu.Lock()
+	cached, ok := c.entries[pageURL]
+	c.mu.Unlock()
+	if ok && s.now().Sub(cached.fetchedAt) < c.ttl {
+		return cached.detail, nil
+	}
+
+	var validators Validators
+	if ok {
+		validators = cached.validators
+	}
+	detail, validators, err := s.loadDetail(ctx, pageURL, validators)
+	if err != nil {
+		if ok {
+			return cached.detail, nil
+		}
+		return nil, err
+	}
+	if detail == nil {
+		// Not modified since the cached fetch
+		detail = cached.detail
+	}
+
+	c.mu.Lock()
+	c.entries[pageURL] = cachedDetail{detail: detail, fetchedAt: s.now(), validators: validators}
+	c.mu.Unlock()
+	return detail, nil
+}
+
+// loadDetail fetches and parses a detail page. It returns a nil detail if the page
+// hasn't changed since the fetch that returned validators.
+func (s *EstateSaleFinderScraper) loadDetail(ctx context.Context, pageURL string, validators Validators) (*SaleDetail, Validators, error) {
+	resp, err := s.fetcher.GetConditional(ctx, pageURL, validators)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && !validators.IsZero() {
+		return nil, validators, nil
+	}
+	if resp.StatusCode != 200 {
+		return nil, Validators{}, fmt.Errorf("got status code %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, Validators{}, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return parseSaleDetail(doc, pageURL), ValidatorsFrom(resp), nil
+}
+
+// parseSaleDetail extracts a sale's detail page. The page has little markup to hang
+// selectors on, so fields are recognized by content where needed.
+func parseSaleDetail(doc *goquery.Document, pageURL string) *SaleDetail {
+	detail := &SaleDetail{}
+	base, _ := url.Parse(pageURL)
+
+	// Description: the marked-up block, or else the longest paragraph
+	detail.Description = strings.TrimSpace(doc.Find(".saledescription, .sale-description, #description").First().Text())
+	if detail.Description == "" {
+		doc.Find("p").Each(func(i int, p *goquery.Selection) {
+			text := strings.TrimSpace(p.Text())
+			if len(text) > len(detail.Description) && len(text) >= 80 {
+				detail.Description = text
+			}
+		})
+	}
+
+	// Photos: full-size links first, then images that aren't wrapped in one
+	seen := map[string]bool{}
+	addImage := func(ref string) {
+		imageURL := resolveURL(base, ref)
+		if imageURL == "" || seen[imageURL] || isDecorativeImage(imageURL) {
+			return
+		}
+		seen[imageURL] = true
+		detail.ImageURLs = append(detail.ImageURLs, imageURL)
+	}
+	doc.Find("a[href]").Each(func(i int, a *goquery.Selection) {
+		if href, _ := a.Attr("href"); imageExtRegex.MatchString(href) {
+			addImage(href)
+		}
+	})
+	doc.Find("img[src]").Each(func(i int, img *goquery.Selection) {
+		if img.ParentsFiltered("a[href]").FilterFunction(func(i int, a *goquery.Selection) bool {
+			href, _ := a.Attr("href")
+			return imageExtRegex.MatchString(href)
+		}).Length() > 0 {
+			return
+		}
+		src, _ := img.Attr("src")
+		if imageExtRegex.MatchString(src) {
+			addImage(src)
+		}
+	})
+
+	// Address, hours and phone from the page's short text lines
+	doc.Find(".address, .columns p, p, li").Each(func(i int, sel *goquery.Selection) {
+		text := strings.TrimSpace(sel.Text())
+		if text == "" || len(text) > 200 {
+			return
+		}
+		if parsed, ok := address.Parse(text); detail.Address == "" && ok && parsed.Street != "" && !clockRegex.MatchString(text) {
+			detail.Address, detail.City, detail.ZipCode = parsed.Line(), parsed.City, parsed.ZipCode
+		}
+		if detail.Hours == "" && clockRegex.MatchString(text) && weekdayRegex.MatchString(text) {
+			detail.Hours = text
+		}
+	})
+
+	detail.Company = parseCompany(doc, base)
+	return detail
+}
+
+// parseCompany reads the company block: the name in the first h5 (as on the list
+// page, linking to the company's profile) and the phone, email and website next to it
+func parseCompany(doc *goquery.Document, base *url.URL) *listing.CompanyContact {
+	heading := doc.Find("h5").First()
+	name := strings.TrimSpace(heading.Text())
+	if name == "" {
+		return nil
+	}
+
+	company := &listing.CompanyContact{Name: name}
+	if href, ok := heading.Find("a[href]").First().Attr("href"); ok {
+		company.ProfileURL = resolveURL(base, href)
+	}
+	block := heading.Parent()
+	company.Phone = phoneRegex.FindString(block.Text())
+	if mailto, ok := block.Find(`a[href^="mailto:"]`).First().Attr("href"); ok {
+		company.Email = strings.TrimPrefix(mailto, "mailto:")
+	}
+	block.Find("a[href]").EachWithBreak(func(i int, a *goquery.Selection) bool {
+		href := resolveURL(base, a.AttrOr("href", ""))
+		u, err := url.Parse(href)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || imageExtRegex.MatchString(href) {
+			return true
+		}
+		// Links back into the listing site aren't the company's
+		if base != nil && strings.HasSuffix(u.Host, strings.TrimPrefix(base.Host, "www.")) {
+			return true
+		}
+		company.Website = href
+		return false
+	})
+
+	return company
+}
+
+// applyDetail fills a list-view listing from its detail page
+func applyDetail(s *listing.ScrapedListing, detail *SaleDetail) {
+	if detail.Description != "" {
+		s.Description = detail.Description
+	}
+	if len(detail.ImageURLs) > 0 {
+		s.ImageURLs = detail.ImageURLs
+		s.ThumbnailURL = detail.ImageURLs[0]
+	}
+	// The detail page may have the street when the list still says "TBA"
+	if detail.Address != "" && address.IsWithheld(s.Address) {
+		s.Address = detail.Address
+	}
+	if s.City == "" {
+		s.City = detail.City
+	}
+	if s.ZipCode == "" {
+		s.ZipCode = detail.ZipCode
+	}
+	if s.EventHours == "" && detail.Hours != "" {
+		s.EventHours = detail.Hours
+		if sessions, err := listing.ParseSessions(detail.Hours, s.StartDate, s.EndDate); err == nil {
+			s.Sessions = sessions
+		}
+	}
+	if detail.Company != nil {
+		// Copied: the detail is cached and shared between scrapes
+		company := *detail.Company
+		if company.ProfileURL == "" && s.Company != nil {
+			company.ProfileURL = s.Company.ProfileURL
+		}
+		s.Company = &company
+	}
+}
+
+// resolveURL makes ref absolute against the page URL ("" if it can't be parsed)
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	return u.String()
+}
+
+// isDecorativeImage skips logos, icons and buttons
+func isDecorativeImage(imageURL string) bool {
+	lower := strings.ToLower(imageURL)
+	for _, marker := range []string{"logo", "icon", "button", "spacer", "banner"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+const detailPage = `<html><body>
+<img src="/images/logo.png">
+<div class="company">
+  <h5>Caring Transitions PDX</h5>
+  <p>Call (503) 555-0142 or <a href="mailto:sales@example.com">email us</a></p>
+  <p><a href="https://www.estatesale-finder.com/company.php?id=7">More sales</a>
+     <a href="https://caringtransitions.example.com">Our website</a></p>
+</div>
+<div class="columns">
+  <p>1234 SE Main Street, Portland, OR 97202</p>
+  <p>Friday 9am-4pm, Saturday 9am-3pm</p>
+</div>
+<p>Full house of mid-century furniture, vintage kitchenware, tools, garden equipment and a 1970s stereo collection. Numbers at 8am.</p>
+<a href="/saleimages/15436/1.jpg"><img src="/saleimages/15436/1_thumb.jpg"></a>
+<a href="/saleimages/15436/2.jpg"><img src="/saleimages/15436/2_thumb.jpg"></a>
+<img src="https://cdn.example.com/15436/3.jpeg">
+</body></html>`
+
+// TestParseSaleDetail tests extracting description, photos, address, hours and company
+func TestParseSaleDetail(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(detailPage))
+	require.NoError(t, err)
+
+	detail := parseSaleDetail(doc, "https://www.estatesale-finder.com/viewsale.php?saleid=15436")
+
+	assert.Contains(t, detail.Description, "mid-century furniture")
+	assert.Equal(t, []string{
+		"https://www.estatesale-finder.com/saleimages/15436/1.jpg",
+		"https://www.estatesale-finder.com/saleimages/15436/2.jpg",
+		"https://cdn.example.com/15436/3.jpeg",
+	}, detail.ImageURLs, "full-size links, no thumbnails or logos")
+	assert.Equal(t, "1234 SE Main Street", detail.Address)
+	assert.Equal(t, "Portland", detail.City)
+	assert.Equal(t, "97202", detail.ZipCode)
+	assert.Equal(t, "Friday 9am-4pm, Saturday 9am-3pm", detail.Hours)
+
+	require.NotNil(t, detail.Company)
+	assert.Equal(t, listing.CompanyContact{
+		Name:    "Caring Transitions PDX",
+		Phone:   "(503) 555-0142",
+		Email:   "sales@example.com",
+		Website: "https://caringtransitions.example.com",
+	}, *detail.Company)
+}
+
+// TestApplyDetail tests filling a list-view listing from its detail page
+func TestApplyDetail(t *testing.T) {
+	start := time.Date(2026, 10, 23, 0, 0, 0, 0, saleLocation)
+	scraped := listing.ScrapedListing{Title: "Sale", Address: "TBA", StartDate: start, EndDate: start.Add(24 * time.Hour)}
+
+	applyDetail(&scraped, &SaleDetail{
+		Description: "Full house",
+		ImageURLs:   []string{"https://img/1.jpg", "https://img/2.jpg"},
+		Address:     "1234 SE Main Street",
+		ZipCode:     "97202",
+		Hours:       "Friday 9am-4pm, Saturday 9am-3pm",
+	})
+
+	assert.Equal(t, "Full house", scraped.Description)
+	assert.Equal(t, "https://img/1.jpg", scraped.ThumbnailURL)
+	assert.Len(t, scraped.ImageURLs, 2)
+	assert.Equal(t, "1234 SE Main Street", scraped.Address)
+	assert.Equal(t, "97202", scraped.ZipCode)
+	assert.Len(t, scraped.Sessions, 2)
+}
+
+// TestFetchDetailCaches tests that detail pages are reused within the TTL and kept on refetch failure
+func TestFetchDetailCaches(t *testing.T) {
+	hits := 0
+	failing := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		hits++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(detailPage))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	fetcher := NewFetcherWithClient(srv.Client())
+	fetcher.hostRate = rate.Inf
+	s := &EstateSaleFinderScraper{
+		fetcher: fetcher,
+		now:     func() time.Time { return now },
+		details: &detailCache{ttl: time.Hour, entries: map[string]cachedDetail{}},
+	}
+
+	detail, err := s.fetchDetail(context.Background(), srv.URL + "/viewsale.php?saleid=1")
+	require.NoError(t, err)
+	assert.Len(t, detail.ImageURLs, 3)
+
+	_, err = s.fetchDetail(context.Background(), srv.URL + "/viewsale.php?saleid=1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, hits, "second fetch within the TTL is cached")
+
+	// Expired and the site is down: serve the stale copy
+	now = now.Add(2 * time.Hour)
+	failing = true
+	detail, err = s.fetchDetail(context.Background(), srv.URL + "/viewsale.php?saleid=1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+	assert.Len(t, detail.ImageURLs, 3)
+
+	_, err = s.fetchDetail(context.Background(), srv.URL + "/viewsale.php?saleid=2")
+	assert.Error(t, err, "nothing cached to fall back on")
+}
+
+// TestFetchDetailNotModified tests revalidating an expired detail page
+func TestFetchDetailNotModified(t *testing.T) {
+	full, notModified := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"sale-1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"sale-1"`)
+		w.Write([]byte(detailPage))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	fetcher := NewFetcherWithClient(srv.Client())
+	fetcher.hostRate = rate.Inf
+	s := &EstateSaleFinderScraper{
+		fetcher: fetcher,
+		now:     func() time.Time { return now },
+		details: &detailCache{ttl: time.Hour, entries: map[string]cachedDetail{}},
+	}
+
+	_, err := s.fetchDetail(context.Background(), srv.URL + "/viewsale.php?saleid=1")
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Hour)
+	detail, err := s.fetchDetail(context.Background(), srv.URL + "/viewsale.php?saleid=1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, full)
+	assert.Equal(t, 1, notModified)
+	assert.Len(t, detail.ImageURLs, 3, "cached detail reused")
+
+	// Revalidated, so fresh for another TTL
+	_, err = s.fetchDetail(context.Background(), srv.URL + "/viewsale.php?saleid=1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, notModified)
+}
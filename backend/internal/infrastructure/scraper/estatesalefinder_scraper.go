@@ -1,87 +1,236 @@
 package scraper
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
 )
 
 // EstateSaleFinderScraper scrapes estatesale-finder.com
 type EstateSaleFinderScraper struct {
-	httpClient *http.Client
+	fetcher *Fetcher
+	now     func() time.Time // Reference time for inferring sale years
+	details *detailCache     // nil disables detail page enrichment (SCRAPE_DETAIL_PAGES=false)
+
+	lists  map[string]*listPage // Last page per list URL, for conditional requests
+	listMu sync.Mutex
 }
 
-// NewEstateSaleFinderScraper creates a new scraper
-func NewEstateSaleFinderScraper() *EstateSaleFinderScraper {
-	return &EstateSaleFinderScraper{
-		httpClient: &http.Client{
-			Timeout: 15 * time.Second,
-		},
+// NewEstateSaleFinderScraper creates a new scraper that fetches through fetcher
+func NewEstateSaleFinderScraper(fetcher *Fetcher) *EstateSaleFinderScraper {
+	s := &EstateSaleFinderScraper{
+		fetcher: fetcher,
+		now:     time.Now,
+	}
+	if detailPagesEnabled() {
+		s.details = newDetailCache()
 	}
+	return s
+}
+
+// Name implements Source
+func (s *EstateSaleFinderScraper) Name() string {
+	return "EstateSale-Finder.com"
+}
+
+// Covers implements Source (estatesale-finder.com is regional: Portland metro only)
+func (s *EstateSaleFinderScraper) Covers(city, state string) bool {
+	return strings.ToUpper(state) == "OR" || strings.ToLower(city) == "portland"
+}
+
+// FetchListings implements Source. The site has no per-city pages, so every
+// covered location gets the full Portland-area list.
+func (s *EstateSaleFinderScraper) FetchListings(ctx context.Context, city, state string) (*FetchResult, error) {
+	return s.ScrapePortlandSales(ctx)
+}
+
+// saleTypeLists are the list requests a scrape makes, by saletypeshow ID. List rows don't
+// say what kind of sale they are, so the typed IDs are requested one at a time and their
+// rows tagged; the remaining IDs are requested together and their rows left untyped.
+var saleTypeLists = []struct {
+	saleTypes string
+	eventType string
+}{
+	{"1", listing.EventEstateSale},
+	{"2", listing.EventMovingSale},
+	{"4", listing.EventGarageSale}, // Garage/Yard
+	{"5,7,8,9,10,11,12,13", ""},
 }
 
 // ScrapePortlandSales scrapes sales from Portland area
 // Region IDs: 1=N Portland, 2=NW Portland, 3=NE Portland, 4=SE Portland, 5=SW Portland
-func (s *EstateSaleFinderScraper) ScrapePortlandSales() ([]listing.ScrapedListing, error) {
+func (s *EstateSaleFinderScraper) ScrapePortlandSales(ctx context.Context) (*FetchResult, error) {
 	// All Portland regions
 	regions := "1,2,3,4,5,6,7,8,9,10,11,12,13,14,15"
-	// All sale types: 1=Estate, 2=Moving, 4=Garage/Yard
-	saleTypes := "1,2,4,5,7,8,9,10,11,12,13"
 
-	url := fmt.Sprintf("https://www.estatesale-finder.com/all_sales_list.php?saletypeshow=%s&regionsshow=%s",
-		saleTypes, regions)
+	result := &FetchResult{}
+	sales := []listing.ScrapedListing{}
+	seen := make(map[string]bool)
+	for _, list := range saleTypeLists {
+		url := fmt.Sprintf("https://www.estatesale-finder.com/all_sales_list.php?saletypeshow=%s&regionsshow=%s",
+			list.saleTypes, regions)
+
+		page, status, err := s.fetchListPage(ctx, url)
+		result.HTTPStatus = status
+		if err != nil {
+			// A missing list would mark its sales missing; fail the whole scrape instead
+			return result, err
+		}
+		result.RowsSeen += page.rowsSeen
+		result.SkippedIDs = append(result.SkippedIDs, page.skippedIDs...)
+
+		// Copy so tagging and enrichment don't touch the cached parse
+		for _, sale := range page.sales {
+			if seen[sale.ExternalID] {
+				continue
+			}
+			seen[sale.ExternalID] = true
+			sale.EventType = list.eventType
+			sale.ScrapedAt, sale.CachedAt = s.now(), s.now()
+			sales = append(sales, sale)
+		}
+	}
+
+	// The list view has no photos and only a title for a description
+	s.enrichListings(ctx, sales)
+
+	result.Listings = sales
+	return result, nil
+}
 
+// fetchListPage fetches and parses one list page, reusing the last parse of url when the
+// server says it's not modified or sends the same bytes. It also returns the HTTP status.
+func (s *EstateSaleFinderScraper) fetchListPage(ctx context.Context, url string) (*listPage, int, error) {
 	log.Printf("→ Scraping: %s", url)
 
-	// Rate limiting
-	time.Sleep(1 * time.Second)
+	s.listMu.Lock()
+	cached := s.lists[url]
+	s.listMu.Unlock()
 
-	resp, err := s.httpClient.Get(url)
+	var validators Validators
+	if cached != nil {
+		validators = cached.validators
+	}
+	resp, err := s.fetcher.GetConditional(ctx, url, validators)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch: %w", err)
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("got status code %d", resp.StatusCode)
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		log.Printf("✓ List page not modified; reusing %d parsed sales", len(cached.sales))
+		return cached, resp.StatusCode, nil
+	case resp.StatusCode != 200:
+		return nil, resp.StatusCode, fmt.Errorf("got status code %d", resp.StatusCode)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	// Servers without validators still send identical bytes for an unchanged page
+	var page *listPage
+	hash := sha256.Sum256(body)
+	if cached != nil && cached.bodyHash == hash {
+		log.Printf("✓ List page unchanged; reusing %d parsed sales", len(cached.sales))
+		reused := *cached
+		page = &reused
+	} else if page, err = s.parseListPage(body, url); err != nil {
+		return nil, resp.StatusCode, err
+	}
+	page.bodyHash = hash
+	page.validators = ValidatorsFrom(resp)
+
+	s.listMu.Lock()
+	if s.lists == nil {
+		s.lists = make(map[string]*listPage)
+	}
+	s.lists[url] = page
+	s.listMu.Unlock()
+
+	return page, resp.StatusCode, nil
+}
+
+// listPage is the parsed list page, kept to skip unchanged pages on the next fetch
+type listPage struct {
+	validators Validators
+	bodyHash   [sha256.Size]byte
+	sales      []listing.ScrapedListing // Before detail enrichment
+	rowsSeen   int
+	skippedIDs []string
+}
+
+// parseListPage parses all_sales_list.php fetched from pageURL
+func (s *EstateSaleFinderScraper) parseListPage(body []byte, pageURL string) (*listPage, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	var sales []listing.ScrapedListing
+	page := &listPage{}
+	base, _ := url.Parse(pageURL)
 
 	// Find each sale row (from both "This Week's Sales" AND "Upcoming Sales" sections)
-	doc.Find(".salerow").Each(func(i int, sel *goquery.Selection) {
-		scraped := s.parseSaleRow(sel)
+	rows := doc.Find(".salerow")
+	page.rowsSeen = rows.Length()
+	rows.Each(func(i int, sel *goquery.Selection) {
+		scraped, err := s.parseSaleRow(sel, base)
+		if err != nil {
+			// Don't invent dates for rows we can't read - skip them
+			log.Printf("✗ Skipping sale row: %v", err)
+			if externalID, ok := saleRowID(sel); ok {
+				page.skippedIDs = append(page.skippedIDs, externalID)
+			}
+			return
+		}
 		if scraped != nil {
-			sales = append(sales, *scraped)
+			page.sales = append(page.sales, *scraped)
 		}
 	})
 
-	log.Printf("✓ Scraped %d sales from estatesale-finder.com (current + upcoming)", len(sales))
-	return sales, nil
+	log.Printf("✓ Scraped %d sales from estatesale-finder.com (current + upcoming, %d rows, %d skipped)", len(page.sales), page.rowsSeen, len(page.skippedIDs))
+	return page, nil
 }
 
-// parseSaleRow extracts data from a single sale row
-func (s *EstateSaleFinderScraper) parseSaleRow(sel *goquery.Selection) *listing.ScrapedListing {
-	// Get sale ID from id attribute (e.g., "sale15436")
+// saleRowID returns a row's external ID from its id attribute (e.g., "sale15436")
+func saleRowID(sel *goquery.Selection) (string, bool) {
 	listingID, exists := sel.Attr("id")
 	if !exists || !strings.HasPrefix(listingID, "sale") {
-		return nil
+		return "", false
+	}
+	return fmt.Sprintf("estatesale-finder-%s", strings.TrimPrefix(listingID, "sale")), true
+}
+
+// parseSaleRow extracts data from a single sale row; links resolve against base (the list page URL).
+// Returns nil, nil for rows that aren't sales (no "saleNNN" id).
+func (s *EstateSaleFinderScraper) parseSaleRow(sel *goquery.Selection, base *url.URL) (*listing.ScrapedListing, error) {
+	externalID, ok := saleRowID(sel)
+	if !ok {
+		return nil, nil
 	}
-	externalID := fmt.Sprintf("estatesale-finder-%s", strings.TrimPrefix(listingID, "sale"))
 
-	// Get provider/title
-	title := strings.TrimSpace(sel.Find("h5 a").First().Text())
-	if title == "" {
+	// Get provider/title (the heading links to the company's profile, company.php?id=NNN)
+	provider := sel.Find("h5 a").First()
+	title := strings.TrimSpace(provider.Text())
+	var company *listing.CompanyContact
+	if title != "" {
+		company = &listing.CompanyContact{Name: title}
+		company.ProfileURL = resolveURL(base, provider.AttrOr("href", ""))
+	} else {
 		title = "Estate Sale" // Fallback
 	}
 
@@ -89,98 +238,52 @@ func (s *EstateSaleFinderScraper) parseSaleRow(sel *goquery.Selection) *listing.
 	viewLink, _ := sel.Find("a.view").Attr("href")
 	sourceURL := "https://www.estatesale-finder.com/" + viewLink
 
-	// Get address (in .columns p elements)
-	address := ""
-	city := ""
-	state := "OR"
-	zipCode := ""
-
-	sel.Find(".columns p").Each(func(i int, p *goquery.Selection) {
-		text := strings.TrimSpace(p.Text())
-
-		// Look for address pattern (contains city names or OR and numbers)
-		if strings.Contains(text, "Portland") || strings.Contains(text, "Beaverton") ||
-		   strings.Contains(text, "Gresham") || strings.Contains(text, "Mulino") ||
-		   strings.Contains(text, "Milwaukie") || strings.Contains(text, "OR") {
-
-			// Extract zip code (5 digits)
-			parts := strings.Fields(text)
-			for _, part := range parts {
-				if len(part) == 5 && isNumeric(part) {
-					zipCode = part
-					break
-				}
-			}
-
-			// Extract city (look for known cities)
-			cities := []string{"Portland", "Beaverton", "Gresham", "Mulino", "Milwaukie", "Lake Oswego"}
-			for _, c := range cities {
-				if strings.Contains(text, c) {
-					city = c
-					break
-				}
-			}
-
-			// Extract address - everything before city/state/zip
-			// Remove "TBA" prefix if present
-			addressText := strings.TrimPrefix(text, "TBA")
-			addressText = strings.TrimSpace(addressText)
-
-			// If we have a city, try to extract street address
-			if city != "" {
-				// Find the position of the city in the text
-				cityIdx := strings.Index(addressText, city)
-				if cityIdx > 0 {
-					address = strings.TrimSpace(addressText[:cityIdx])
-				} else if addressText != "" && !strings.HasPrefix(addressText, city) {
-					// If city not found but we have text, use it
-					address = addressText
-				}
-			}
-
-			// If address is still empty or is just the city, mark as TBA
-			if address == "" || address == city {
-				address = "TBA"
-			}
+	// Get address (the first .columns p that parses as one, e.g. "TBA Tigard, OR 97223")
+	var addr address.Address
+	sel.Find(".columns p").EachWithBreak(func(i int, p *goquery.Selection) bool {
+		parsed, ok := address.Parse(p.Text())
+		if !ok || (parsed.City == "" && parsed.ZipCode == "" && !parsed.Withheld) {
+			return true
 		}
+		addr = parsed
+		return false
 	})
 
-	// Get dates/times
+	// Withheld or unreadable streets are stored as "TBA"
+	street := addr.Line()
+	if addr.Withheld || street == "" {
+		street = "TBA"
+	}
+	state := addr.State
+	if state == "" {
+		state = "OR" // The site only lists Portland-area sales
+	}
+
+	// Get dates/times ("Opens 31st Oct 10:00am" + an hours paragraph)
 	saleInfo := ""
 	hours := ""
 
 	sel.Find(".columns p").Each(func(i int, p *goquery.Selection) {
 		text := strings.TrimSpace(p.Text())
-		if strings.Contains(text, "Opens") || strings.Contains(text, "day") {
+		if strings.Contains(text, "Opens") {
+			saleInfo = text
+		} else if saleInfo == "" && strings.Contains(text, "day") {
 			saleInfo = text
 		}
-		if strings.Contains(text, "am") || strings.Contains(text, "pm") {
-			if !strings.Contains(text, "Opens") {
-				hours = text
-			}
+		if clockRegex.MatchString(text) && !strings.Contains(text, "Opens") {
+			hours = text
 		}
 	})
 
-	// Parse dates (simplified - just use today + 7 days as placeholder)
-	startDate := time.Now().AddDate(0, 0, 7)
-	endDate := startDate.AddDate(0, 0, 2) // 3-day sale typically
-
-	// Try to extract actual open date from saleInfo
-	if strings.Contains(saleInfo, "Opens") {
-		// Format: "Opens 31st Oct 10:00am"
-		parts := strings.Fields(saleInfo)
-		for j, part := range parts {
-			if part == "Opens" && j+2 < len(parts) {
-				// parts[j+1] = "31st", parts[j+2] = "Oct"
-				dateStr := strings.Join(parts[j+1:j+3], " ")
-				parsedDate := s.parseDate(dateStr)
-				if !parsedDate.IsZero() {
-					startDate = parsedDate
-					endDate = startDate.AddDate(0, 0, 2)
-				}
-				break
-			}
-		}
+	dates, err := ParseSaleDates(saleInfo, hours, s.now(), saleLocation)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to parse dates from %q / %q: %w", externalID, saleInfo, hours, err)
+	}
+
+	// Per-day open hours (left empty rather than guessed when unreadable)
+	sessions, err := listing.ParseSessions(hours, dates.Start, dates.End)
+	if err != nil {
+		sessions = nil
 	}
 
 	description := fmt.Sprintf("%s\n\n%s\n%s", title, saleInfo, hours)
@@ -189,53 +292,19 @@ func (s *EstateSaleFinderScraper) parseSaleRow(sel *goquery.Selection) *listing.
 		ExternalID:   externalID,
 		Title:        title,
 		Description:  description,
-		Address:      address,
-		City:         city,
+		Address:      street,
+		City:         addr.City,
 		State:        state,
-		ZipCode:      zipCode,
-		StartDate:    startDate,
-		EndDate:      endDate,
+		ZipCode:      addr.ZipCode,
+		StartDate:    dates.Start,
+		EndDate:      dates.End,
+		EventHours:   hours,
+		Sessions:     sessions,
+		Company:      company,
 		ThumbnailURL: "", // No images in list view
-		SourceName:   "EstateSale-Finder.com",
+		SourceName:   s.Name(),
 		SourceURL:    sourceURL,
-		ScrapedAt:    time.Now(),
-		CachedAt:     time.Now(),
-	}
-}
-
-// parseDate parses dates like "31st Oct" into current year
-func (s *EstateSaleFinderScraper) parseDate(dateStr string) time.Time {
-	// Remove ordinal suffixes (st, nd, rd, th)
-	dateStr = strings.ReplaceAll(dateStr, "st", "")
-	dateStr = strings.ReplaceAll(dateStr, "nd", "")
-	dateStr = strings.ReplaceAll(dateStr, "rd", "")
-	dateStr = strings.ReplaceAll(dateStr, "th", "")
-
-	// Add current year
-	currentYear := time.Now().Year()
-	dateStr = fmt.Sprintf("%s %d", dateStr, currentYear)
-
-	// Try parsing
-	layouts := []string{
-		"2 Jan 2006",
-		"02 Jan 2006",
-	}
-
-	for _, layout := range layouts {
-		if t, err := time.Parse(layout, dateStr); err == nil {
-			return t
-		}
-	}
-
-	return time.Time{}
-}
-
-// isNumeric checks if string is all digits
-func isNumeric(s string) bool {
-	for _, c := range s {
-		if c < '0' || c > '9' {
-			return false
-		}
-	}
-	return true
+		ScrapedAt:    s.now(),
+		CachedAt:     s.now(),
+	}, nil
 }
@@ -0,0 +1,68 @@
+package scraper
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/httpreplay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScrapePortlandSalesEventTypes tests that rows are tagged with the sale type of the
+// list request that returned them, and rows from the combined request are left untyped
+func TestScrapePortlandSalesEventTypes(t *testing.T) {
+	fixtures := filepath.Join("testdata", "synthetic", "fixtures", "estatesale-finder")
+	now := time.Date(2025, time.October, 20, 12, 0, 0, 0, saleLocation)
+
+	result, err := fetchEstateSaleFinderGolden(httpreplay.NewClient(fixtures, httpreplay.ModeReplay), now)
+	require.NoError(t, err)
+
+	eventTypes := map[string]string{}
+	for _, sale := range result.Listings {
+		eventTypes[sale.ExternalID] = sale.EventType
+	}
+	assert.Equal(t, map[string]string{
+		"estatesale-finder-15436": listing.EventEstateSale,
+		"estatesale-finder-15441": listing.EventEstateSale,
+		"estatesale-finder-15452": listing.EventMovingSale,
+	}, eventTypes)
+
+	assert.Equal(t, []string{"estatesale-finder-15460"}, result.SkippedIDs, "the garage sale list's undated row")
+	assert.Equal(t, 5, result.RowsSeen, "rows across every list request")
+}
+
+// TestParseSaleRowProfileURL tests that company profile links resolve against the list page
+func TestParseSaleRowProfileURL(t *testing.T) {
+	base, err := url.Parse("https://www.estatesale-finder.com/all_sales_list.php?saletypes=1")
+	require.NoError(t, err)
+	s := &EstateSaleFinderScraper{now: func() time.Time { return time.Date(2025, time.October, 20, 12, 0, 0, 0, saleLocation) }}
+
+	tests := map[string]string{
+		"company.php?id=33":                        "https://www.estatesale-finder.com/company.php?id=33",
+		"/company.php?id=33":                       "https://www.estatesale-finder.com/company.php?id=33",
+		"https://rosecityliquidators.example.com/": "https://rosecityliquidators.example.com/",
+	}
+	for href, want := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="row salerow" id="sale15452">
+<div class="columns">
+<h5><a href="` + href + `">Rose City Liquidators</a></h5>
+<p>1607 SE Bybee Blvd, Portland, OR 97202</p>
+<p>Opens 31st Oct 9:00am</p>
+<p>Friday 31st Oct 9am-4pm</p>
+<a class="view button" href="view_sale.php?saleid=15452">View Sale</a>
+</div>
+</div>`))
+		require.NoError(t, err)
+
+		scraped, err := s.parseSaleRow(doc.Find(".salerow"), base)
+		require.NoError(t, err, href)
+		require.NotNil(t, scraped.Company, href)
+		assert.Equal(t, want, scraped.Company.ProfileURL, href)
+	}
+}
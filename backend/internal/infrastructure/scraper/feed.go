@@ -0,0 +1,138 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// maxFeedSize caps how much of a feed is read (company calendars are a few KB)
+const maxFeedSize = 10 << 20
+
+// FeedConfig configures a company's iCalendar or RSS/Atom feed
+type FeedConfig struct {
+	Name     string `yaml:"name"` // Source name shown on listings, e.g. "Acme Estate Sales"
+	URL      string `yaml:"url"`  // The .ics or feed URL
+	Coverage `yaml:",inline"`
+	TimeZone string `yaml:"timezone"` // For times without a zone (default America/Los_Angeles)
+}
+
+// validate checks the fields every feed source needs
+func (c FeedConfig) validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if parsed, err := url.Parse(c.URL); err != nil || parsed.Host == "" {
+		return fmt.Errorf("%s: invalid URL %q", c.Name, c.URL)
+	}
+	if err := c.Coverage.validate(); err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// fetchFeed returns a feed's status and body
+func fetchFeed(ctx context.Context, fetcher *Fetcher, feedURL string) (int, []byte, error) {
+	resp, err := fetcher.Get(ctx, feedURL)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return resp.StatusCode, nil, fmt.Errorf("got status code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// externalID builds a configured source's external ID from whatever identifies the
+// sale on that source (a UID, GUID or URL), e.g. "acme-estate-sales-3f2a9c01b7de"
+func externalID(sourceName, key string) string {
+	sum := sha1.Sum([]byte(key))
+	return fmt.Sprintf("%s-%s", sourceSlug(sourceName), hex.EncodeToString(sum[:6]))
+}
+
+// applyDefaults fills what a configured source couldn't find: a withheld street
+// is "TBA" and the state is the one the source covers
+func applyDefaults(scraped *listing.ScrapedListing, coverage Coverage) {
+	if scraped.Address == "" {
+		scraped.Address = "TBA"
+	}
+	if scraped.State == "" {
+		scraped.State = address.StateCode(coverage.State)
+	}
+}
+
+// applyAddressText fills the address from free text, e.g. "123 SE Main St, Portland, OR 97214"
+func applyAddressText(scraped *listing.ScrapedListing, text string) {
+	if parsed, ok := address.Parse(text); ok {
+		applyAddress(scraped, parsed)
+	}
+}
+
+// applyAddress copies a parsed address onto a listing (a withheld street stays empty)
+func applyAddress(scraped *listing.ScrapedListing, parsed address.Address) {
+	scraped.Address = parsed.Line()
+	scraped.City, scraped.State, scraped.ZipCode = parsed.City, parsed.State, parsed.ZipCode
+}
+
+var (
+	// blockTagRegex matches tags that end a line of text
+	blockTagRegex = regexp.MustCompile(`(?i)<br\s*/?>|</(?:p|div|li|h[1-6]|tr)>`)
+
+	// lineSpaceRegex matches runs of spaces and tabs
+	lineSpaceRegex = regexp.MustCompile(`[ \t\f\v]+`)
+)
+
+// htmlText turns an HTML snippet (e.g. a feed item's description) into plain text,
+// one line per paragraph or <br>
+func htmlText(s string) string {
+	s = stripTags(blockTagRegex.ReplaceAllString(s, "\n"))
+	s = html.UnescapeString(s)
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(lineSpaceRegex.ReplaceAllString(line, " ")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findAddress returns the first line of text that reads as a street address with a
+// state or ZIP (or a withheld "TBA" address). Leading parts of a line that aren't
+// the address are skipped, e.g. "The Smith Estate, 1234 SE Main St, Portland, OR".
+func findAddress(text string) (address.Address, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		for {
+			parsed, ok := address.Parse(line)
+			if ok && (parsed.Withheld || (startsWithDigit(parsed.Street) && (parsed.State != "" || parsed.ZipCode != ""))) {
+				return parsed, true
+			}
+			comma := strings.Index(line, ",")
+			if comma < 0 {
+				break
+			}
+			line = line[comma+1:]
+		}
+	}
+	return address.Address{}, false
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
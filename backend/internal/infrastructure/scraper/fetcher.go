@@ -0,0 +1,288 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultUserAgent identifies the aggregator to the sites it scrapes (override with SCRAPER_USER_AGENT)
+	DefaultUserAgent = "EstateSaleFinderBot/1.0 (+https://estatesalefinder.ai/bot; bot@estatesalefinder.ai)"
+
+	// DefaultHostRate is the per-host request rate (override with SCRAPER_HOST_RATE, requests per second)
+	DefaultHostRate = 1.0
+
+	robotsTTL      = 24 * time.Hour
+	robotsErrorTTL = 10 * time.Minute // Retry soon after a server error disallowed everything
+	maxRetries     = 2
+	maxBackoff     = 2 * time.Minute // Longer Retry-After values fail the fetch instead of waiting
+)
+
+// ErrDisallowed is returned for URLs the site's robots.txt disallows
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// Fetcher is the HTTP client shared by all sources. It honors robots.txt (disallow and
+// crawl-delay), rate limits each host across goroutines, identifies itself with a
+// User-Agent carrying contact info, and backs off on 429/503 using Retry-After.
+type Fetcher struct {
+	client    *http.Client
+	userAgent string
+	hostRate  rate.Limit
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// hostState is the politeness state for one host
+type hostState struct {
+	mu              sync.Mutex // Serializes robots.txt loads
+	limiter         *rate.Limiter
+	robots          *robotsRules
+	robotsExpiresAt time.Time
+	blockedUntil    time.Time // Set from Retry-After
+}
+
+// NewFetcher creates a fetcher configured from SCRAPER_USER_AGENT and SCRAPER_HOST_RATE
+func NewFetcher() *Fetcher {
+	f := NewFetcherWithClient(&http.Client{Timeout: 15 * time.Second})
+
+	if ua := strings.TrimSpace(os.Getenv("SCRAPER_USER_AGENT")); ua != "" {
+		f.userAgent = ua
+	}
+	if r, err := strconv.ParseFloat(os.Getenv("SCRAPER_HOST_RATE"), 64); err == nil && r > 0 {
+		f.hostRate = rate.Limit(r)
+	}
+	return f
+}
+
+// NewFetcherWithClient creates a fetcher with the defaults around client (e.g. a replay client in tests)
+func NewFetcherWithClient(client *http.Client) *Fetcher {
+	return &Fetcher{
+		client:    client,
+		userAgent: DefaultUserAgent,
+		hostRate:  rate.Limit(DefaultHostRate),
+		hosts:     map[string]*hostState{},
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+}
+
+// Validators are a response's cache validators, sent back to make a request conditional
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// ValidatorsFrom returns resp's ETag and Last-Modified
+func ValidatorsFrom(resp *http.Response) Validators {
+	return Validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+}
+
+// IsZero reports whether there is nothing to make a request conditional on
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// Get fetches rawURL politely. A 429/503 is retried after its Retry-After (or an
+// exponential backoff); if it persists, the last response is returned for the caller
+// to report. Waiting and the request itself stop when ctx is done.
+func (f *Fetcher) Get(ctx context.Context, rawURL string) (*http.Response, error) {
+	return f.GetConditional(ctx, rawURL, Validators{})
+}
+
+// GetConditional is Get with If-None-Match/If-Modified-Since from v. The response is a
+// 304 with no body when the caller's copy (the one v came from) is still current.
+func (f *Fetcher) GetConditional(ctx context.Context, rawURL string, v Validators) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	host := f.host(u)
+
+	robots, err := f.robots(ctx, u, host)
+	if err != nil {
+		return nil, err
+	}
+	if !robots.allowed(u.RequestURI()) {
+		return nil, fmt.Errorf("%s: %w", rawURL, ErrDisallowed)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := f.wait(ctx, host); err != nil {
+			return nil, err
+		}
+
+		resp, err := f.do(ctx, u.String(), v)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+
+		backoff := retryAfter(resp.Header.Get("Retry-After"), f.now())
+		if backoff <= 0 {
+			backoff = time.Duration(1<<attempt) * 2 * time.Second
+		}
+		host.mu.Lock()
+		host.blockedUntil = f.now().Add(backoff)
+		host.mu.Unlock()
+
+		if attempt >= maxRetries || backoff > maxBackoff {
+			log.Printf("Warning: %s returned %d; giving up after %d attempts", u.Host, resp.StatusCode, attempt+1)
+			return resp, nil
+		}
+		resp.Body.Close()
+		log.Printf("→ %s returned %d; retrying in %v", u.Host, resp.StatusCode, backoff)
+	}
+}
+
+// host returns (creating) the state for u's host
+func (f *Fetcher) host(u *url.URL) *hostState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.ToLower(u.Host)
+	h, ok := f.hosts[key]
+	if !ok {
+		h = &hostState{limiter: rate.NewLimiter(f.hostRate, 1)}
+		f.hosts[key] = h
+	}
+	return h
+}
+
+// robots returns the host's robots.txt rules, loading them when missing or expired.
+// A missing robots.txt allows everything; a server error disallows everything for a while.
+// It only fails if ctx is done, in which case nothing is cached.
+func (f *Fetcher) robots(ctx context.Context, u *url.URL, host *hostState) (*robotsRules, error) {
+	host.mu.Lock()
+	defer host.mu.Unlock()
+
+	if host.robots != nil && f.now().Before(host.robotsExpiresAt) {
+		return host.robots, nil
+	}
+
+	rules, ttl := f.loadRobots(ctx, u, host)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	host.robots, host.robotsExpiresAt = rules, f.now().Add(ttl)
+
+	// Crawl-delay can only slow us down
+	limit := f.hostRate
+	if rules.crawlDelay > 0 {
+		if delayLimit := rate.Every(rules.crawlDelay); delayLimit < limit {
+			limit = delayLimit
+		}
+	}
+	host.limiter.SetLimit(limit)
+
+	return rules, nil
+}
+
+// loadRobots fetches and parses robots.txt (called with host.mu held)
+func (f *Fetcher) loadRobots(ctx context.Context, u *url.URL, host *hostState) (*robotsRules, time.Duration) {
+	robotsURL := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}).String()
+
+	if err := host.limiter.Wait(ctx); err != nil {
+		return disallowAll, robotsErrorTTL
+	}
+	resp, err := f.do(ctx, robotsURL, Validators{})
+	if err != nil {
+		log.Printf("Warning: failed to fetch %s: %v", robotsURL, err)
+		return disallowAll, robotsErrorTTL
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		log.Printf("Warning: %s returned %d; not fetching from %s for now", robotsURL, resp.StatusCode, u.Host)
+		return disallowAll, robotsErrorTTL
+	case resp.StatusCode >= 400:
+		return allowAll, robotsTTL
+	}
+
+	return parseRobots(resp.Body, f.agentToken()), robotsTTL
+}
+
+// wait blocks until the host's Retry-After has passed and the rate limiter allows a request
+func (f *Fetcher) wait(ctx context.Context, host *hostState) error {
+	host.mu.Lock()
+	blocked := host.blockedUntil.Sub(f.now())
+	host.mu.Unlock()
+	if blocked > 0 {
+		if err := f.sleep(ctx, blocked); err != nil {
+			return err
+		}
+	}
+	return host.limiter.Wait(ctx)
+}
+
+// sleepContext sleeps for d, returning early with ctx's error if it's done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// do sends one GET with our User-Agent and any validators
+func (f *Fetcher) do(ctx context.Context, rawURL string, v Validators) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	return resp, nil
+}
+
+// agentToken is the product token robots.txt groups name, e.g. "EstateSaleFinderBot"
+func (f *Fetcher) agentToken() string {
+	token, _, _ := strings.Cut(f.userAgent, "/")
+	return strings.TrimSpace(token)
+}
+
+// retryAfter parses a Retry-After header (seconds or an HTTP date); 0 if absent or invalid
+func retryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
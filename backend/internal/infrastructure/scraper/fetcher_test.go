@@ -0,0 +1,252 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+const testRobots = `# Comments are ignored
+User-agent: OtherBot
+Disallow: /
+
+User-agent: EstateSaleFinderBot
+User-agent: SomethingElse
+Disallow: /private/
+Allow: /private/sales/
+Disallow: /*.pdf$
+Crawl-delay: 5
+
+User-agent: *
+Disallow: /
+`
+
+// TestParseRobots tests group selection and longest-match rules
+func TestParseRobots(t *testing.T) {
+	rules := parseRobots(strings.NewReader(testRobots), "EstateSaleFinderBot")
+
+	assert.True(t, rules.allowed("/all_sales_list.php?regionsshow=1"))
+	assert.False(t, rules.allowed("/private/account"))
+	assert.True(t, rules.allowed("/private/sales/123"), "longer allow wins")
+	assert.False(t, rules.allowed("/flyers/sale.pdf"))
+	assert.True(t, rules.allowed("/flyers/sale.pdf?page=2"), "$ anchors the end")
+	assert.Equal(t, 5*time.Second, rules.crawlDelay)
+
+	// Unknown agents get the * group
+	assert.False(t, parseRobots(strings.NewReader(testRobots), "UnknownBot").allowed("/"))
+
+	// No groups at all
+	assert.True(t, parseRobots(strings.NewReader(""), "EstateSaleFinderBot").allowed("/anything"))
+}
+
+// TestRetryAfter tests both Retry-After formats
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 30*time.Second, retryAfter("30", now))
+	assert.Equal(t, 90*time.Second, retryAfter("Fri, 16 Oct 2026 12:01:30 GMT", now))
+	assert.Equal(t, time.Duration(0), retryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), retryAfter("", now))
+}
+
+// testFetcher returns a fetcher for srv that records sleeps instead of sleeping
+func testFetcher(srv *httptest.Server) (*Fetcher, *[]time.Duration) {
+	f := NewFetcherWithClient(srv.Client())
+	f.hostRate = rate.Inf
+	var slept []time.Duration
+	f.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return f, &slept
+}
+
+// TestFetcherConditional tests sending a response's validators back
+func TestFetcherConditional(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Fri, 16 Oct 2026 12:00:00 GMT")
+		w.Write([]byte("sales"))
+	}))
+	defer srv.Close()
+	f, _ := testFetcher(srv)
+
+	resp, err := f.Get(context.Background(), srv.URL + "/all_sales_list.php")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	v := ValidatorsFrom(resp)
+	assert.Equal(t, Validators{ETag: `"v1"`, LastModified: "Fri, 16 Oct 2026 12:00:00 GMT"}, v)
+
+	resp, err = f.GetConditional(context.Background(), srv.URL+"/all_sales_list.php", v)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+}
+
+// TestFetcherCancelled tests that a cancelled request stops without caching robots.txt
+func TestFetcherCancelled(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	f, _ := testFetcher(srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.Get(ctx, srv.URL+"/sales")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, requests)
+
+	resp, err := f.Get(context.Background(), srv.URL+"/sales")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "robots.txt wasn't cached as unreachable")
+
+	// Cancelled while blocked by Retry-After
+	assert.ErrorIs(t, sleepContext(ctx, time.Hour), context.Canceled)
+}
+
+// TestFetcherHonorsRobots tests that disallowed URLs are never requested
+func TestFetcherHonorsRobots(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	var userAgents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		userAgents = append(userAgents, r.UserAgent())
+		mu.Unlock()
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte("User-agent: *\nDisallow: /admin/\n"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	f, _ := testFetcher(srv)
+
+	resp, err := f.Get(context.Background(), srv.URL + "/sales")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = f.Get(context.Background(), srv.URL + "/admin/users")
+	assert.True(t, errors.Is(err, ErrDisallowed))
+
+	resp, err = f.Get(context.Background(), srv.URL + "/sales?page=2")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"/robots.txt", "/sales", "/sales"}, requested, "robots.txt fetched once and cached")
+	for _, ua := range userAgents {
+		assert.Equal(t, DefaultUserAgent, ua)
+	}
+}
+
+// TestFetcherServerErrorRobots tests that an unreachable robots.txt blocks the host
+func TestFetcherServerErrorRobots(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	f, _ := testFetcher(srv)
+	_, err := f.Get(context.Background(), srv.URL + "/sales")
+	assert.True(t, errors.Is(err, ErrDisallowed))
+}
+
+// TestFetcherRetryAfter tests backing off on 429 and 503
+func TestFetcherRetryAfter(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		calls++
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	f, slept := testFetcher(srv)
+	resp, err := f.Get(context.Background(), srv.URL + "/sales")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, calls)
+	require.Len(t, *slept, 2)
+	assert.InDelta(t, float64(7*time.Second), float64((*slept)[0]), float64(time.Second), "Retry-After honored")
+	assert.InDelta(t, float64(4*time.Second), float64((*slept)[1]), float64(time.Second), "exponential backoff without Retry-After")
+}
+
+// TestFetcherGivesUpOnLongRetryAfter tests that a long Retry-After fails fast
+func TestFetcherGivesUpOnLongRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f, slept := testFetcher(srv)
+	resp, err := f.Get(context.Background(), srv.URL + "/sales")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Empty(t, *slept)
+}
+
+// TestFetcherCrawlDelay tests that crawl-delay slows the host's limiter
+func TestFetcherCrawlDelay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte("User-agent: *\nCrawl-delay: 10\n"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	f := NewFetcherWithClient(srv.Client())
+	u, err := url.Parse(srv.URL + "/sales")
+	require.NoError(t, err)
+	host := f.host(u)
+
+	rules, err := f.robots(context.Background(), u, host)
+	require.NoError(t, err)
+	assert.True(t, rules.allowed(u.RequestURI()))
+	assert.Equal(t, rate.Every(10*time.Second), host.limiter.Limit())
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+)
+
+// scrapeFlight runs at most one scrape per key at a time and gives its result to every
+// caller that asked while it ran. The scrape runs on its own goroutine with its own
+// context: it's cancelled once every caller has gone (e.g. all clients disconnected),
+// unless one of them timed out waiting, in which case it finishes to refresh the cache.
+type scrapeFlight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+
+	scrapes      atomic.Int64
+	deduplicated atomic.Int64
+	timedOut     atomic.Int64
+}
+
+// flightCall is one in-progress scrape
+type flightCall struct {
+	done  chan struct{} // Closed once sales and err are set
+	sales []listing.ScrapedListing
+	err   error
+
+	// Guarded by scrapeFlight.mu
+	waiters int                // Callers still waiting
+	finish  bool               // A caller timed out; don't cancel when the rest leave
+	cancel  context.CancelFunc // Cancels the scrape's context
+}
+
+// do returns the result of fn for key, joining a scrape already in progress if there is one.
+// It returns early if ctx is done or timeout passes first.
+func (g *scrapeFlight) do(ctx context.Context, key string, timeout time.Duration, fn func(ctx context.Context) ([]listing.ScrapedListing, error)) ([]listing.ScrapedListing, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	c, ok := g.calls[key]
+	if ok {
+		g.deduplicated.Add(1)
+		c.waiters++
+	} else {
+		scrapeCtx, cancel := context.WithCancel(context.Background())
+		c = &flightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = c
+		g.scrapes.Add(1)
+		go g.run(scrapeCtx, key, c, fn)
+	}
+	g.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-c.done:
+		return c.sales, c.err
+	case <-timer.C:
+		g.timedOut.Add(1)
+		g.leave(key, c, true)
+		return nil, fmt.Errorf("gave up waiting for scrape of %s after %v", key, timeout)
+	case <-ctx.Done():
+		g.leave(key, c, false)
+		return nil, fmt.Errorf("stopped waiting for scrape of %s: %w", key, ctx.Err())
+	}
+}
+
+// leave drops a caller that stopped waiting, cancelling the scrape if nobody else wants it
+func (g *scrapeFlight) leave(key string, c *flightCall, timedOut bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c.waiters--
+	if timedOut {
+		c.finish = true
+	}
+	if c.waiters == 0 && !c.finish {
+		c.cancel()
+		// Later callers start a fresh scrape rather than join a cancelled one
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+	}
+}
+
+// run performs the scrape and releases every waiter
+func (g *scrapeFlight) run(ctx context.Context, key string, c *flightCall, fn func(ctx context.Context) ([]listing.ScrapedListing, error)) {
+	c.sales, c.err = fn(ctx)
+	c.cancel()
+
+	g.mu.Lock()
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	close(c.done)
+}
+
+// stats returns the counters since the process started
+func (g *scrapeFlight) stats() scrape.DedupStats {
+	return scrape.DedupStats{
+		Scrapes:      g.scrapes.Load(),
+		Deduplicated: g.deduplicated.Load(),
+		TimedOut:     g.timedOut.Load(),
+	}
+}
@@ -0,0 +1,217 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitFor polls cond until it's true or a second has passed
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestScrapeFlightSharesResult tests that every concurrent caller gets the one scrape's result
+func TestScrapeFlightSharesResult(t *testing.T) {
+	var g scrapeFlight
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fn := func(ctx context.Context) ([]listing.ScrapedListing, error) {
+		calls.Add(1)
+		<-release
+		return []listing.ScrapedListing{{ExternalID: "a"}, {ExternalID: "b"}}, nil
+	}
+
+	const callers = 50
+	results := make([][]listing.ScrapedListing, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = g.do(context.Background(), "sales:portland:OR", time.Minute, fn)
+		}(i)
+	}
+
+	waitFor(t, func() bool { return g.deduplicated.Load() == callers-1 })
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i])
+		assert.Len(t, results[i], 2, "caller %d", i)
+	}
+	assert.Equal(t, int64(1), g.stats().Scrapes)
+	assert.Equal(t, int64(callers-1), g.stats().Deduplicated)
+
+	// Finished scrapes aren't reused
+	_, err := g.do(context.Background(), "sales:portland:OR", time.Minute, func(ctx context.Context) ([]listing.ScrapedListing, error) {
+		return nil, errors.New("down")
+	})
+	assert.EqualError(t, err, "down")
+	assert.Equal(t, int64(2), g.stats().Scrapes)
+}
+
+// TestScrapeFlightWaitTimeout tests that a caller can stop waiting without cancelling the scrape
+func TestScrapeFlightWaitTimeout(t *testing.T) {
+	var g scrapeFlight
+	release := make(chan struct{})
+	var scrapeErr error
+	done := make(chan struct{})
+	fn := func(ctx context.Context) ([]listing.ScrapedListing, error) {
+		defer close(done)
+		select {
+		case <-release:
+		case <-ctx.Done():
+			scrapeErr = ctx.Err()
+		}
+		return []listing.ScrapedListing{{ExternalID: "a"}}, nil
+	}
+
+	_, err := g.do(context.Background(), "sales:portland:OR", 10*time.Millisecond, fn)
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), g.stats().TimedOut)
+
+	// A later caller joins the same scrape and gets its result
+	go func() {
+		waitFor(t, func() bool { return g.deduplicated.Load() == 1 })
+		close(release)
+	}()
+	sales, err := g.do(context.Background(), "sales:portland:OR", time.Minute, fn)
+	require.NoError(t, err)
+	assert.Len(t, sales, 1)
+	<-done
+	assert.NoError(t, scrapeErr, "not cancelled")
+}
+
+// TestScrapeFlightCancel tests that the scrape is cancelled once every caller has gone
+func TestScrapeFlightCancel(t *testing.T) {
+	var g scrapeFlight
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	fn := func(ctx context.Context) ([]listing.ScrapedListing, error) {
+		close(started)
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return nil, ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() {
+		_, err := g.do(ctx1, "sales:portland:OR", time.Minute, fn)
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, err := g.do(ctx2, "sales:portland:OR", time.Minute, fn)
+		errs <- err
+	}()
+	waitFor(t, func() bool { return g.deduplicated.Load() == 1 })
+
+	// One client leaving isn't enough
+	cancel1()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	select {
+	case <-cancelled:
+		t.Fatal("scrape cancelled while a caller was still waiting")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel2()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	assert.ErrorIs(t, <-cancelled, context.Canceled)
+}
+
+// blockingSource returns its listings once released, counting fetches
+type blockingSource struct {
+	fakeSource
+	release chan struct{}
+	fetches atomic.Int32
+}
+
+func (b *blockingSource) FetchListings(ctx context.Context, city, state string) (*FetchResult, error) {
+	b.fetches.Add(1)
+	<-b.release
+	return &FetchResult{Listings: b.listings, RowsSeen: len(b.listings), HTTPStatus: 200}, nil
+}
+
+// TestGetFeedByLocationConcurrent tests that concurrent cache misses all get the scraped sales
+func TestGetFeedByLocationConcurrent(t *testing.T) {
+	src := &blockingSource{
+		fakeSource: fakeSource{name: "slow", state: "OR", listings: []listing.ScrapedListing{{ExternalID: "slow-1"}, {ExternalID: "slow-2"}}},
+		release:    make(chan struct{}),
+	}
+	s := &ScraperService{repo: &fakeListingRepo{}, cacheTTL: 6 * time.Hour}
+	s.RegisterSource(src)
+
+	const requests = 20
+	feeds := make([]*listing.ScrapedFeed, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			feed, err := s.GetFeedByLocation(context.Background(), "Portland", "OR")
+			assert.NoError(t, err)
+			feeds[i] = feed
+		}(i)
+	}
+
+	waitFor(t, func() bool { return s.DedupStats().Deduplicated == requests-1 })
+	close(src.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), src.fetches.Load())
+	for i, feed := range feeds {
+		require.NotNil(t, feed)
+		assert.Len(t, feed.Listings, 2, "request %d", i)
+	}
+}
+
+// TestSchedulerJoinsRequestScrape tests that a scheduled refresh of a location a request
+// is already scraping waits on that scrape instead of starting another
+func TestSchedulerJoinsRequestScrape(t *testing.T) {
+	src := &blockingSource{
+		fakeSource: fakeSource{name: "slow", state: "OR", listings: []listing.ScrapedListing{{ExternalID: "slow-1"}}},
+		release:    make(chan struct{}),
+	}
+	s := &ScraperService{}
+	s.RegisterSource(src)
+
+	requestErr := make(chan error, 1)
+	go func() {
+		_, err := s.refreshShared(context.Background(), "Portland", "OR", scrape.TriggerRequest, time.Minute)
+		requestErr <- err
+	}()
+	waitFor(t, func() bool { return src.fetches.Load() == 1 })
+
+	sc := NewScheduler(s, SchedulerConfig{Locations: []Location{{City: "portland", State: "or"}}})
+	schedulerErr := make(chan error, 1)
+	go func() { schedulerErr <- sc.RunOnce(context.Background()) }()
+	waitFor(t, func() bool { return s.DedupStats().Deduplicated == 1 })
+
+	close(src.release)
+	require.NoError(t, <-requestErr)
+	require.NoError(t, <-schedulerErr)
+	assert.Equal(t, int32(1), src.fetches.Load())
+	assert.Equal(t, int64(1), s.DedupStats().Scrapes)
+}
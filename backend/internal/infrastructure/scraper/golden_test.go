@@ -0,0 +1,116 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/httpreplay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+var (
+	record = flag.Bool("record", false, "fetch from the live sites and re-record testdata/fixtures (then run with -update)")
+	update = flag.Bool("update", false, "rewrite the golden files from the current parser output")
+)
+
+// goldenSource is a source run against HTTP fixtures: recorded from the live site under
+// testdata/fixtures, or hand-written under testdata/synthetic
+type goldenSource struct {
+	name       string    // Fixture and golden file name
+	synthetic  bool      // Hand-written fixtures; -record leaves them alone
+	recordedAt time.Time // Clock the fixtures were recorded or written for (for year inference)
+	fetch      func(client *http.Client, now time.Time) (*FetchResult, error)
+}
+
+// dir returns the testdata directory holding the source's fixtures/ and golden/
+func (g goldenSource) dir() string {
+	if g.synthetic {
+		return filepath.Join("testdata", "synthetic")
+	}
+	return "testdata"
+}
+
+var goldenSources = []goldenSource{
+	{
+		name:  "estatesale-finder",
+		fetch: fetchEstateSaleFinderGolden, // Not recorded yet: set recordedAt once it is
+	},
+	{
+		name:       "estatesale-finder",
+		synthetic:  true,
+		recordedAt: time.Date(2025, time.October, 20, 12, 0, 0, 0, saleLocation),
+		fetch:      fetchEstateSaleFinderGolden,
+	},
+}
+
+func fetchEstateSaleFinderGolden(client *http.Client, now time.Time) (*FetchResult, error) {
+	s := &EstateSaleFinderScraper{
+		fetcher: goldenFetcher(client),
+		now:     func() time.Time { return now },
+		details: &detailCache{ttl: DefaultDetailTTL, entries: map[string]cachedDetail{}},
+	}
+	return s.ScrapePortlandSales(context.Background())
+}
+
+// goldenFetcher wraps client; replays skip the politeness delays, recordings keep them
+func goldenFetcher(client *http.Client) *Fetcher {
+	f := NewFetcherWithClient(client)
+	if !*record {
+		f.hostRate = rate.Inf
+	}
+	return f
+}
+
+// TestGoldenSources tests each source's parsed output against its golden file,
+// replaying the HTTP responses in its fixtures directory
+func TestGoldenSources(t *testing.T) {
+	for _, src := range goldenSources {
+		testName := src.name
+		if src.synthetic {
+			testName = "synthetic/" + src.name
+		}
+		t.Run(testName, func(t *testing.T) {
+			fixtures := filepath.Join(src.dir(), "fixtures", src.name)
+			mode := httpreplay.ModeReplay
+			now := src.recordedAt
+			switch {
+			case *record && src.synthetic:
+				t.Skip("hand-written fixtures aren't re-recorded")
+			case *record:
+				require.NoError(t, os.RemoveAll(fixtures))
+				mode = httpreplay.ModeRecord
+				now = time.Now()
+				t.Logf("Recording %s at %s; update recordedAt to match", src.name, now.Format(time.RFC3339))
+			default:
+				if _, err := os.Stat(fixtures); os.IsNotExist(err) {
+					t.Skipf("no recorded fixtures in %s (run with -record)", fixtures)
+				}
+			}
+
+			result, err := src.fetch(httpreplay.NewClient(fixtures, mode), now)
+			require.NoError(t, err)
+
+			got, err := json.MarshalIndent(result, "", "  ")
+			require.NoError(t, err)
+			got = append(got, '\n')
+
+			golden := filepath.Join(src.dir(), "golden", src.name+".json")
+			if *update || *record {
+				require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+				require.NoError(t, os.WriteFile(golden, got, 0o644))
+			}
+
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err, "missing golden file (run with -update)")
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
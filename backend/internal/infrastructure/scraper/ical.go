@@ -0,0 +1,524 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// Recurrence limits: a sale runs for days, so an open-ended rule is cut off early
+const (
+	maxOccurrences    = 14  // Sessions kept per recurring event
+	maxRecurrenceDays = 730 // How far past DTSTART a rule is expanded
+)
+
+// ICalSource reads sales from a company's iCalendar (.ics) feed. Each VEVENT is a
+// sale; a recurring event (e.g. 9am-3pm daily for three days) is one sale with a
+// session per occurrence.
+type ICalSource struct {
+	config  FeedConfig
+	fetcher *Fetcher
+	loc     *time.Location // For floating times and all-day events
+	now     func() time.Time
+}
+
+// NewICalSource creates a source for config that fetches through fetcher
+func NewICalSource(config FeedConfig, fetcher *Fetcher) (*ICalSource, error) {
+	loc, err := sourceLocation(config.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	return &ICalSource{config: config, fetcher: fetcher, loc: loc, now: time.Now}, nil
+}
+
+// Name implements Source
+func (s *ICalSource) Name() string {
+	return s.config.Name
+}
+
+// Covers implements Source
+func (s *ICalSource) Covers(city, state string) bool {
+	return s.config.Coverage.Covers(city, state)
+}
+
+// FetchListings implements Source. Events that are over, cancelled or can't be read
+// are left out.
+func (s *ICalSource) FetchListings(ctx context.Context, city, state string) (*FetchResult, error) {
+	status, body, err := fetchFeed(ctx, s.fetcher, s.config.URL)
+	result := &FetchResult{HTTPStatus: status}
+	if err != nil {
+		return result, err
+	}
+
+	events, err := parseICal(body)
+	if err != nil {
+		return result, err
+	}
+	result.RowsSeen = len(events)
+
+	now := s.now()
+	seen := map[string]bool{}
+	for _, event := range events {
+		scraped, err := s.eventListing(event, now)
+		if err != nil {
+			log.Printf("✗ %s: skipping event: %v", s.Name(), err)
+			result.SkippedIDs = append(result.SkippedIDs, externalID(s.Name(), event.key()))
+			continue
+		}
+		if scraped == nil || seen[scraped.ExternalID] {
+			continue
+		}
+		seen[scraped.ExternalID] = true
+		result.Listings = append(result.Listings, *scraped)
+	}
+
+	log.Printf("✓ Scraped %d sales from %s (%d events)", len(result.Listings), s.Name(), len(events))
+	return result, nil
+}
+
+// eventListing maps a VEVENT to a listing. Events that are cancelled or over return nil, nil.
+func (s *ICalSource) eventListing(event icalEvent, now time.Time) (*listing.ScrapedListing, error) {
+	if strings.EqualFold(event.text("STATUS"), "CANCELLED") {
+		return nil, nil
+	}
+	if _, ok := event.get("RECURRENCE-ID"); ok {
+		return nil, nil // A changed occurrence; the series comes from its master event
+	}
+
+	title := event.text("SUMMARY")
+	if title == "" {
+		return nil, fmt.Errorf("event without a SUMMARY")
+	}
+
+	occurrences, timed, err := s.occurrences(event)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", title, err)
+	}
+
+	// Only what's still to come
+	var upcoming []listing.Session
+	for _, occ := range occurrences {
+		if !occ.End.Before(now) {
+			upcoming = append(upcoming, occ)
+		}
+	}
+	if len(upcoming) == 0 {
+		return nil, nil
+	}
+
+	base, _ := url.Parse(s.config.URL)
+	sourceURL := resolveURL(base, event.text("URL"))
+	if sourceURL == "" {
+		sourceURL = s.config.URL
+	}
+
+	scraped := &listing.ScrapedListing{
+		ExternalID:  externalID(s.Name(), event.key()),
+		Title:       title,
+		Description: htmlText(event.text("DESCRIPTION")),
+		EventType:   listing.EventTypeFromCategories(event.categories()),
+		StartDate:   upcoming[0].Start,
+		EndDate:     upcoming[len(upcoming)-1].End,
+		SourceName:  s.Name(),
+		SourceURL:   sourceURL,
+		ScrapedAt:   now,
+		CachedAt:    now,
+	}
+	switch {
+	case !timed:
+	case len(occurrences) == 1:
+		scraped.Sessions = dailySessions(scraped.StartDate, scraped.EndDate)
+	default:
+		scraped.Sessions = upcoming
+	}
+
+	// LOCATION is often "Smith Estate, 123 Main St, ..."; prefer a line that's an address
+	location := event.text("LOCATION")
+	if parsed, ok := findAddress(location + "\n" + htmlText(event.text("DESCRIPTION"))); ok {
+		applyAddress(scraped, parsed)
+	} else {
+		applyAddressText(scraped, location)
+	}
+	if lat, lng, ok := strings.Cut(event.text("GEO"), ";"); ok {
+		latitude, latErr := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+		longitude, lngErr := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+		if latErr == nil && lngErr == nil && (latitude != 0 || longitude != 0) {
+			scraped.Latitude, scraped.Longitude = latitude, longitude
+			scraped.GeoPrecision = listing.GeoPrecisionRooftop
+		}
+	}
+	applyDefaults(scraped, s.config.Coverage)
+
+	for _, attach := range event["ATTACH"] {
+		if strings.HasPrefix(strings.ToLower(attach.params["FMTTYPE"]), "image/") || imageExtRegex.MatchString(attach.value) {
+			if imgURL := resolveURL(base, attach.value); imgURL != "" {
+				scraped.ImageURLs = append(scraped.ImageURLs, imgURL)
+			}
+		}
+	}
+	if len(scraped.ImageURLs) > 0 {
+		scraped.ThumbnailURL = scraped.ImageURLs[0]
+	}
+
+	if organizer, ok := event.get("ORGANIZER"); ok {
+		company := &listing.CompanyContact{
+			Name:  organizer.params["CN"],
+			Email: strings.TrimPrefix(strings.TrimPrefix(organizer.value, "mailto:"), "MAILTO:"),
+		}
+		if company.Name != "" || company.Email != "" {
+			scraped.Company = company
+		}
+	}
+
+	return scraped, nil
+}
+
+// occurrences returns when each occurrence of an event runs. timed is false for
+// all-day events and events without a published end, which run to the end of the day.
+func (s *ICalSource) occurrences(event icalEvent) ([]listing.Session, bool, error) {
+	startProp, ok := event.get("DTSTART")
+	if !ok {
+		return nil, false, fmt.Errorf("missing DTSTART")
+	}
+	start, allDay, err := icalTime(startProp, s.loc)
+	if err != nil {
+		return nil, false, fmt.Errorf("DTSTART: %w", err)
+	}
+
+	end := start
+	hasEnd := true
+	if endProp, ok := event.get("DTEND"); ok {
+		if end, _, err = icalTime(endProp, s.loc); err != nil {
+			return nil, false, fmt.Errorf("DTEND: %w", err)
+		}
+	} else if durProp, ok := event.get("DURATION"); ok {
+		days, clock, err := icalDuration(durProp.value)
+		if err != nil {
+			return nil, false, fmt.Errorf("DURATION: %w", err)
+		}
+		end = start.AddDate(0, 0, days).Add(clock)
+	} else if allDay {
+		end = start.AddDate(0, 0, 1)
+	} else {
+		hasEnd = false
+	}
+	if end.Before(start) {
+		return nil, false, fmt.Errorf("ends before it starts")
+	}
+
+	// Each occurrence ends as many days after it starts, at the same wall-clock time, as
+	// the first (so one across a DST change isn't an hour off)
+	end = end.In(start.Location())
+	endDays := int(math.Round(dayOf(end).Sub(dayOf(start)).Hours() / 24))
+	endHour, endMinute, endSecond := end.Clock()
+
+	starts := []time.Time{start}
+	if rule, ok := event.get("RRULE"); ok {
+		if starts, err = expandRRule(start, rule.value, s.exdates(event)); err != nil {
+			return nil, false, err
+		}
+	}
+
+	timed := hasEnd && !allDay
+	sessions := make([]listing.Session, 0, len(starts))
+	for _, occ := range starts {
+		occEnd := time.Date(occ.Year(), occ.Month(), occ.Day()+endDays, endHour, endMinute, endSecond, 0, occ.Location())
+		switch {
+		case allDay:
+			occEnd = occEnd.Add(-time.Second) // DTEND is exclusive: end at 23:59:59 the day before
+		case !hasEnd:
+			occEnd = time.Date(occ.Year(), occ.Month(), occ.Day(), 23, 59, 59, 0, occ.Location())
+		}
+		sessions = append(sessions, listing.Session{Start: occ, End: occEnd})
+	}
+	return sessions, timed, nil
+}
+
+// exdates returns an event's excluded occurrences
+func (s *ICalSource) exdates(event icalEvent) []icalExdate {
+	var exdates []icalExdate
+	for _, prop := range event["EXDATE"] {
+		for _, value := range strings.Split(prop.value, ",") {
+			t, allDay, err := icalTime(icalProperty{params: prop.params, value: value}, s.loc)
+			if err == nil {
+				exdates = append(exdates, icalExdate{t: t, allDay: allDay})
+			}
+		}
+	}
+	return exdates
+}
+
+// icalExdate is an excluded occurrence; an all-day EXDATE excludes its whole day
+type icalExdate struct {
+	t      time.Time
+	allDay bool
+}
+
+func (e icalExdate) excludes(t time.Time) bool {
+	if e.allDay {
+		return dayOf(t).Equal(dayOf(e.t.In(t.Location())))
+	}
+	return e.t.Equal(t)
+}
+
+// expandRRule returns the starts of a recurring event. FREQ=DAILY and FREQ=WEEKLY
+// (with INTERVAL, COUNT, UNTIL and weekly BYDAY) cover how sales publish multi-day
+// schedules; other rules are rejected.
+func expandRRule(start time.Time, rule string, exdates []icalExdate) ([]time.Time, error) {
+	parts := map[string]string{}
+	for _, part := range strings.Split(rule, ";") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			parts[strings.ToUpper(strings.TrimSpace(k))] = strings.ToUpper(strings.TrimSpace(v))
+		}
+	}
+
+	freq := parts["FREQ"]
+	if freq != "DAILY" && freq != "WEEKLY" {
+		return nil, fmt.Errorf("unsupported RRULE frequency %q", freq)
+	}
+
+	interval := 1
+	if n, err := strconv.Atoi(parts["INTERVAL"]); err == nil && n > 0 {
+		interval = n
+	}
+	count := 0
+	if n, err := strconv.Atoi(parts["COUNT"]); err == nil && n > 0 {
+		count = n
+	}
+	var until time.Time
+	if v := parts["UNTIL"]; v != "" {
+		t, allDay, err := icalTime(icalProperty{value: v}, start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("RRULE UNTIL: %w", err)
+		}
+		if allDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Second) // The whole UNTIL day
+		}
+		until = t
+	}
+
+	byDay := map[time.Weekday]bool{}
+	if freq == "WEEKLY" {
+		for _, day := range strings.Split(parts["BYDAY"], ",") {
+			day = strings.TrimLeft(day, "+-0123456789")
+			if wd, ok := icalWeekdays[day]; ok {
+				byDay[wd] = true
+			}
+		}
+		if len(byDay) == 0 {
+			byDay[start.Weekday()] = true
+		}
+	}
+
+	daysSinceMonday := (int(start.Weekday()) + 6) % 7
+	var starts []time.Time
+	generated := 0 // COUNT includes occurrences removed by EXDATE
+	for i := 0; i < maxRecurrenceDays && len(starts) < maxOccurrences; i++ {
+		if freq == "DAILY" && i%interval != 0 {
+			continue
+		}
+		t := start.AddDate(0, 0, i)
+		if freq == "WEEKLY" && (((i+daysSinceMonday)/7)%interval != 0 || !byDay[t.Weekday()]) {
+			continue
+		}
+		if (!until.IsZero() && t.After(until)) || (count > 0 && generated == count) {
+			break
+		}
+		generated++
+		if excluded(t, exdates) {
+			continue
+		}
+		starts = append(starts, t)
+	}
+	return starts, nil
+}
+
+func excluded(t time.Time, exdates []icalExdate) bool {
+	for _, ex := range exdates {
+		if ex.excludes(t) {
+			return true
+		}
+	}
+	return false
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// icalTime parses a DATE or DATE-TIME value into loc. Floating times are read in loc;
+// a TZID that isn't an IANA name (e.g. Outlook's "Pacific Standard Time") is too.
+func icalTime(prop icalProperty, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	zone := loc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if z, err := time.LoadLocation(tzid); err == nil {
+			zone = z
+		}
+	}
+	layout := "20060102T150405"
+	if strings.HasSuffix(value, "Z") {
+		layout, zone = "20060102T150405Z", time.UTC
+	}
+	t, err := time.ParseInLocation(layout, value, zone)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	return t.In(loc), false, nil
+}
+
+// durationRegex matches an iCalendar DURATION, e.g. "PT6H", "P1DT2H30M", "P2W"
+var durationRegex = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// icalDuration splits a DURATION into calendar days and clock time
+func icalDuration(value string) (int, time.Duration, error) {
+	m := durationRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, 0, fmt.Errorf("invalid duration %q", value)
+	}
+	n := func(s string) int {
+		v, _ := strconv.Atoi(s)
+		return v
+	}
+	days := n(m[1])*7 + n(m[2])
+	clock := time.Duration(n(m[3]))*time.Hour + time.Duration(n(m[4]))*time.Minute + time.Duration(n(m[5]))*time.Second
+	return days, clock, nil
+}
+
+// icalProperty is one content line: NAME;PARAM=value:VALUE
+type icalProperty struct {
+	params map[string]string // Upper-case names, unquoted values
+	value  string            // Raw (still escaped) value
+}
+
+// icalEvent is a VEVENT's properties by upper-case name
+type icalEvent map[string][]icalProperty
+
+// get returns the first property with a name
+func (e icalEvent) get(name string) (icalProperty, bool) {
+	if props := e[name]; len(props) > 0 {
+		return props[0], true
+	}
+	return icalProperty{}, false
+}
+
+// text returns a TEXT property's unescaped value ("" if absent)
+func (e icalEvent) text(name string) string {
+	prop, _ := e.get(name)
+	return strings.TrimSpace(icalUnescaper.Replace(prop.value))
+}
+
+// categories returns the event's CATEGORIES, which may be several comma-separated
+// lists (an escaped "\," is part of a category)
+func (e icalEvent) categories() []string {
+	var categories []string
+	for _, prop := range e["CATEGORIES"] {
+		for _, category := range strings.Split(strings.ReplaceAll(prop.value, `\,`, "\x00"), ",") {
+			category = icalUnescaper.Replace(strings.ReplaceAll(category, "\x00", `\,`))
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+	return categories
+}
+
+// key identifies the event across fetches: its UID, else its summary and start
+func (e icalEvent) key() string {
+	if uid := e.text("UID"); uid != "" {
+		return uid
+	}
+	start, _ := e.get("DTSTART")
+	return e.text("SUMMARY") + "|" + start.value
+}
+
+var icalUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+// parseICal returns the VEVENTs in an iCalendar document. Components nested in an
+// event (e.g. VALARM) are ignored.
+func parseICal(data []byte) ([]icalEvent, error) {
+	// Unfold: a line starting with a space or tab continues the previous one
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+
+	var events []icalEvent
+	var event icalEvent
+	calendar := false
+	nested := 0 // Depth of components inside the current event
+	for _, line := range lines {
+		name, prop, ok := parseICalLine(line)
+		if !ok {
+			continue
+		}
+		component := strings.ToUpper(strings.TrimSpace(prop.value))
+		switch {
+		case name == "BEGIN" && component == "VCALENDAR":
+			calendar = true
+		case name == "BEGIN" && event == nil && component == "VEVENT":
+			event = icalEvent{}
+		case name == "BEGIN" && event != nil:
+			nested++
+		case name == "END" && event != nil && nested > 0:
+			nested--
+		case name == "END" && event != nil && component == "VEVENT":
+			events = append(events, event)
+			event = nil
+		case event != nil && nested == 0:
+			event[name] = append(event[name], prop)
+		}
+	}
+
+	if !calendar {
+		return nil, fmt.Errorf("not an iCalendar feed")
+	}
+	return events, nil
+}
+
+// parseICalLine splits a content line into its name and property
+func parseICalLine(line string) (string, icalProperty, bool) {
+	colon := -1
+	quoted := false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", icalProperty{}, false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icalProperty{params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), prop, true
+}
@@ -0,0 +1,223 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const icalFeed = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Acme//Sales//EN
+BEGIN:VEVENT
+UID:sale-101@acme.example.com
+SUMMARY:Mid-Century Estate Sale
+DESCRIPTION:Teak furniture\, vintage kitchenware\nNumbers at 8am
+LOCATION:The Smith Estate\, 1234 SE Main St\, Portland\, OR 97214
+GEO:45.5122;-122.6587
+CATEGORIES:Antiques\, Art,Sales
+CATEGORIES:Estate Sale
+URL:/sales/101
+ATTACH;FMTTYPE=image/jpeg:https://cdn.example.com/101.jpg
+ORGANIZER;CN="Acme Estate Sales":mailto:sales@acme.example.com
+DTSTART;TZID=America/Los_Angeles:20250606T090000
+DTEND;TZID=America/Los_Angeles:20250606T150000
+RRULE:FREQ=DAILY;COUNT=3
+EXDATE;TZID=America/Los_Angeles:20250607T090000
+BEGIN:VALARM
+ACTION:DISPLAY
+DESCRIPTION:Reminder
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:sale-102@acme.example.com
+SUMMARY:Garage Sale
+CATEGORIES:Moving Sale
+LOCATION:TBA Beaverton\, OR 97005
+DTSTART;VALUE=DATE:20250613
+DTEND;VALUE=DATE:20250615
+END:VEVENT
+BEGIN:VEVENT
+UID:sale-103@acme.example.com
+SUMMARY:Weekend Sale
+DESCRIPTION:Long description that is folded
+  across two lines
+DTSTART:20250620T160000Z
+DTEND:20250622T220000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:sale-104@acme.example.com
+SUMMARY:Cancelled Sale
+STATUS:CANCELLED
+DTSTART:20250606T160000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:sale-099@acme.example.com
+SUMMARY:Last Month's Sale
+DTSTART:20250501T160000Z
+DTEND:20250501T220000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:sale-105@acme.example.com
+SUMMARY:Monthly Sale
+DTSTART:20250606T160000Z
+RRULE:FREQ=MONTHLY
+END:VEVENT
+END:VCALENDAR
+`
+
+// TestICalSourceFetchListings tests mapping VEVENTs, including recurring ones, to listings
+func TestICalSourceFetchListings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sales.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(strings.ReplaceAll(icalFeed, "\n", "\r\n")))
+	}))
+	defer srv.Close()
+
+	fetcher, _ := testFetcher(srv)
+	src, err := NewICalSource(FeedConfig{Name: "Acme Estate Sales", URL: srv.URL + "/sales.ics", Coverage: Coverage{State: "OR"}}, fetcher)
+	require.NoError(t, err)
+	src.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, saleLocation) }
+
+	result, err := src.FetchListings(context.Background(), "Portland", "OR")
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.RowsSeen)
+	assert.Len(t, result.SkippedIDs, 1, "the MONTHLY rule isn't supported")
+	require.Len(t, result.Listings, 3, "cancelled and past events are left out")
+
+	// Daily for three days, minus the excluded Saturday
+	sale := result.Listings[0]
+	assert.Equal(t, "Mid-Century Estate Sale", sale.Title)
+	assert.Equal(t, "Teak furniture, vintage kitchenware\nNumbers at 8am", sale.Description)
+	assert.Equal(t, "1234 SE Main St", sale.Address)
+	assert.Equal(t, "Portland", sale.City)
+	assert.Equal(t, "97214", sale.ZipCode)
+	assert.InDelta(t, 45.5122, sale.Latitude, 1e-9)
+	assert.Equal(t, srv.URL+"/sales/101", sale.SourceURL)
+	assert.Equal(t, []string{"https://cdn.example.com/101.jpg"}, sale.ImageURLs)
+	assert.Equal(t, listing.EventEstateSale, sale.EventType, "the first category that is a sale type")
+	require.NotNil(t, sale.Company)
+	assert.Equal(t, "Acme Estate Sales", sale.Company.Name)
+	assert.Equal(t, "sales@acme.example.com", sale.Company.Email)
+	assert.Equal(t, "2025-06-06T09:00:00-07:00", sale.StartDate.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-08T15:00:00-07:00", sale.EndDate.Format(time.RFC3339))
+	require.Len(t, sale.Sessions, 2)
+	assert.Equal(t, "2025-06-08T09:00:00-07:00", sale.Sessions[1].Start.Format(time.RFC3339))
+
+	// All-day: DTEND is exclusive
+	garage := result.Listings[1]
+	assert.Equal(t, "TBA", garage.Address)
+	assert.Equal(t, "Beaverton", garage.City)
+	assert.Equal(t, listing.EventMovingSale, garage.EventType, "CATEGORIES, not the summary")
+	assert.Equal(t, "2025-06-13T00:00:00-07:00", garage.StartDate.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-14T23:59:59-07:00", garage.EndDate.Format(time.RFC3339))
+	assert.Empty(t, garage.Sessions)
+
+	// One event over several days: open 9am-3pm local each day
+	weekend := result.Listings[2]
+	assert.Equal(t, "Long description that is folded across two lines", weekend.Description)
+	assert.Equal(t, "OR", weekend.State, "defaults to the covered state")
+	assert.Empty(t, weekend.EventType)
+	require.Len(t, weekend.Sessions, 3)
+	assert.Equal(t, "2025-06-21T09:00:00-07:00", weekend.Sessions[1].Start.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-21T15:00:00-07:00", weekend.Sessions[1].End.Format(time.RFC3339))
+}
+
+// TestICalSourceDST tests that sessions keep their wall-clock hours across a DST change,
+// for a recurring event and for a single event spanning several days
+func TestICalSourceDST(t *testing.T) {
+	// Daylight saving ends at 2am on Sunday, Nov 2, 2025
+	events, err := parseICal([]byte(`BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:sale-201@acme.example.com
+SUMMARY:Daily Sale
+DTSTART;TZID=America/Los_Angeles:20251031T090000
+DTEND;TZID=America/Los_Angeles:20251031T150000
+RRULE:FREQ=DAILY;COUNT=3
+END:VEVENT
+BEGIN:VEVENT
+UID:sale-202@acme.example.com
+SUMMARY:Weekend Sale
+DTSTART;TZID=America/Los_Angeles:20251031T090000
+DTEND;TZID=America/Los_Angeles:20251102T150000
+END:VEVENT
+BEGIN:VEVENT
+UID:sale-203@acme.example.com
+SUMMARY:Weekly Sale
+DTSTART;TZID=America/Los_Angeles:20251024T090000
+DTEND;TZID=America/Los_Angeles:20251026T150000
+RRULE:FREQ=WEEKLY;COUNT=2
+END:VEVENT
+END:VCALENDAR
+`))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	src, err := NewICalSource(FeedConfig{Name: "Acme Estate Sales", URL: "https://acme.example.com/sales.ics", Coverage: Coverage{State: "OR"}}, nil)
+	require.NoError(t, err)
+	now := time.Date(2025, 10, 20, 12, 0, 0, 0, saleLocation)
+
+	want := []string{"Oct 31 09:00-15:00 PDT", "Nov 1 09:00-15:00 PDT", "Nov 2 09:00-15:00 PST"}
+	for _, event := range events[:2] {
+		scraped, err := src.eventListing(event, now)
+		require.NoError(t, err)
+		var got []string
+		for _, session := range scraped.Sessions {
+			got = append(got, session.Start.Format("Jan 2 15:04")+"-"+session.End.Format("15:04 MST"))
+		}
+		assert.Equal(t, want, got, scraped.Title)
+	}
+
+	// Fri-Sun weekly: the second weekend ends at 3pm too, not 2pm
+	weekly, err := src.eventListing(events[2], now)
+	require.NoError(t, err)
+	require.Len(t, weekly.Sessions, 2)
+	assert.Equal(t, "2025-10-26T15:00:00-07:00", weekly.Sessions[0].End.Format(time.RFC3339))
+	assert.Equal(t, "2025-11-02T15:00:00-08:00", weekly.Sessions[1].End.Format(time.RFC3339))
+}
+
+// TestExpandRRule tests the recurrence rules sales use
+func TestExpandRRule(t *testing.T) {
+	start := time.Date(2025, 6, 6, 9, 0, 0, 0, saleLocation) // A Friday
+	days := func(starts []time.Time) []int {
+		var out []int
+		for _, s := range starts {
+			out = append(out, s.Day())
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		rule string
+		want []int
+	}{
+		{"daily count", "FREQ=DAILY;COUNT=3", []int{6, 7, 8}},
+		{"daily until date", "FREQ=DAILY;UNTIL=20250608", []int{6, 7, 8}},
+		{"every other day", "FREQ=DAILY;INTERVAL=2;COUNT=3", []int{6, 8, 10}},
+		{"weekends", "FREQ=WEEKLY;BYDAY=FR,SA,SU;COUNT=6", []int{6, 7, 8, 13, 14, 15}},
+		{"fortnightly", "FREQ=WEEKLY;INTERVAL=2;COUNT=3", []int{6, 20, 4}},
+		{"open-ended", "FREQ=DAILY", []int{6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starts, err := expandRRule(start, tt.rule, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, days(starts))
+		})
+	}
+
+	_, err := expandRRule(start, "FREQ=YEARLY", nil)
+	assert.Error(t, err)
+}
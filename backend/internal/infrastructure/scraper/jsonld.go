@@ -0,0 +1,460 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// JSONLDConfig configures a site that publishes its sales as schema.org Event JSON-LD
+type JSONLDConfig struct {
+	Name     string   `yaml:"name"` // Source name shown on listings, e.g. "Acme Estate Sales"
+	URLs     []string `yaml:"urls"` // Pages embedding the events
+	Coverage `yaml:",inline"`
+	TimeZone string `yaml:"timezone"` // For dates without an offset (default America/Los_Angeles)
+}
+
+// validate checks the fields every JSON-LD source needs
+func (c JSONLDConfig) validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(c.URLs) == 0 {
+		return fmt.Errorf("%s: at least one URL is required", c.Name)
+	}
+	for _, u := range c.URLs {
+		if parsed, err := url.Parse(u); err != nil || parsed.Host == "" {
+			return fmt.Errorf("%s: invalid URL %q", c.Name, u)
+		}
+	}
+	if err := c.Coverage.validate(); err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// JSONLDSource extracts schema.org Event/SaleEvent JSON-LD from configured pages.
+// It lets a company site be added by configuration rather than a scraper of its own.
+type JSONLDSource struct {
+	config  JSONLDConfig
+	fetcher *Fetcher
+	loc     *time.Location // For dates without an offset
+	now     func() time.Time
+}
+
+// NewJSONLDSource creates a source for config that fetches through fetcher
+func NewJSONLDSource(config JSONLDConfig, fetcher *Fetcher) (*JSONLDSource, error) {
+	loc, err := sourceLocation(config.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLDSource{config: config, fetcher: fetcher, loc: loc, now: time.Now}, nil
+}
+
+// Name implements Source
+func (s *JSONLDSource) Name() string {
+	return s.config.Name
+}
+
+// Covers implements Source
+func (s *JSONLDSource) Covers(city, state string) bool {
+	return s.config.Coverage.Covers(city, state)
+}
+
+// FetchListings implements Source. Every configured page is fetched; if any can't be
+// read the fetch fails, since a page's missing events would otherwise be marked missing.
+func (s *JSONLDSource) FetchListings(ctx context.Context, city, state string) (*FetchResult, error) {
+	result := &FetchResult{}
+	seen := map[string]bool{}
+
+	for i, pageURL := range s.config.URLs {
+		status, events, err := s.fetchPage(ctx, pageURL)
+		if i == 0 || err != nil {
+			result.HTTPStatus = status
+		}
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", pageURL, err)
+		}
+
+		result.RowsSeen += len(events)
+		for _, event := range events {
+			scraped, err := s.eventListing(event, pageURL)
+			if err != nil {
+				log.Printf("✗ %s: skipping event: %v", s.Name(), err)
+				// Without a stable key the event's ID can't be known; it may go missing
+				if key := eventKey(event, pageURL); key != "" {
+					result.SkippedIDs = append(result.SkippedIDs, externalID(s.Name(), key))
+				}
+				continue
+			}
+			if scraped == nil || seen[scraped.ExternalID] {
+				continue
+			}
+			seen[scraped.ExternalID] = true
+			result.Listings = append(result.Listings, *scraped)
+		}
+	}
+
+	log.Printf("✓ Scraped %d sales from %s (%d events on %d pages)", len(result.Listings), s.Name(), result.RowsSeen, len(s.config.URLs))
+	return result, nil
+}
+
+// fetchPage returns the page's status and the events in its JSON-LD blocks
+func (s *JSONLDSource) fetchPage(ctx context.Context, pageURL string) (int, []map[string]any, error) {
+	resp, err := s.fetcher.Get(ctx, pageURL)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return resp.StatusCode, nil, fmt.Errorf("got status code %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return resp.StatusCode, extractJSONLDEvents(doc), nil
+}
+
+// extractJSONLDEvents returns every Event object in the page's JSON-LD, whether it's
+// a top-level object, in an array or @graph, or nested (e.g. in an ItemList)
+func extractJSONLDEvents(doc *goquery.Document) []map[string]any {
+	var events []map[string]any
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, script *goquery.Selection) {
+		var data any
+		if err := json.Unmarshal([]byte(script.Text()), &data); err != nil {
+			log.Printf("Warning: skipping unreadable JSON-LD block: %v", err)
+			return
+		}
+		collectEvents(data, &events)
+	})
+	return events
+}
+
+// collectEvents walks a JSON-LD value, appending Event objects (without descending into them)
+func collectEvents(v any, events *[]map[string]any) {
+	switch v := v.(type) {
+	case map[string]any:
+		if isEventType(v["@type"]) {
+			*events = append(*events, v)
+			return
+		}
+		for _, child := range v {
+			collectEvents(child, events)
+		}
+	case []any:
+		for _, child := range v {
+			collectEvents(child, events)
+		}
+	}
+}
+
+// isEventType reports whether @type names Event or a subtype (SaleEvent, ...)
+func isEventType(t any) bool {
+	for _, name := range stringsOf(t) {
+		if name == "Event" || strings.HasSuffix(name, "Event") {
+			return true
+		}
+	}
+	return false
+}
+
+// eventListing maps a schema.org Event to a listing. Cancelled events return nil, nil
+// so they go missing like any other removed sale.
+func (s *JSONLDSource) eventListing(event map[string]any, pageURL string) (*listing.ScrapedListing, error) {
+	if strings.HasSuffix(jsonldText(event["eventStatus"]), "EventCancelled") {
+		return nil, nil
+	}
+
+	title := jsonldText(event["name"])
+	if title == "" {
+		return nil, fmt.Errorf("event without a name")
+	}
+
+	start, startHasTime, err := parseJSONLDDate(jsonldText(event["startDate"]), s.loc)
+	if err != nil {
+		return nil, fmt.Errorf("%q: startDate: %w", title, err)
+	}
+	end, endHasTime := start, startHasTime
+	if endText := jsonldText(event["endDate"]); endText != "" {
+		if end, endHasTime, err = parseJSONLDDate(endText, s.loc); err != nil {
+			return nil, fmt.Errorf("%q: endDate: %w", title, err)
+		}
+	}
+	if !endHasTime {
+		end = time.Date(end.Year(), end.Month(), end.Day(), 23, 59, 59, 0, end.Location())
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("%q: ends before it starts", title)
+	}
+
+	base, _ := url.Parse(pageURL)
+	sourceURL := resolveURL(base, jsonldText(event["url"]))
+	if sourceURL == "" {
+		sourceURL = pageURL
+	}
+
+	scraped := &listing.ScrapedListing{
+		Title:       title,
+		Description: strings.TrimSpace(stripTags(jsonldText(event["description"]))),
+		EventType:   listing.EventTypeFromCategories(jsonldCategories(event)),
+		StartDate:   start,
+		EndDate:     end,
+		SourceName:  s.Name(),
+		SourceURL:   sourceURL,
+		ScrapedAt:   s.now(),
+		CachedAt:    s.now(),
+	}
+	if startHasTime && endHasTime {
+		// Daily hours are the site's local clock, even when published with a fixed offset
+		scraped.Sessions = dailySessions(start.In(s.loc), end.In(s.loc))
+	}
+
+	s.applyLocation(scraped, event["location"])
+	applyDefaults(scraped, s.config.Coverage)
+
+	for _, img := range jsonldImages(event["image"]) {
+		if imgURL := resolveURL(base, img); imgURL != "" {
+			scraped.ImageURLs = append(scraped.ImageURLs, imgURL)
+		}
+	}
+	if len(scraped.ImageURLs) > 0 {
+		scraped.ThumbnailURL = scraped.ImageURLs[0]
+	}
+	scraped.Company = jsonldOrganizer(event["organizer"], base)
+
+	// Sites rarely give events IDs; the event URL is the most stable key, else what it is
+	key := eventKey(event, pageURL)
+	if key == "" {
+		key = fmt.Sprintf("%s|%s|%s", title, start.Format(time.RFC3339), scraped.Address)
+	}
+	scraped.ExternalID = externalID(s.Name(), key)
+
+	return scraped, nil
+}
+
+// eventKey returns an event's @id or, failing that, its own URL ("" if it has neither)
+func eventKey(event map[string]any, pageURL string) string {
+	if id := jsonldText(event["@id"]); id != "" {
+		return id
+	}
+	base, _ := url.Parse(pageURL)
+	if eventURL := resolveURL(base, jsonldText(event["url"])); eventURL != pageURL {
+		return eventURL
+	}
+	return ""
+}
+
+// applyLocation fills the address and coordinates from a Place, PostalAddress or text
+func (s *JSONLDSource) applyLocation(scraped *listing.ScrapedListing, loc any) {
+	if list, ok := loc.([]any); ok && len(list) > 0 {
+		loc = list[0]
+	}
+
+	place, ok := loc.(map[string]any)
+	if !ok {
+		applyAddressText(scraped, jsonldText(loc))
+		return
+	}
+
+	addr := place["address"]
+	if addr == nil && strings.HasSuffix(jsonldText(place["@type"]), "PostalAddress") {
+		addr = place
+	}
+	if postal, ok := addr.(map[string]any); ok {
+		street, _ := address.Parse(jsonldText(postal["streetAddress"])) // Empty if withheld
+		scraped.Address = street.Line()
+		scraped.City = jsonldText(postal["addressLocality"])
+		scraped.State = address.StateCode(jsonldText(postal["addressRegion"]))
+		scraped.ZipCode = jsonldText(postal["postalCode"])
+	} else {
+		applyAddressText(scraped, jsonldText(addr))
+	}
+
+	if geo, ok := place["geo"].(map[string]any); ok {
+		lat, latErr := strconv.ParseFloat(jsonldText(geo["latitude"]), 64)
+		lng, lngErr := strconv.ParseFloat(jsonldText(geo["longitude"]), 64)
+		if latErr == nil && lngErr == nil && (lat != 0 || lng != 0) {
+			scraped.Latitude, scraped.Longitude = lat, lng
+			scraped.GeoPrecision = listing.GeoPrecisionRooftop
+		}
+	}
+}
+
+// jsonldLayouts are the ISO 8601 forms sites use, most specific first
+var jsonldLayouts = []struct {
+	layout  string
+	hasTime bool
+}{
+	{time.RFC3339, true},
+	{"2006-01-02T15:04:05", true},
+	{"2006-01-02T15:04", true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02T15:04Z07:00", true},
+	{"2006-01-02", false},
+}
+
+// parseJSONLDDate parses an ISO 8601 date or date-time. Values without an offset are in loc.
+func parseJSONLDDate(value string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false, fmt.Errorf("missing")
+	}
+	for _, l := range jsonldLayouts {
+		if t, err := time.ParseInLocation(l.layout, value, loc); err == nil {
+			return t, l.hasTime, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("unrecognized date %q", value)
+}
+
+// dailySessions reads a multi-day event as open each day from the start time to the
+// end time (the usual way sites publish e.g. "Fri-Sun 9am-3pm"), in start's location.
+// Events whose end time isn't after their start time get one continuous session.
+func dailySessions(start, end time.Time) []listing.Session {
+	end = end.In(start.Location())
+	startHour, startMinute, _ := start.Clock()
+	endHour, endMinute, _ := end.Clock()
+	if endHour*60+endMinute <= startHour*60+startMinute {
+		return []listing.Session{{Start: start, End: end}}
+	}
+
+	var sessions []listing.Session
+	for day := dayOf(start); !day.After(dayOf(end)); day = day.AddDate(0, 0, 1) {
+		sessions = append(sessions, listing.Session{
+			Start: atClock(day, startHour, startMinute),
+			End:   atClock(day, endHour, endMinute),
+		})
+	}
+	return sessions
+}
+
+// dayOf returns midnight at the start of t's day, in t's location
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// jsonldImages returns image URLs from a URL, ImageObject or a list of either
+func jsonldImages(v any) []string {
+	var images []string
+	switch v := v.(type) {
+	case string:
+		images = append(images, v)
+	case map[string]any:
+		if u := jsonldText(v["url"]); u != "" {
+			images = append(images, u)
+		} else if u := jsonldText(v["contentUrl"]); u != "" {
+			images = append(images, u)
+		}
+	case []any:
+		for _, item := range v {
+			images = append(images, jsonldImages(item)...)
+		}
+	}
+	return images
+}
+
+// jsonldOrganizer maps an Organization/Person organizer to a company contact
+func jsonldOrganizer(v any, base *url.URL) *listing.CompanyContact {
+	if list, ok := v.([]any); ok && len(list) > 0 {
+		v = list[0]
+	}
+	org, ok := v.(map[string]any)
+	if !ok {
+		if name := jsonldText(v); name != "" {
+			return &listing.CompanyContact{Name: name}
+		}
+		return nil
+	}
+
+	company := &listing.CompanyContact{
+		Name:    jsonldText(org["name"]),
+		Phone:   jsonldText(org["telephone"]),
+		Email:   strings.TrimPrefix(jsonldText(org["email"]), "mailto:"),
+		Website: resolveURL(base, jsonldText(org["url"])),
+	}
+	if company.Name == "" {
+		return nil
+	}
+	return company
+}
+
+// jsonldCategories returns what an event says about the kind of sale it is: its
+// additionalType (e.g. "http://www.productontology.org/id/Garage_sale") and keywords
+// (a list or comma-separated text)
+func jsonldCategories(event map[string]any) []string {
+	categories := stringsOf(event["additionalType"])
+	for _, keyword := range stringsOf(event["keywords"]) {
+		categories = append(categories, strings.Split(keyword, ",")...)
+	}
+	return categories
+}
+
+// jsonldText returns a JSON-LD value as text: strings (entity-decoded), numbers,
+// the first of a list, or an object's @value/@id
+func jsonldText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(html.UnescapeString(v))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		if len(v) > 0 {
+			return jsonldText(v[0])
+		}
+	case map[string]any:
+		if value, ok := v["@value"]; ok {
+			return jsonldText(value)
+		}
+		return jsonldText(v["@id"])
+	}
+	return ""
+}
+
+// stringsOf returns a string or list of strings as a slice
+func stringsOf(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+var (
+	// tagRegex matches HTML tags (descriptions are sometimes HTML)
+	tagRegex = regexp.MustCompile(`<[^>]*>`)
+
+	// slugRegex matches runs of characters not allowed in an external ID
+	slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func stripTags(s string) string {
+	return tagRegex.ReplaceAllString(s, " ")
+}
+
+// sourceSlug turns a source name into an external ID prefix ("Acme Estate Sales" → "acme-estate-sales")
+func sourceSlug(name string) string {
+	return strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
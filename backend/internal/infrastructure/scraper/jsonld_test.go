@@ -0,0 +1,226 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jsonldPage = `<html><head>
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Organization", "name": "Acme Estate Sales"}
+</script>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {
+      "@type": "SaleEvent",
+      "name": "Mid-Century Estate Sale",
+      "description": "<p>Teak furniture &amp; vintage kitchenware</p>",
+      "startDate": "2025-06-06T09:00:00-07:00",
+      "endDate": "2025-06-08T15:00:00-07:00",
+      "url": "/sales/mid-century",
+      "keywords": "antiques, estate sale, furniture",
+      "image": [{"@type": "ImageObject", "url": "/img/1.jpg"}, "https://cdn.example.com/2.jpg"],
+      "location": {
+        "@type": "Place",
+        "address": {
+          "@type": "PostalAddress",
+          "streetAddress": "1234 SE Main St",
+          "addressLocality": "Portland",
+          "addressRegion": "Oregon",
+          "postalCode": "97214"
+        },
+        "geo": {"@type": "GeoCoordinates", "latitude": "45.5122", "longitude": -122.6587}
+      },
+      "organizer": {"@type": "Organization", "name": "Acme Estate Sales", "telephone": "503-555-0100", "url": "https://acme.example.com"}
+    },
+    {
+      "@type": "ItemList",
+      "itemListElement": [
+        {"@type": "ListItem", "item": {
+          "@type": "Event",
+          "name": "Garage Sale",
+          "additionalType": "http://www.productontology.org/id/Garage_sale",
+          "startDate": "2025-06-07",
+          "location": "TBA Beaverton, OR 97005"
+        }},
+        {"@type": "ListItem", "item": {
+          "@type": "Event",
+          "name": "Cancelled Sale",
+          "startDate": "2025-06-07",
+          "eventStatus": "https://schema.org/EventCancelled"
+        }},
+        {"@type": "ListItem", "item": {
+          "@type": "Event",
+          "name": "Moving Sale",
+          "startDate": "next Saturday",
+          "url": "/sales/moving"
+        }}
+      ]
+    }
+  ]
+}
+</script>
+<script type="application/ld+json">{ not json</script>
+</head><body></body></html>`
+
+// testJSONLDSource returns a source reading srv's /sales page
+func testJSONLDSource(t *testing.T, srv *httptest.Server, paths ...string) *JSONLDSource {
+	t.Helper()
+	fetcher, _ := testFetcher(srv)
+	config := JSONLDConfig{Name: "Acme Estate Sales", Coverage: Coverage{State: "OR"}}
+	for _, p := range paths {
+		config.URLs = append(config.URLs, srv.URL+p)
+	}
+	src, err := NewJSONLDSource(config, fetcher)
+	require.NoError(t, err)
+	return src
+}
+
+func jsonldServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sales" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(jsonldPage))
+	}))
+}
+
+// TestJSONLDSourceFetchListings tests mapping schema.org events to listings
+func TestJSONLDSourceFetchListings(t *testing.T) {
+	srv := jsonldServer()
+	defer srv.Close()
+
+	src := testJSONLDSource(t, srv, "/sales")
+	result, err := src.FetchListings(context.Background(), "Portland", "OR")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, result.HTTPStatus)
+	assert.Equal(t, 4, result.RowsSeen, "the Organization isn't an event")
+	require.Len(t, result.Listings, 2, "cancelled and unreadable events are skipped")
+	assert.Equal(t, []string{externalID("Acme Estate Sales", srv.URL+"/sales/moving")}, result.SkippedIDs,
+		"an unreadable event isn't marked missing")
+
+	sale := result.Listings[0]
+	assert.Equal(t, "Mid-Century Estate Sale", sale.Title)
+	assert.Equal(t, "Teak furniture & vintage kitchenware", sale.Description)
+	assert.Equal(t, "1234 SE Main St", sale.Address)
+	assert.Equal(t, "Portland", sale.City)
+	assert.Equal(t, "OR", sale.State)
+	assert.Equal(t, "97214", sale.ZipCode)
+	assert.InDelta(t, 45.5122, sale.Latitude, 1e-9)
+	assert.InDelta(t, -122.6587, sale.Longitude, 1e-9)
+	assert.Equal(t, listing.GeoPrecisionRooftop, sale.GeoPrecision)
+	assert.Equal(t, srv.URL+"/sales/mid-century", sale.SourceURL)
+	assert.Equal(t, []string{srv.URL + "/img/1.jpg", "https://cdn.example.com/2.jpg"}, sale.ImageURLs)
+	assert.Equal(t, srv.URL+"/img/1.jpg", sale.ThumbnailURL)
+	assert.Equal(t, "Acme Estate Sales", sale.SourceName)
+	assert.Equal(t, listing.EventEstateSale, sale.EventType)
+	assert.Regexp(t, `^acme-estate-sales-[0-9a-f]{12}$`, sale.ExternalID)
+	require.NotNil(t, sale.Company)
+	assert.Equal(t, "503-555-0100", sale.Company.Phone)
+	assert.Equal(t, "https://acme.example.com", sale.Company.Website)
+
+	// Open 9am-3pm on each of the three days
+	require.Len(t, sale.Sessions, 3)
+	assert.Equal(t, "2025-06-07T09:00:00-07:00", sale.Sessions[1].Start.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-07T15:00:00-07:00", sale.Sessions[1].End.Format(time.RFC3339))
+
+	// Date-only, withheld address given as text
+	garage := result.Listings[1]
+	assert.Equal(t, "TBA", garage.Address)
+	assert.Equal(t, "Beaverton", garage.City)
+	assert.Equal(t, "97005", garage.ZipCode)
+	assert.Equal(t, listing.EventGarageSale, garage.EventType)
+	assert.Equal(t, "2025-06-07T00:00:00-07:00", garage.StartDate.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-07T23:59:59-07:00", garage.EndDate.Format(time.RFC3339))
+	assert.Empty(t, garage.Sessions)
+	assert.Equal(t, srv.URL+"/sales", garage.SourceURL)
+
+	// IDs are stable across scrapes
+	again, err := src.FetchListings(context.Background(), "Portland", "OR")
+	require.NoError(t, err)
+	assert.Equal(t, sale.ExternalID, again.Listings[0].ExternalID)
+	assert.Equal(t, garage.ExternalID, again.Listings[1].ExternalID)
+}
+
+// TestJSONLDSourcePageFailures tests that one bad page fails the source, so the events
+// on it aren't marked missing
+func TestJSONLDSourcePageFailures(t *testing.T) {
+	srv := jsonldServer()
+	defer srv.Close()
+
+	result, err := testJSONLDSource(t, srv, "/sales", "/gone").FetchListings(context.Background(), "Portland", "OR")
+	assert.ErrorContains(t, err, srv.URL+"/gone")
+	assert.Equal(t, http.StatusNotFound, result.HTTPStatus)
+
+	result, err = testJSONLDSource(t, srv, "/gone").FetchListings(context.Background(), "Portland", "OR")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, result.HTTPStatus)
+}
+
+// TestLoadSourcesConfig tests reading and validating a sources file
+func TestLoadSourcesConfig(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "sources.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	config, err := LoadSourcesConfig(write(`
+jsonld:
+  - name: Acme Estate Sales
+    urls: [https://acme.example.com/sales]
+    state: Oregon
+    cities: [Portland, Tigard]
+`))
+	require.NoError(t, err)
+	require.Len(t, config.JSONLD, 1)
+	assert.True(t, config.JSONLD[0].Covers("portland", "OR"))
+	assert.False(t, config.JSONLD[0].Covers("Salem", "OR"))
+	assert.False(t, config.JSONLD[0].Covers("Portland", "ME"))
+
+	// The example file stays loadable
+	example, err := LoadSourcesConfig(filepath.Join("..", "..", "..", "sources.example.yaml"))
+	require.NoError(t, err)
+	assert.Len(t, example.JSONLD, 1)
+	assert.Len(t, example.ICal, 1)
+	assert.Len(t, example.RSS, 1)
+	assert.Len(t, example.Sites, 1)
+
+	for name, content := range map[string]string{
+		"missing name":  "jsonld:\n  - urls: [https://a.example.com]\n    state: OR\n",
+		"no URLs":       "jsonld:\n  - name: A\n    state: OR\n",
+		"unknown state": "jsonld:\n  - name: A\n    urls: [https://a.example.com]\n    state: Atlantis\n",
+		"duplicate": "jsonld:\n  - name: A\n    urls: [https://a.example.com]\n    state: OR\n" +
+			"ical:\n  - name: A\n    url: https://b.example.com/a.ics\n    state: OR\n",
+		"feed URL": "rss:\n  - name: B\n    url: feed.xml\n    state: OR\n",
+	} {
+		_, err := LoadSourcesConfig(write(content))
+		assert.Error(t, err, name)
+	}
+}
+
+// TestDailySessionsDST tests that each day keeps its wall-clock hours across a DST change
+func TestDailySessionsDST(t *testing.T) {
+	// Daylight saving ends at 2am on Sunday, Nov 2, 2025
+	start := time.Date(2025, 10, 31, 9, 0, 0, 0, saleLocation)
+	end := time.Date(2025, 11, 2, 15, 0, 0, 0, saleLocation)
+
+	var got []string
+	for _, session := range dailySessions(start, end) {
+		got = append(got, session.Start.Format("Jan 2 15:04")+"-"+session.End.Format("15:04 MST"))
+	}
+	assert.Equal(t, []string{"Oct 31 09:00-15:00 PDT", "Nov 1 09:00-15:00 PDT", "Nov 2 09:00-15:00 PST"}, got)
+}
@@ -0,0 +1,274 @@
+package scraper
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+)
+
+// RSSSource reads sales from a company's RSS 2.0 or Atom feed. Feed items carry no
+// event dates, so the sale dates and hours are read from the title and description
+// the way they are from a listing page.
+type RSSSource struct {
+	config  FeedConfig
+	fetcher *Fetcher
+	loc     *time.Location
+	now     func() time.Time
+}
+
+// NewRSSSource creates a source for config that fetches through fetcher
+func NewRSSSource(config FeedConfig, fetcher *Fetcher) (*RSSSource, error) {
+	loc, err := sourceLocation(config.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	return &RSSSource{config: config, fetcher: fetcher, loc: loc, now: time.Now}, nil
+}
+
+// Name implements Source
+func (s *RSSSource) Name() string {
+	return s.config.Name
+}
+
+// Covers implements Source
+func (s *RSSSource) Covers(city, state string) bool {
+	return s.config.Coverage.Covers(city, state)
+}
+
+// FetchListings implements Source. Items without a sale date (e.g. blog posts) or
+// whose sale is over are left out.
+func (s *RSSSource) FetchListings(ctx context.Context, city, state string) (*FetchResult, error) {
+	status, body, err := fetchFeed(ctx, s.fetcher, s.config.URL)
+	result := &FetchResult{HTTPStatus: status}
+	if err != nil {
+		return result, err
+	}
+
+	items, err := parseFeed(body)
+	if err != nil {
+		return result, err
+	}
+	result.RowsSeen = len(items)
+
+	now := s.now()
+	seen := map[string]bool{}
+	for _, item := range items {
+		scraped, err := s.itemListing(item, now)
+		if errors.Is(err, ErrNoSaleDate) {
+			continue
+		}
+		if err != nil {
+			log.Printf("✗ %s: skipping item: %v", s.Name(), err)
+			result.SkippedIDs = append(result.SkippedIDs, externalID(s.Name(), item.key()))
+			continue
+		}
+		if scraped == nil || seen[scraped.ExternalID] {
+			continue
+		}
+		seen[scraped.ExternalID] = true
+		result.Listings = append(result.Listings, *scraped)
+	}
+
+	log.Printf("✓ Scraped %d sales from %s (%d items)", len(result.Listings), s.Name(), len(items))
+	return result, nil
+}
+
+// itemListing maps a feed item to a listing. A sale that's over returns nil, nil.
+func (s *RSSSource) itemListing(item feedItem, now time.Time) (*listing.ScrapedListing, error) {
+	title := htmlText(item.Title)
+	if title == "" {
+		return nil, fmt.Errorf("item without a title")
+	}
+	description := htmlText(item.Description)
+
+	// Address lines are left out of the hours ("Address released Thursday" isn't a sale day)
+	hours := withoutAddressLines(description)
+	dates, err := ParseSaleDates(title, hours, now, s.loc)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", title, err)
+	}
+	if dates.End.Before(now) {
+		return nil, nil
+	}
+	sessions, err := listing.ParseSessions(hours, dates.Start, dates.End)
+	if err != nil {
+		log.Printf("Warning: %s: could not parse hours for %q: %v", s.Name(), title, err)
+	}
+
+	base, _ := url.Parse(s.config.URL)
+	sourceURL := resolveURL(base, item.Link)
+	if sourceURL == "" {
+		sourceURL = s.config.URL
+	}
+
+	scraped := &listing.ScrapedListing{
+		ExternalID:  externalID(s.Name(), item.key()),
+		Title:       title,
+		Description: description,
+		EventType:   listing.EventTypeFromCategories(item.Categories),
+		StartDate:   dates.Start,
+		EndDate:     dates.End,
+		Sessions:    sessions,
+		SourceName:  s.Name(),
+		SourceURL:   sourceURL,
+		ScrapedAt:   now,
+		CachedAt:    now,
+	}
+
+	if parsed, ok := findAddress(description); ok {
+		applyAddress(scraped, parsed)
+	}
+	if lat, lng, ok := strings.Cut(strings.TrimSpace(item.GeoPoint), " "); ok {
+		latitude, latErr := strconv.ParseFloat(lat, 64)
+		longitude, lngErr := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+		if latErr == nil && lngErr == nil && (latitude != 0 || longitude != 0) {
+			scraped.Latitude, scraped.Longitude = latitude, longitude
+			scraped.GeoPrecision = listing.GeoPrecisionRooftop
+		}
+	}
+	applyDefaults(scraped, s.config.Coverage)
+
+	for _, img := range item.Images {
+		if imgURL := resolveURL(base, img); imgURL != "" {
+			scraped.ImageURLs = append(scraped.ImageURLs, imgURL)
+		}
+	}
+	if len(scraped.ImageURLs) > 0 {
+		scraped.ThumbnailURL = scraped.ImageURLs[0]
+	}
+
+	return scraped, nil
+}
+
+// withoutAddressLines drops the lines of text that findAddress would take as the address
+func withoutAddressLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if _, ok := findAddress(line); !ok {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// feedItem is an RSS item or Atom entry
+type feedItem struct {
+	ID          string // guid / id
+	Title       string
+	Link        string
+	Description string   // HTML
+	Images      []string // Image enclosures
+	GeoPoint    string   // GeoRSS "lat lng"
+	Categories  []string // e.g. "Moving Sale" (see listing.EventTypeFromCategory)
+}
+
+// key identifies the item across fetches: its GUID, else its link, else its title
+func (i feedItem) key() string {
+	for _, key := range []string{i.ID, i.Link, i.Title} {
+		if key = strings.TrimSpace(key); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// rssDocument reads both RSS 2.0 (<rss><channel><item>) and Atom (<feed><entry>)
+type rssDocument struct {
+	XMLName xml.Name
+	Items   []rssItem   `xml:"channel>item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	GUID        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Enclosures  []struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	Media []struct {
+		URL string `xml:"url,attr"`
+	} `xml:"http://search.yahoo.com/mrss/ content"`
+	GeoPoint   string   `xml:"http://www.georss.org/georss point"`
+	Categories []string `xml:"category"`
+}
+
+type atomEntry struct {
+	ID    string `xml:"id"`
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	Summary    string `xml:"summary"`
+	Content    string `xml:"content"`
+	GeoPoint   string `xml:"http://www.georss.org/georss point"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+}
+
+// parseFeed returns the items of an RSS 2.0 or Atom feed
+func parseFeed(data []byte) ([]feedItem, error) {
+	var doc rssDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	var items []feedItem
+	switch doc.XMLName.Local {
+	case "rss":
+		for _, it := range doc.Items {
+			item := feedItem{ID: it.GUID, Title: it.Title, Link: strings.TrimSpace(it.Link), Description: it.Description, GeoPoint: it.GeoPoint, Categories: it.Categories}
+			if it.Content != "" {
+				item.Description = it.Content // The full post when the description is a summary
+			}
+			for _, enc := range it.Enclosures {
+				if strings.HasPrefix(enc.Type, "image/") || imageExtRegex.MatchString(enc.URL) {
+					item.Images = append(item.Images, enc.URL)
+				}
+			}
+			for _, media := range it.Media {
+				if imageExtRegex.MatchString(media.URL) {
+					item.Images = append(item.Images, media.URL)
+				}
+			}
+			items = append(items, item)
+		}
+	case "feed":
+		for _, entry := range doc.Entries {
+			item := feedItem{ID: entry.ID, Title: entry.Title, Description: entry.Summary, GeoPoint: entry.GeoPoint}
+			if entry.Content != "" {
+				item.Description = entry.Content
+			}
+			for _, category := range entry.Categories {
+				item.Categories = append(item.Categories, category.Term, category.Label)
+			}
+			for _, link := range entry.Links {
+				switch {
+				case link.Rel == "enclosure" && (strings.HasPrefix(link.Type, "image/") || imageExtRegex.MatchString(link.Href)):
+					item.Images = append(item.Images, link.Href)
+				case (link.Rel == "" || link.Rel == "alternate") && item.Link == "":
+					item.Link = link.Href
+				}
+			}
+			items = append(items, item)
+		}
+	default:
+		return nil, fmt.Errorf("not an RSS or Atom feed (root element <%s>)", doc.XMLName.Local)
+	}
+	return items, nil
+}
@@ -0,0 +1,136 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:georss="http://www.georss.org/georss">
+<channel>
+  <title>Acme Estate Sales</title>
+  <item>
+    <title>Laurelhurst Estate Sale - June 6th-7th</title>
+    <link>https://acme.example.com/sales/laurelhurst</link>
+    <guid isPermaLink="false">acme-sale-201</guid>
+    <category>Featured</category>
+    <category>Estate Sales</category>
+    <description><![CDATA[<p>1234 NE Glisan St, Portland, OR 97232</p><p>Fri 9am-4pm, Sat 9am-2pm</p><p>Antiques &amp; art</p>]]></description>
+    <enclosure url="https://cdn.example.com/201.jpg" type="image/jpeg" length="1000"/>
+    <georss:point>45.5265 -122.6262</georss:point>
+  </item>
+  <item>
+    <title>We're hiring!</title>
+    <link>https://acme.example.com/blog/hiring</link>
+    <description>Join our team.</description>
+  </item>
+  <item>
+    <title>Spring Sale - May 2nd</title>
+    <link>https://acme.example.com/sales/spring</link>
+    <description>9am-3pm</description>
+  </item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Acme Estate Sales</title>
+  <entry>
+    <id>tag:acme.example.com,2025:sale-202</id>
+    <title>Sellwood Moving Sale June 14</title>
+    <link rel="alternate" href="/sales/sellwood"/>
+    <link rel="enclosure" type="image/png" href="/img/202.png"/>
+    <category term="downsizing"/>
+    <summary type="html">&lt;p&gt;Address released Thursday&lt;/p&gt;&lt;p&gt;10am-2pm&lt;/p&gt;</summary>
+  </entry>
+</feed>`
+
+func feedServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/feed.rss":
+			w.Write([]byte(rssFeed))
+		case "/feed.atom":
+			w.Write([]byte(atomFeed))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func testRSSSource(t *testing.T, srv *httptest.Server, path string) *RSSSource {
+	t.Helper()
+	fetcher, _ := testFetcher(srv)
+	src, err := NewRSSSource(FeedConfig{Name: "Acme Estate Sales", URL: srv.URL + path, Coverage: Coverage{State: "OR"}}, fetcher)
+	require.NoError(t, err)
+	src.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, saleLocation) }
+	return src
+}
+
+// TestRSSSourceFetchListings tests reading sale dates, hours and addresses from RSS items
+func TestRSSSourceFetchListings(t *testing.T) {
+	srv := feedServer()
+	defer srv.Close()
+
+	result, err := testRSSSource(t, srv, "/feed.rss").FetchListings(context.Background(), "Portland", "OR")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.RowsSeen)
+	require.Len(t, result.Listings, 1, "items without a sale date and past sales are left out")
+
+	sale := result.Listings[0]
+	assert.Equal(t, "Laurelhurst Estate Sale - June 6th-7th", sale.Title)
+	assert.Equal(t, "1234 NE Glisan St, Portland, OR 97232\nFri 9am-4pm, Sat 9am-2pm\nAntiques & art", sale.Description)
+	assert.Equal(t, "1234 NE Glisan St", sale.Address)
+	assert.Equal(t, "Portland", sale.City)
+	assert.Equal(t, "97232", sale.ZipCode)
+	assert.InDelta(t, -122.6262, sale.Longitude, 1e-9)
+	assert.Equal(t, "https://acme.example.com/sales/laurelhurst", sale.SourceURL)
+	assert.Equal(t, "https://cdn.example.com/201.jpg", sale.ThumbnailURL)
+	assert.Equal(t, listing.EventEstateSale, sale.EventType)
+	assert.Equal(t, externalID("Acme Estate Sales", "acme-sale-201"), sale.ExternalID)
+	assert.Equal(t, "2025-06-06T09:00:00-07:00", sale.StartDate.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-07T14:00:00-07:00", sale.EndDate.Format(time.RFC3339))
+	require.Len(t, sale.Sessions, 2)
+	assert.Equal(t, "2025-06-06T16:00:00-07:00", sale.Sessions[0].End.Format(time.RFC3339))
+}
+
+// TestRSSSourceAtom tests reading an Atom feed
+func TestRSSSourceAtom(t *testing.T) {
+	srv := feedServer()
+	defer srv.Close()
+
+	result, err := testRSSSource(t, srv, "/feed.atom").FetchListings(context.Background(), "Portland", "OR")
+	require.NoError(t, err)
+	require.Len(t, result.Listings, 1)
+
+	sale := result.Listings[0]
+	assert.Equal(t, srv.URL+"/sales/sellwood", sale.SourceURL)
+	assert.Equal(t, []string{srv.URL + "/img/202.png"}, sale.ImageURLs)
+	assert.Equal(t, "TBA", sale.Address)
+	assert.Equal(t, "OR", sale.State)
+	assert.Equal(t, listing.EventMovingSale, sale.EventType)
+	assert.Equal(t, "2025-06-14T10:00:00-07:00", sale.StartDate.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-14T14:00:00-07:00", sale.EndDate.Format(time.RFC3339))
+}
+
+// TestRSSSourceNotAFeed tests that a page that isn't a feed fails the fetch
+func TestRSSSourceNotAFeed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`<html><body>Moved to our new site</body></html>`))
+	}))
+	defer srv.Close()
+
+	_, err := testRSSSource(t, srv, "/feed").FetchListings(context.Background(), "Portland", "OR")
+	assert.Error(t, err)
+}
@@ -0,0 +1,315 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Alpine images ship without zoneinfo
+)
+
+// saleLocation is the timezone sale times are published in (Portland metro)
+var saleLocation = mustLoadLocation("America/Los_Angeles")
+
+// ErrNoSaleDate is returned when no calendar date can be found in a sale's text
+var ErrNoSaleDate = errors.New("no sale date found")
+
+var (
+	// "Opens 31st Oct 10:00am", "Opens 2 November 9am"
+	opensRegex = regexp.MustCompile(`(?i)\bopens\s+(\d{1,2})(?:st|nd|rd|th)?\s+([a-z]+)\.?(?:\s+(\d{4}))?(?:\s+(\d{1,2}(?::\d{2})?\s*[ap]\.?m\.?))?`)
+
+	// "31st Oct", "1 November 2025"
+	dayMonthRegex = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+([a-z]{3,9})\.?(?:,?\s+(\d{4}))?\b`)
+
+	// "Oct 31st", "November 1, 2025"
+	monthDayRegex = regexp.MustCompile(`(?i)\b([a-z]{3,9})\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?\b`)
+
+	// "10:00am", "9 am", "4pm"
+	clockRegex = regexp.MustCompile(`(?i)\b(\d{1,2})(?::(\d{2}))?\s*([ap])\.?m\.?`)
+
+	// "9am-4pm", "10:00am - 3:00pm", "9-4pm", "9am to 4pm"
+	timeRangeRegex = regexp.MustCompile(`(?i)\b(\d{1,2}(?::\d{2})?\s*(?:[ap]\.?m\.?)?)\s*(?:-|–|to)\s*(\d{1,2}(?::\d{2})?\s*[ap]\.?m\.?)`)
+
+	// "Fri", "Saturday"
+	weekdayRegex = regexp.MustCompile(`(?i)\b(sun|mon|tue|tues|wed|thu|thur|thurs|fri|sat)(?:day|nesday|rsday|urday|sday)?\b`)
+)
+
+var monthNames = []string{
+	"january", "february", "march", "april", "may", "june",
+	"july", "august", "september", "october", "november", "december",
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// SaleDates holds the parsed start and end of a sale
+type SaleDates struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseSaleDates turns a sale's "Opens ..." line and its hours paragraph into
+// real timestamps in loc. The year is inferred relative to now (a "2nd Jan"
+// seen in late December is next year's).
+//
+// The end is the last date (or weekday) mentioned, at the last closing time.
+// When no closing time is published the sale is assumed to end that day.
+func ParseSaleDates(openText, hoursText string, now time.Time, loc *time.Location) (SaleDates, error) {
+	now = now.In(loc)
+	text := openText
+	if hoursText != openText {
+		text = strings.TrimSpace(openText + " " + hoursText)
+	}
+
+	// 1. Start date (prefer the explicit "Opens" phrase)
+	var startDay time.Time
+	var startClock string
+	if m := opensRegex.FindStringSubmatch(text); m != nil {
+		day, err := dateFromParts(m[1], m[2], m[3], now, loc)
+		if err != nil {
+			return SaleDates{}, fmt.Errorf("invalid open date %q: %w", m[0], err)
+		}
+		startDay = day
+		startClock = m[4]
+	}
+
+	dates := findDates(text, now, loc)
+	if startDay.IsZero() {
+		if len(dates) == 0 {
+			return SaleDates{}, ErrNoSaleDate
+		}
+		startDay = dates[0]
+	}
+
+	// 2. Opening and closing times
+	ranges := timeRangeRegex.FindAllStringSubmatch(hoursText, -1)
+	if startClock == "" && len(ranges) > 0 {
+		startClock = ranges[0][1]
+		if !clockRegex.MatchString(startClock) {
+			// "9-4pm": the meridiem only appears on the closing time
+			startClock = inheritMeridiem(startClock, ranges[0][2])
+		}
+	}
+
+	start := startDay
+	if startClock != "" {
+		hour, minute, err := parseClock(startClock)
+		if err != nil {
+			return SaleDates{}, fmt.Errorf("invalid open time %q: %w", startClock, err)
+		}
+		start = atClock(startDay, hour, minute)
+	}
+
+	// 3. Last day of the sale
+	endDay := startDay
+	for _, d := range dates {
+		if d.After(endDay) {
+			endDay = d
+		}
+	}
+	if endDay.Equal(startDay) {
+		if days := weekdayRegex.FindAllStringSubmatch(hoursText, -1); len(days) > 0 {
+			last := weekdays[strings.ToLower(days[len(days)-1][1])[:3]]
+			offset := (int(last) - int(startDay.Weekday()) + 7) % 7
+			endDay = startDay.AddDate(0, 0, offset)
+		}
+	}
+
+	end := time.Date(endDay.Year(), endDay.Month(), endDay.Day(), 23, 59, 59, 0, loc)
+	if len(ranges) > 0 {
+		hour, minute, err := parseClock(ranges[len(ranges)-1][2])
+		if err != nil {
+			return SaleDates{}, fmt.Errorf("invalid close time %q: %w", ranges[len(ranges)-1][2], err)
+		}
+		end = atClock(endDay, hour, minute)
+	}
+
+	if end.Before(start) {
+		return SaleDates{}, fmt.Errorf("sale ends (%s) before it starts (%s)", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+
+	return SaleDates{Start: start, End: end}, nil
+}
+
+// findDates returns every calendar date mentioned in text, in order of appearance
+func findDates(text string, now time.Time, loc *time.Location) []time.Time {
+	type match struct {
+		pos, end int
+		date     time.Time
+	}
+	var matches []match
+
+	for _, idx := range dayMonthRegex.FindAllStringSubmatchIndex(text, -1) {
+		day, month, year := text[idx[2]:idx[3]], text[idx[4]:idx[5]], ""
+		if idx[6] >= 0 {
+			year = text[idx[6]:idx[7]]
+		}
+		if d, err := dateFromParts(day, month, year, now, loc); err == nil {
+			matches = append(matches, match{idx[0], idx[1], d})
+		}
+	}
+	for _, idx := range monthDayRegex.FindAllStringSubmatchIndex(text, -1) {
+		// "Oct 10:00am" is a month followed by a time, not October 10th
+		if idx[6] < 0 && followedByClock(text[idx[5]:]) {
+			continue
+		}
+		month, day, year := text[idx[2]:idx[3]], text[idx[4]:idx[5]], ""
+		if idx[6] >= 0 {
+			year = text[idx[6]:idx[7]]
+		}
+		if d, err := dateFromParts(day, month, year, now, loc); err == nil {
+			matches = append(matches, match{idx[0], idx[1], d})
+		}
+	}
+
+	// Keep appearance order (the two patterns are scanned separately)
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].pos < matches[j-1].pos; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+
+	dates := make([]time.Time, 0, len(matches))
+	lastEnd := -1
+	for _, m := range matches {
+		// "8th Nov 9am" also reads as "Nov 9" - the first reading wins
+		if m.pos < lastEnd {
+			continue
+		}
+		lastEnd = m.end
+
+		d := m.date
+		// A later date that lands before the previous one crossed a year boundary
+		if len(dates) > 0 && d.Before(dates[len(dates)-1]) {
+			d = d.AddDate(1, 0, 0)
+		}
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+// followedByClock reports whether rest starts with the tail of a time of day
+func followedByClock(rest string) bool {
+	trimmed := strings.ToLower(strings.TrimLeft(rest, " "))
+	return strings.HasPrefix(rest, ":") ||
+		strings.HasPrefix(trimmed, "am") || strings.HasPrefix(trimmed, "pm") ||
+		strings.HasPrefix(trimmed, "a.m") || strings.HasPrefix(trimmed, "p.m")
+}
+
+// dateFromParts builds midnight of day/month in loc, inferring the year when absent
+func dateFromParts(dayStr, monthStr, yearStr string, now time.Time, loc *time.Location) (time.Time, error) {
+	month, ok := parseMonth(monthStr)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown month %q", monthStr)
+	}
+	day, err := strconv.Atoi(dayStr)
+	if err != nil || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("invalid day %q", dayStr)
+	}
+
+	if yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid year %q", yearStr)
+		}
+		return validDate(year, month, day, loc)
+	}
+	return nearestDate(month, day, now, loc)
+}
+
+// nearestDate builds midnight of a yearless day/month in loc. It picks the occurrence
+// closest to now: listings stay up a few weeks after a sale, and upcoming sales are
+// posted months ahead at most.
+func nearestDate(month time.Month, day int, now time.Time, loc *time.Location) (time.Time, error) {
+	candidate, err := validDate(now.Year(), month, day, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch {
+	case candidate.Before(now.AddDate(0, -2, 0)):
+		return validDate(now.Year()+1, month, day, loc)
+	case candidate.After(now.AddDate(0, 10, 0)):
+		return validDate(now.Year()-1, month, day, loc)
+	}
+	return candidate, nil
+}
+
+// validDate rejects dates time.Date would normalize (e.g. 31st Nov → 1st Dec)
+func validDate(year int, month time.Month, day int, loc *time.Location) (time.Time, error) {
+	d := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	if d.Day() != day {
+		return time.Time{}, fmt.Errorf("%s %d has no day %d", month, year, day)
+	}
+	return d, nil
+}
+
+// parseMonth accepts full month names and their common abbreviations ("Oct", "Sept")
+func parseMonth(s string) (time.Month, bool) {
+	s = strings.ToLower(strings.TrimSuffix(s, "."))
+	if len(s) < 3 {
+		return 0, false
+	}
+	for i, name := range monthNames {
+		if strings.HasPrefix(name, s) {
+			return time.Month(i + 1), true
+		}
+	}
+	return 0, false
+}
+
+// parseClock converts "10:00am" / "4 pm" to a 24-hour hour and minute
+func parseClock(s string) (int, int, error) {
+	m := clockRegex.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, fmt.Errorf("no time of day in %q", s)
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if hour < 1 || hour > 12 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	hour %= 12
+	if strings.EqualFold(m[3], "p") {
+		hour += 12
+	}
+	return hour, minute, nil
+}
+
+// atClock returns day at hour:minute wall-clock time (DST-safe, unlike adding a duration to midnight)
+func atClock(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// inheritMeridiem copies am/pm from the closing time onto a bare opening hour,
+// unless that would put the opening after the close ("9-4pm" opens at 9am)
+func inheritMeridiem(open, close string) string {
+	m := clockRegex.FindStringSubmatch(close)
+	if m == nil {
+		return open
+	}
+	withClose := open + m[3] + "m"
+	openHour, _, err1 := parseClock(withClose)
+	closeHour, _, err2 := parseClock(close)
+	if err1 == nil && err2 == nil && openHour > closeHour {
+		return open + "am"
+	}
+	return withClose
+}
+
+// mustLoadLocation loads a timezone or panics (zone data is embedded)
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load timezone %s: %v", name, err))
+	}
+	return loc
+}
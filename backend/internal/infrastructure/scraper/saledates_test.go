@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pdt(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, saleLocation)
+}
+
+// TestParseSaleDates tests parsing of estatesale-finder "Opens" + hours text
+func TestParseSaleDates(t *testing.T) {
+	now := pdt(2025, time.October, 20, 12, 0)
+
+	tests := []struct {
+		name      string
+		openText  string
+		hoursText string
+		now       time.Time
+		start     time.Time
+		end       time.Time
+	}{
+		{
+			name:      "opens with time, hours by weekday",
+			openText:  "Opens 31st Oct 10:00am",
+			hoursText: "Fri 10am-4pm, Sat 10am-4pm, Sun 11am-2pm",
+			now:       now,
+			start:     pdt(2025, time.October, 31, 10, 0),
+			end:       pdt(2025, time.November, 2, 14, 0),
+		},
+		{
+			name:      "full month name keeps its letters",
+			openText:  "Opens 1st August 9:00am",
+			hoursText: "9:00am - 3:00pm",
+			now:       pdt(2025, time.July, 20, 12, 0),
+			start:     pdt(2025, time.August, 1, 9, 0),
+			end:       pdt(2025, time.August, 1, 15, 0),
+		},
+		{
+			name:      "explicit dates in hours paragraph",
+			openText:  "Opens 7th Nov 9am",
+			hoursText: "Friday 7th Nov 9am-4pm, Saturday 8th Nov 9-3pm",
+			now:       now,
+			start:     pdt(2025, time.November, 7, 9, 0),
+			end:       pdt(2025, time.November, 8, 15, 0),
+		},
+		{
+			name:      "year rollover in late December",
+			openText:  "Opens 2nd Jan 10:00am",
+			hoursText: "Fri 10am-3pm Sat 10am-2pm",
+			now:       pdt(2025, time.December, 28, 12, 0),
+			start:     pdt(2026, time.January, 2, 10, 0),
+			end:       pdt(2026, time.January, 3, 14, 0),
+		},
+		{
+			name:      "sale spanning new year",
+			openText:  "Opens 30th Dec 9am",
+			hoursText: "Tue 30th Dec 9am-4pm, Fri 2nd Jan 9am-1pm",
+			now:       pdt(2025, time.December, 15, 12, 0),
+			start:     pdt(2025, time.December, 30, 9, 0),
+			end:       pdt(2026, time.January, 2, 13, 0),
+		},
+		{
+			name:      "recently finished sale stays in the current year",
+			openText:  "Opens 3rd Oct 9am",
+			hoursText: "9am-4pm",
+			now:       now,
+			start:     pdt(2025, time.October, 3, 9, 0),
+			end:       pdt(2025, time.October, 3, 16, 0),
+		},
+		{
+			name:      "no closing time ends the same day",
+			openText:  "Opens 31st Oct 10:00am",
+			hoursText: "",
+			now:       now,
+			start:     pdt(2025, time.October, 31, 10, 0),
+			end:       pdt(2025, time.October, 31, 23, 59).Add(59 * time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates, err := ParseSaleDates(tt.openText, tt.hoursText, tt.now, saleLocation)
+			require.NoError(t, err)
+			assert.Equal(t, tt.start, dates.Start)
+			assert.Equal(t, tt.end, dates.End)
+		})
+	}
+}
+
+// TestParseSaleDatesFailures tests that unreadable text is reported, not invented
+func TestParseSaleDatesFailures(t *testing.T) {
+	now := pdt(2025, time.October, 20, 12, 0)
+
+	_, err := ParseSaleDates("", "", now, saleLocation)
+	assert.ErrorIs(t, err, ErrNoSaleDate)
+
+	_, err = ParseSaleDates("Call for details", "9am-4pm", now, saleLocation)
+	assert.ErrorIs(t, err, ErrNoSaleDate)
+
+	_, err = ParseSaleDates("Opens 31st Nov 9am", "", now, saleLocation)
+	assert.Error(t, err)
+
+	_, err = ParseSaleDates("Opens 12th Smarch 9am", "", now, saleLocation)
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+)
+
+// Location is a city/state the scheduler keeps fresh
+type Location struct {
+	City  string
+	State string
+}
+
+// SchedulerConfig controls which locations are refreshed and how often
+type SchedulerConfig struct {
+	Locations []Location
+	Interval  time.Duration // Time between refreshes of a location
+	Jitter    time.Duration // Up to this much random delay is added to each wait
+}
+
+// SchedulerConfigFromEnv reads SCRAPE_LOCATIONS ("Portland,OR;Seattle,WA"),
+// SCRAPE_INTERVAL (default 4h) and SCRAPE_JITTER (default 10m)
+func SchedulerConfigFromEnv() (SchedulerConfig, error) {
+	config := SchedulerConfig{
+		Interval: 4 * time.Hour, // Inside the 6h freshness window, so requests rarely see stale data
+		Jitter:   10 * time.Minute,
+	}
+
+	locations := os.Getenv("SCRAPE_LOCATIONS")
+	if locations == "" {
+		locations = "Portland,OR"
+	}
+	for _, entry := range strings.Split(locations, ";") {
+		parts := strings.Split(entry, ",")
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return config, fmt.Errorf("invalid SCRAPE_LOCATIONS entry %q (expected City,ST)", entry)
+		}
+		config.Locations = append(config.Locations, Location{
+			City:  strings.TrimSpace(parts[0]),
+			State: strings.ToUpper(strings.TrimSpace(parts[1])),
+		})
+	}
+
+	if interval := os.Getenv("SCRAPE_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil || d <= 0 {
+			return config, fmt.Errorf("invalid SCRAPE_INTERVAL %q", interval)
+		}
+		config.Interval = d
+	}
+	if jitter := os.Getenv("SCRAPE_JITTER"); jitter != "" {
+		d, err := time.ParseDuration(jitter)
+		if err != nil || d < 0 {
+			return config, fmt.Errorf("invalid SCRAPE_JITTER %q", jitter)
+		}
+		config.Jitter = d
+	}
+
+	return config, nil
+}
+
+// Scheduler periodically refreshes configured locations so requests can be
+// served from Redis/PostgreSQL without scraping inline
+type Scheduler struct {
+	scraper *ScraperService
+	config  SchedulerConfig
+	jitter  func() time.Duration
+}
+
+// NewScheduler creates a scheduler for a scraper service
+func NewScheduler(scraper *ScraperService, config SchedulerConfig) *Scheduler {
+	sc := &Scheduler{scraper: scraper, config: config}
+	sc.jitter = func() time.Duration {
+		if sc.config.Jitter <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(sc.config.Jitter)))
+	}
+	return sc
+}
+
+// Run refreshes every location on its own interval until ctx is cancelled.
+// Each location starts after a random jitter so they don't all scrape at once.
+func (sc *Scheduler) Run(ctx context.Context) {
+	log.Printf("⏰ Scheduler started: %d locations every %v (jitter %v)", len(sc.config.Locations), sc.config.Interval, sc.config.Jitter)
+
+	var wg sync.WaitGroup
+	for _, loc := range sc.config.Locations {
+		wg.Add(1)
+		go func(loc Location) {
+			defer wg.Done()
+			sc.runLocation(ctx, loc)
+		}(loc)
+	}
+	wg.Wait()
+
+	log.Printf("⏰ Scheduler stopped")
+}
+
+// RunOnce refreshes every location once, in order. A location a request is already
+// scraping isn't scraped again; RunOnce waits for that scrape instead.
+func (sc *Scheduler) RunOnce(ctx context.Context) error {
+	var failures []string
+	for _, loc := range sc.config.Locations {
+		if _, err := sc.scraper.refreshShared(ctx, loc.City, loc.State, scrape.TriggerScheduler, backgroundRefreshTimeout); err != nil {
+			failures = append(failures, fmt.Sprintf("%s, %s: %v", loc.City, loc.State, err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d locations failed: %s", len(failures), len(sc.config.Locations), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// runLocation is the refresh loop for one location
+func (sc *Scheduler) runLocation(ctx context.Context, loc Location) {
+	wait := sc.jitter()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if _, err := sc.scraper.refreshShared(ctx, loc.City, loc.State, scrape.TriggerScheduler, backgroundRefreshTimeout); err != nil {
+			log.Printf("✗ Scheduled refresh failed for %s, %s: %v", loc.City, loc.State, err)
+		}
+
+		wait = sc.config.Interval + sc.jitter()
+	}
+}
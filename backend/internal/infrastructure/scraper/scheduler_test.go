@@ -0,0 +1,44 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchedulerConfigFromEnv tests reading scheduler settings from the environment
+func TestSchedulerConfigFromEnv(t *testing.T) {
+	t.Setenv("SCRAPE_LOCATIONS", "Portland,or; Vancouver , WA")
+	t.Setenv("SCRAPE_INTERVAL", "2h")
+	t.Setenv("SCRAPE_JITTER", "")
+
+	config, err := SchedulerConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []Location{{City: "Portland", State: "OR"}, {City: "Vancouver", State: "WA"}}, config.Locations)
+	assert.Equal(t, 2*time.Hour, config.Interval)
+	assert.Equal(t, 10*time.Minute, config.Jitter)
+
+	t.Setenv("SCRAPE_LOCATIONS", "Portland")
+	_, err = SchedulerConfigFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("SCRAPE_LOCATIONS", "")
+	t.Setenv("SCRAPE_INTERVAL", "soon")
+	_, err = SchedulerConfigFromEnv()
+	assert.Error(t, err)
+}
+
+// TestSchedulerJitter tests that jitter stays within the configured bound
+func TestSchedulerJitter(t *testing.T) {
+	sc := NewScheduler(nil, SchedulerConfig{Interval: time.Hour, Jitter: time.Minute})
+	for i := 0; i < 100; i++ {
+		j := sc.jitter()
+		assert.GreaterOrEqual(t, j, time.Duration(0))
+		assert.Less(t, j, time.Minute)
+	}
+
+	sc = NewScheduler(nil, SchedulerConfig{Interval: time.Hour})
+	assert.Equal(t, time.Duration(0), sc.jitter())
+}
@@ -1,15 +1,19 @@
 package scraper
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/company"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
 	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/infrastructure/cache"
 )
 
@@ -18,61 +22,192 @@ type ScraperService struct {
 	cache          *cache.RedisClient
 	repo           listing.Repository // Database for persistent storage
 	cacheTTL       time.Duration
-	httpClient     *http.Client
-	scrapeInFlight sync.Map // Prevents duplicate scrapes
-	esFinderScraper *EstateSaleFinderScraper
+
+	// Concurrent requests for a location share one scrape
+	inFlight    scrapeFlight
+	waitTimeout time.Duration // How long a request waits on a scrape before falling back
+
+	// Registered scraper sources (see source.go)
+	sources   []Source
+	sourcesMu sync.RWMutex
+	fetcher   *Fetcher // Shared by the sources so per-host limits hold across them
+
+	// Sources defined in SCRAPE_SOURCES_FILE (see sources_config.go)
+	sourcesFile       string
+	sourcesReload     time.Duration // How often the file is checked for changes (0 disables)
+	configuredSources map[string]bool
+	configuredMu      sync.Mutex
+
+	geocoder listing.Geocoder // Optional, see SetGeocoder
+
+	// Background mode: requests never scrape inline (see SetBackgroundRefresh)
+	backgroundRefresh bool
+	revalidating      sync.Map // Locations with a revalidation in progress
+
+	runs scrape.RunRepository // Optional, see SetRunRepository
+
+	companies company.Repository // Optional, see SetCompanyRepository
+
+	// Listings missing from this many scrapes in a row are tombstoned
+	tombstoneAfter int
+
+	// Runs whose fill rates fall below threshold × baseline are quarantined (see drift.go)
+	driftThreshold    float64 // 0 disables drift detection
+	driftBaselineRuns int
+
+	// Per-source circuit breakers (see breaker.go)
+	breakers        map[string]*circuitBreaker
+	breakersMu      sync.Mutex
+	breakerFailures int
+	breakerCooldown time.Duration
 }
 
-// NewScraperService creates a new scraper service
+// DefaultTombstoneAfter is used when SCRAPE_TOMBSTONE_AFTER is not set
+const DefaultTombstoneAfter = 3
+
+// DefaultScrapeWaitTimeout is used when SCRAPE_WAIT_TIMEOUT is not set
+const DefaultScrapeWaitTimeout = 30 * time.Second
+
+// backgroundRefreshTimeout is how long revalidation and the scheduler wait on a scrape
+const backgroundRefreshTimeout = 15 * time.Minute
+
+// DefaultSourcesReload is used when SCRAPE_SOURCES_RELOAD is not set
+const DefaultSourcesReload = 30 * time.Second
+
+// NewScraperService creates a new scraper service with the default sources registered
 func NewScraperService(redisClient *cache.RedisClient, repo listing.Repository) *ScraperService {
-	return &ScraperService{
-		cache:    redisClient,
-		repo:     repo,
-		cacheTTL: 6 * time.Hour, // Cache for 6 hours
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		esFinderScraper: NewEstateSaleFinderScraper(),
+	s := &ScraperService{
+		cache:          redisClient,
+		repo:           repo,
+		cacheTTL:       6 * time.Hour, // Cache for 6 hours
+		tombstoneAfter: DefaultTombstoneAfter,
+		fetcher:        NewFetcher(),
+		waitTimeout:    DefaultScrapeWaitTimeout,
+
+		breakerFailures: DefaultBreakerFailures,
+		breakerCooldown: DefaultBreakerCooldown,
+
+		sourcesFile:   os.Getenv("SCRAPE_SOURCES_FILE"),
+		sourcesReload: DefaultSourcesReload,
+
+		driftThreshold:    scrape.DefaultDriftThreshold,
+		driftBaselineRuns: scrape.DefaultDriftBaselineRuns,
+	}
+
+	if n, err := strconv.Atoi(os.Getenv("SCRAPE_TOMBSTONE_AFTER")); err == nil && n > 0 {
+		s.tombstoneAfter = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("SCRAPE_WAIT_TIMEOUT")); err == nil && d > 0 {
+		s.waitTimeout = d
 	}
+	if n, err := strconv.Atoi(os.Getenv("SCRAPE_BREAKER_FAILURES")); err == nil && n > 0 {
+		s.breakerFailures = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("SCRAPE_BREAKER_COOLDOWN")); err == nil && d > 0 {
+		s.breakerCooldown = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("SCRAPE_SOURCES_RELOAD")); err == nil && d >= 0 {
+		s.sourcesReload = d
+	}
+	if f, err := strconv.ParseFloat(os.Getenv("SCRAPE_DRIFT_THRESHOLD"), 64); err == nil && f >= 0 && f < 1 {
+		s.driftThreshold = f
+	}
+	if n, err := strconv.Atoi(os.Getenv("SCRAPE_DRIFT_BASELINE_RUNS")); err == nil && n >= scrape.MinBaselineRuns {
+		s.driftBaselineRuns = n
+	}
+
+	s.RegisterSource(NewEstateSaleFinderScraper(s.fetcher))
+
+	// Sources defined by configuration (see sources_config.go and WatchSources)
+	if s.sourcesFile != "" {
+		config, err := LoadSourcesConfig(s.sourcesFile)
+		if err != nil {
+			log.Printf("Warning: configured sources disabled: %v", err)
+		} else {
+			s.RegisterConfiguredSources(config)
+		}
+	}
+
+	return s
+}
+
+// SetGeocoder sets the geocoder used to fill coordinates on scraped sales
+func (s *ScraperService) SetGeocoder(g listing.Geocoder) {
+	s.geocoder = g
+}
+
+// SetBackgroundRefresh switches GetListingsByLocation to serve only from Redis/PostgreSQL.
+// Stale or missing locations are refreshed asynchronously (stale-while-revalidate);
+// the Scheduler keeps configured locations fresh.
+func (s *ScraperService) SetBackgroundRefresh(enabled bool) {
+	s.backgroundRefresh = enabled
 }
 
-// GetListingsByLocation returns sales for a city/state (cached or scraped)
+// WatchSources reloads SCRAPE_SOURCES_FILE when it changes, every SCRAPE_SOURCES_RELOAD,
+// until ctx is done. It returns immediately if there's no file or reloading is off.
+func (s *ScraperService) WatchSources(ctx context.Context) {
+	if s.sourcesFile == "" || s.sourcesReload <= 0 {
+		return
+	}
+	s.WatchSourcesFile(ctx, s.sourcesFile, s.sourcesReload)
+}
+
+// SetRunRepository sets where refresh runs are recorded
+func (s *ScraperService) SetRunRepository(runs scrape.RunRepository) {
+	s.runs = runs
+}
+
+// SetCompanyRepository sets where the companies running scraped sales are stored.
+// Without it scraped sales aren't linked to a company.
+func (s *ScraperService) SetCompanyRepository(companies company.Repository) {
+	s.companies = companies
+}
+
+// GetListingsByLocation returns sales for a city/state (cached or scraped), see GetFeedByLocation
+func (s *ScraperService) GetListingsByLocation(ctx context.Context, city, state string) ([]listing.ScrapedListing, error) {
+	feed, err := s.GetFeedByLocation(ctx, city, state)
+	if err != nil {
+		return nil, err
+	}
+	return feed.Listings, nil
+}
+
+// GetFeedByLocation returns sales for a city/state (cached or scraped)
 // Implements 3-tier strategy: Redis → PostgreSQL → Scrape
-func (s *ScraperService) GetListingsByLocation(city, state string) ([]listing.ScrapedListing, error) {
+// In background mode the last tier is replaced by an asynchronous refresh.
+// If the scrape fails (e.g. the source is down and its circuit is open), the last
+// known sales in PostgreSQL are served however old they are, flagged stale.
+// A scrape started here is cancelled if every request waiting on it is cancelled.
+func (s *ScraperService) GetFeedByLocation(ctx context.Context, city, state string) (*listing.ScrapedFeed, error) {
 	cacheKey := s.getCacheKey(city, state)
 
 	// 1. Try Redis cache first (fastest)
-	if s.cache.IsEnabled() {
+	if s.cacheEnabled() {
 		var cachedSales []listing.ScrapedListing
 		err := s.cache.Get(cacheKey, &cachedSales)
 		if err == nil {
 			log.Printf("✓ Cache HIT (Redis): %s (%d sales)", cacheKey, len(cachedSales))
-			return cachedSales, nil
+			return &listing.ScrapedFeed{Listings: cachedSales}, nil
 		}
 	}
 
 	log.Printf("✗ Cache MISS (Redis): %s", cacheKey)
 
 	// 2. Check PostgreSQL - is data less than 6 hours old?
-	lastScrape, err := s.repo.GetLastScrapedTime(city, state)
+	lastScrape, err := s.repo.GetLastScrapedTime(ctx, city, state)
 	if err != nil {
 		log.Printf("Warning: Failed to check PostgreSQL last scrape time: %v", err)
 	}
 
-	needsRescrape := true
 	if lastScrape != nil && lastScrape.LastScrapedAt != nil {
 		age := time.Since(*lastScrape.LastScrapedAt)
 		log.Printf("→ PostgreSQL data age: %v (threshold: 6h)", age)
 
-		if age < 6*time.Hour {
-			needsRescrape = false
-			log.Printf("✓ PostgreSQL data is fresh (<6h), loading from DB...")
-
+		if age < s.cacheTTL || s.backgroundRefresh {
 			// Load from PostgreSQL
-			dbSales, err := s.repo.GetExternalSalesByLocation(city, state)
+			dbSales, err := s.repo.GetExternalSalesByLocation(ctx, city, state)
 			if err != nil {
 				log.Printf("Warning: Failed to load from PostgreSQL: %v", err)
-				needsRescrape = true // Fallback to scraping
 			} else if len(dbSales) > 0 {
 				// Convert Listing to ScrapedListing
 				scrapedListings := make([]listing.ScrapedListing, len(dbSales))
@@ -80,8 +215,17 @@ func (s *ScraperService) GetListingsByLocation(city, state string) ([]listing.Sc
 					scrapedListings[i] = s.ToScrapedListing()
 				}
 
+				if age >= s.cacheTTL {
+					// Background mode: serve stale data now, refresh behind it
+					log.Printf("→ PostgreSQL data is stale (>6h), serving %d sales while revalidating", len(scrapedListings))
+					s.revalidate(city, state)
+					return &listing.ScrapedFeed{Listings: scrapedListings, Stale: true, LastScrapedAt: lastScrape.LastScrapedAt}, nil
+				}
+
+				log.Printf("✓ PostgreSQL data is fresh (<6h), loaded %d sales from DB", len(scrapedListings))
+
 				// Re-cache in Redis
-				if s.cache.IsEnabled() {
+				if s.cacheEnabled() {
 					if err := s.cache.Set(cacheKey, scrapedListings, s.cacheTTL); err != nil {
 						log.Printf("Warning: Failed to re-cache from PostgreSQL: %v", err)
 					} else {
@@ -89,10 +233,9 @@ func (s *ScraperService) GetListingsByLocation(city, state string) ([]listing.Sc
 					}
 				}
 
-				return scrapedListings, nil
+				return &listing.ScrapedFeed{Listings: scrapedListings, LastScrapedAt: lastScrape.LastScrapedAt}, nil
 			} else {
 				log.Printf("Warning: PostgreSQL returned 0 sales, will re-scrape")
-				needsRescrape = true
 			}
 		} else {
 			log.Printf("→ PostgreSQL data is stale (>6h), re-scraping...")
@@ -101,186 +244,337 @@ func (s *ScraperService) GetListingsByLocation(city, state string) ([]listing.Sc
 		log.Printf("→ No PostgreSQL data found, initial scrape needed")
 	}
 
-	// 3. Needs scraping - check if scrape already in progress (request deduplication)
-	if needsRescrape {
-		ch := make(chan scrapeResult, 1)
-		if existing, loaded := s.scrapeInFlight.LoadOrStore(cacheKey, ch); loaded {
-			log.Printf("⏳ Scrape in progress for %s, waiting...", cacheKey)
-			result := <-existing.(chan scrapeResult)
-			return result.sales, result.err
+	// Background mode never blocks a request on a live scrape
+	if s.backgroundRefresh {
+		s.revalidate(city, state)
+		return &listing.ScrapedFeed{Listings: []listing.ScrapedListing{}}, nil
+	}
+
+	// 3. Needs scraping - join the scrape already in progress, if any (request deduplication)
+	waitTimeout := s.waitTimeout
+	if waitTimeout <= 0 {
+		waitTimeout = DefaultScrapeWaitTimeout
+	}
+	sales, err := s.refreshShared(ctx, city, state, scrape.TriggerRequest, waitTimeout)
+
+	if err != nil {
+		// 4. Scrape failed or is taking too long - fall back to the last known sales
+		if feed := s.lastKnownFeed(ctx, city, state, lastScrape); feed != nil {
+			log.Printf("→ Scrape failed (%v), serving %d last known sales (stale)", err, len(feed.Listings))
+			return feed, nil
 		}
+		return nil, err
+	}
 
-		// 4. We're first - do the scrape
-		log.Printf("🌐 Scraping %s, %s...", city, state)
+	return &listing.ScrapedFeed{Listings: sales, LastScrapedAt: timePtr(time.Now())}, nil
+}
 
-		sales, err := s.scrapeEstateSaleFinder(city, state)
-		result := scrapeResult{sales: sales, err: err}
+// lastKnownFeed loads a location's sales from PostgreSQL regardless of age, flagged stale.
+// It returns nil if there are none.
+func (s *ScraperService) lastKnownFeed(ctx context.Context, city, state string, lastScrape *listing.Listing) *listing.ScrapedFeed {
+	if lastScrape == nil || lastScrape.LastScrapedAt == nil {
+		return nil
+	}
 
-		// Notify all waiting goroutines
-		ch <- result
-		close(ch)
-		s.scrapeInFlight.Delete(cacheKey)
+	dbSales, err := s.repo.GetExternalSalesByLocation(ctx, city, state)
+	if err != nil {
+		log.Printf("Warning: Failed to load last known sales from PostgreSQL: %v", err)
+		return nil
+	}
+	if len(dbSales) == 0 {
+		return nil
+	}
 
-		if err != nil {
-			return nil, err
-		}
+	feed := &listing.ScrapedFeed{Stale: true, LastScrapedAt: lastScrape.LastScrapedAt}
+	for _, l := range dbSales {
+		feed.Listings = append(feed.Listings, l.ToScrapedListing())
+	}
+	return feed
+}
 
-		// 5. Persist to PostgreSQL (converts ScrapedListing → Sale)
-		if s.repo != nil {
-			log.Printf("→ Persisting %d sales to PostgreSQL...", len(sales))
-			successCount := 0
-			failCount := 0
-			for _, scraped := range sales {
-				saleEntity := scraped.ToSale()
-				if err := s.repo.UpsertExternalSale(&saleEntity); err != nil {
-					log.Printf("✗ FAILED to persist sale %s: %v", scraped.ExternalID, err)
-					failCount++
-				} else {
-					successCount++
-				}
-			}
-			log.Printf("✓ Persisted %d/%d sales to PostgreSQL (failed: %d)", successCount, len(sales), failCount)
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// RefreshLocation scrapes every covering source for a city/state, then geocodes,
+// persists and caches the results. Each source's fetch is recorded as a run when a
+// run repository is set. A source whose fill rates collapsed against its recent runs
+// is treated as failed: its listings are quarantined and the stored ones kept. An
+// error is only returned if every source failed.
+func (s *ScraperService) RefreshLocation(ctx context.Context, city, state, trigger string) ([]listing.ScrapedListing, error) {
+	log.Printf("🌐 Scraping %s, %s (%s)...", city, state, trigger)
+
+	fetches := s.fetchSources(ctx, city, state)
+	if len(fetches) == 0 {
+		log.Printf("Note: no registered source covers %s, %s", city, state)
+		return []listing.ScrapedListing{}, nil
+	}
+	// Nobody wants the results any more; don't write a partial scrape
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("scrape of %s, %s cancelled: %w", city, state, err)
+	}
+
+	var results [][]listing.ScrapedListing
+	var failures []string
+	degraded := false
+	for _, f := range fetches {
+		// A skipped source made no request, so there's no run to record
+		if errors.Is(f.err, ErrCircuitOpen) {
+			log.Printf("✗ Source %s skipped for %s, %s: %v", f.source.Name(), city, state, f.err)
+			failures = append(failures, fmt.Sprintf("%s: %v", f.source.Name(), f.err))
+			continue
 		}
 
-		// 6. Store in Redis cache
-		if s.cache.IsEnabled() {
-			if err := s.cache.Set(cacheKey, sales, s.cacheTTL); err != nil {
-				log.Printf("Warning: Failed to cache results: %v", err)
-			} else {
-				log.Printf("✓ Cached %d sales in Redis (TTL: %v)", len(sales), s.cacheTTL)
-			}
+		if _, err := s.saveFetch(ctx, f, city, state, trigger); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", f.source.Name(), err))
+			degraded = degraded || errors.Is(err, ErrDegraded)
+			continue
 		}
+		results = append(results, f.result.Listings)
+	}
 
-		return sales, nil
+	if len(failures) == len(fetches) {
+		return nil, fmt.Errorf("all sources failed: %s", strings.Join(failures, "; "))
 	}
 
-	// Shouldn't reach here
-	return []listing.ScrapedListing{}, nil
-}
+	sales := mergeListings(results...)
 
-// scrapeEstateSaleFinder scrapes estatesale-finder.com for Portland area
-func (s *ScraperService) scrapeEstateSaleFinder(city, state string) ([]listing.ScrapedListing, error) {
-	// For now, only supports Portland area (estatesale-finder.com is regional)
-	if strings.ToUpper(state) == "OR" || strings.ToLower(city) == "portland" {
-		return s.esFinderScraper.ScrapePortlandSales()
+	// Store in Redis cache (not when a source was degraded, so reads go to the stored listings)
+	if s.cacheEnabled() && !degraded {
+		cacheKey := s.getCacheKey(city, state)
+		if err := s.cache.Set(cacheKey, sales, s.cacheTTL); err != nil {
+			log.Printf("Warning: Failed to cache results: %v", err)
+		} else {
+			log.Printf("✓ Cached %d sales in Redis (TTL: %v)", len(sales), s.cacheTTL)
+		}
 	}
 
-	// Fallback: return empty for other locations
-	log.Printf("Note: estatesale-finder.com only covers Portland area. %s, %s not supported yet.", city, state)
-	return []listing.ScrapedListing{}, nil
+	return sales, nil
 }
 
-// scrapeEstateSalesNet scrapes estatesales.net for a city/state (legacy - not used)
-func (s *ScraperService) scrapeEstateSalesNet(city, state string) ([]listing.ScrapedListing, error) {
-	// Normalize inputs
-	city = strings.ReplaceAll(city, " ", "-")
-	state = strings.ToUpper(state)
+// ErrDegraded is returned for a fetch whose fill rates collapsed (see drift.go)
+var ErrDegraded = errors.New("degraded")
+
+// saveFetch records one source's fetch as a run and, unless it failed or looks degraded,
+// geocodes and persists its listings. The error says why nothing was saved.
+func (s *ScraperService) saveFetch(ctx context.Context, f sourceFetch, city, state, trigger string) (*scrape.Run, error) {
+	run := &scrape.Run{Source: f.source.Name(), City: city, State: state, Trigger: trigger, StartedAt: f.startedAt}
+	if f.result != nil {
+		run.RowsSeen = f.result.RowsSeen
+		if f.result.HTTPStatus != 0 {
+			status := f.result.HTTPStatus
+			run.HTTPStatus = &status
+		}
+	}
+
+	if f.err != nil {
+		log.Printf("✗ Source %s failed for %s, %s: %v", f.source.Name(), city, state, f.err)
+		run.Finish(0, f.err)
+		s.recordRun(ctx, run)
+		return run, f.err
+	}
+
+	sales := f.result.Listings
 
-	// Build URL: https://www.estatesales.net/OR/Portland
-	url := fmt.Sprintf("https://www.estatesales.net/%s/%s", state, city)
+	// Keep the stored listings rather than overwrite them with a broken parse
+	if reason := s.checkDrift(ctx, run, f.result); reason != "" {
+		log.Printf("✗ Source %s degraded for %s, %s (%s); quarantining %d sales", f.source.Name(), city, state, reason, len(sales))
+		run.Degraded, run.DegradedReason, run.Quarantined = true, reason, len(sales)
+		run.Finish(len(sales), nil)
+		s.recordRun(ctx, run)
+		s.quarantine(ctx, run, sales)
+		return run, fmt.Errorf("%w: %s", ErrDegraded, reason)
+	}
 
-	log.Printf("→ Scraping: %s", url)
+	// Geocode (sources rarely give coordinates)
+	s.geocodeListings(sales)
 
-	// Rate limiting: Sleep 1 second between requests
-	time.Sleep(1 * time.Second)
+	// Persist to PostgreSQL (converts ScrapedListing → Sale)
+	s.persistListings(ctx, sales, run)
 
-	// Fetch HTML
-	resp, err := s.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	// Flag this source's listings for the location that have disappeared
+	s.markMissing(ctx, f.result, run)
+
+	run.Finish(len(sales), nil)
+	s.recordRun(ctx, run)
+	return run, nil
+}
+
+// persistListings upserts one source's listings, counting the outcomes on the run
+func (s *ScraperService) persistListings(ctx context.Context, sales []listing.ScrapedListing, run *scrape.Run) {
+	if s.repo == nil {
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("got status code %d for %s", resp.StatusCode, url)
+	log.Printf("→ Persisting %d %s sales to PostgreSQL...", len(sales), run.Source)
+	s.linkCompanies(ctx, sales, run)
+	for _, scraped := range sales {
+		saleEntity := scraped.ToSale()
+		saleEntity.ScrapeCity, saleEntity.ScrapeState = run.City, run.State
+		result, err := s.repo.UpsertExternalSale(ctx, &saleEntity)
+		if err != nil {
+			log.Printf("✗ FAILED to persist sale %s: %v", scraped.ExternalID, err)
+			run.Failed++
+			continue
+		}
+		// Sessions and photos are part of the content hash: an unchanged listing has the same ones
+		if result != listing.UpsertUnchanged {
+			if err := s.repo.ReplaceSessions(ctx, saleEntity.ID, scraped.Sessions); err != nil {
+				log.Printf("✗ FAILED to persist sessions for sale %s: %v", scraped.ExternalID, err)
+				run.Failed++
+				continue
+			}
+			// Listings without photos this run (e.g. detail page unavailable) keep their old ones
+			if len(scraped.ImageURLs) > 0 {
+				if err := s.repo.ReplaceExternalImages(ctx, saleEntity.ID, scraped.ImageURLs); err != nil {
+					log.Printf("✗ FAILED to persist images for sale %s: %v", scraped.ExternalID, err)
+					run.Failed++
+					continue
+				}
+			}
+		}
+
+		switch result {
+		case listing.UpsertInserted:
+			run.Inserted++
+		case listing.UpsertUpdated:
+			run.Updated++
+		default:
+			run.Unchanged++
+		}
 	}
+	log.Printf("✓ Persisted %s sales: %d new, %d updated, %d unchanged, %d failed",
+		run.Source, run.Inserted, run.Updated, run.Unchanged, run.Failed)
+}
 
-	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+// linkCompanies upserts the companies running sales and sets their IDs on the sales'
+// company details. A company that can't be saved leaves its sales unlinked (ID 0).
+func (s *ScraperService) linkCompanies(ctx context.Context, sales []listing.ScrapedListing, run *scrape.Run) {
+	if s.companies == nil {
+		return
 	}
 
-	// Extract sales
-	var sales []listing.ScrapedListing
+	// Most companies run several sales at once; save each once per run
+	ids := make(map[string]int)
+	for i := range sales {
+		contact := sales[i].Company
+		if contact == nil || company.NormalizeName(contact.Name) == "" {
+			continue
+		}
+		key := contact.ProfileURL
+		if key == "" {
+			key = company.NormalizeName(contact.Name)
+		}
 
-	// Find listing cards (you'll need to inspect HTML to get correct selectors)
-	// This is a placeholder - actual selectors depend on estatesales.net structure
-	doc.Find(".sale-item, .listing-card, article").Each(func(i int, sel *goquery.Selection) {
-		scraped := s.parseSaleListing(sel, city, state)
-		if scraped != nil {
-			sales = append(sales, *scraped)
+		id, ok := ids[key]
+		if !ok {
+			c := &company.Company{
+				Name:       contact.Name,
+				Website:    contact.Website,
+				Phone:      contact.Phone,
+				Email:      contact.Email,
+				Source:     run.Source,
+				ProfileURL: contact.ProfileURL,
+			}
+			if err := s.companies.UpsertScraped(ctx, c); err != nil {
+				log.Printf("✗ FAILED to save company %q for sale %s: %v", contact.Name, sales[i].ExternalID, err)
+			}
+			id = c.ID // 0 on failure, so the company isn't retried for every sale
+			ids[key] = id
 		}
-	})
 
-	log.Printf("✓ Scraped %d sales from %s", len(sales), url)
-	return sales, nil
+		// Copied: the contact may be shared with a source's cache
+		linked := *contact
+		linked.ID = id
+		sales[i].Company = &linked
+	}
 }
 
-// parseSaleListing extracts data from a single listing HTML element
-func (s *ScraperService) parseSaleListing(sel *goquery.Selection, city, state string) *listing.ScrapedListing {
-	// Extract data (these selectors are placeholders - inspect actual HTML)
-	title := strings.TrimSpace(sel.Find("h2, h3, .title").Text())
-	if title == "" {
-		return nil // Skip if no title
-	}
+// refreshShared runs RefreshLocation through inFlight, so a request, a revalidation and
+// the scheduler refreshing the same location share one scrape. It stops waiting after
+// timeout; the scrape then carries on and still saves its results.
+func (s *ScraperService) refreshShared(ctx context.Context, city, state, trigger string, timeout time.Duration) ([]listing.ScrapedListing, error) {
+	return s.inFlight.do(ctx, s.getCacheKey(city, state), timeout, func(ctx context.Context) ([]listing.ScrapedListing, error) {
+		return s.RefreshLocation(ctx, city, state, trigger)
+	})
+}
 
-	// Get source URL
-	linkHref, exists := sel.Find("a").Attr("href")
-	if !exists {
-		return nil
+// revalidate refreshes a location in the background (at most one refresh per location at a time)
+func (s *ScraperService) revalidate(city, state string) {
+	key := s.getCacheKey(city, state)
+	if _, loaded := s.revalidating.LoadOrStore(key, true); loaded {
+		return
 	}
 
-	// Make absolute URL
-	sourceURL := linkHref
-	if !strings.HasPrefix(linkHref, "http") {
-		sourceURL = "https://www.estatesales.net" + linkHref
-	}
+	go func() {
+		defer s.revalidating.Delete(key)
+		// Not tied to the request that noticed the stale data
+		if _, err := s.refreshShared(context.Background(), city, state, scrape.TriggerRequest, backgroundRefreshTimeout); err != nil {
+			log.Printf("✗ Background refresh failed for %s, %s: %v", city, state, err)
+		}
+	}()
+}
 
-	// Extract external ID from URL (e.g., /OR/Portland/12345 -> estatesales-net-12345)
-	parts := strings.Split(strings.Trim(linkHref, "/"), "/")
-	externalID := fmt.Sprintf("estatesales-net-%s", parts[len(parts)-1])
+// markMissing flags listings the source no longer returns for the run's location.
+// An empty result is skipped: it's far more likely a broken page than every sale being cancelled.
+func (s *ScraperService) markMissing(ctx context.Context, result *FetchResult, run *scrape.Run) {
+	if s.repo == nil {
+		return
+	}
+	if len(result.Listings) == 0 {
+		log.Printf("Warning: %s returned no listings for %s, %s; not marking anything missing", run.Source, run.City, run.State)
+		return
+	}
 
-	// Get thumbnail
-	thumbnailURL, _ := sel.Find("img").Attr("src")
-	if !strings.HasPrefix(thumbnailURL, "http") {
-		thumbnailURL = "https://www.estatesales.net" + thumbnailURL
+	seen := make([]string, 0, len(result.Listings)+len(result.SkippedIDs))
+	for _, l := range result.Listings {
+		seen = append(seen, l.ExternalID)
 	}
+	seen = append(seen, result.SkippedIDs...)
 
-	// Get address (placeholder)
-	address := strings.TrimSpace(sel.Find(".address, .location").Text())
+	missing, err := s.repo.MarkMissing(ctx, run.Source, run.City, run.State, seen, s.tombstoneAfter)
+	if err != nil {
+		log.Printf("✗ FAILED to mark missing %s sales: %v", run.Source, err)
+		return
+	}
 
-	// Get dates (placeholder - parse from text)
-	dateText := strings.TrimSpace(sel.Find(".date, .dates, time").Text())
-	startDate, endDate := s.parseDates(dateText)
+	run.Missing = missing.Missing
+	run.Tombstoned = missing.Tombstoned
+	if missing.Missing > 0 || missing.Tombstoned > 0 {
+		log.Printf("→ %s: %d sales missing, %d tombstoned", run.Source, missing.Missing, missing.Tombstoned)
+	}
+}
 
-	return &listing.ScrapedListing{
-		ExternalID:   externalID,
-		Title:        title,
-		Address:      address,
-		City:         city,
-		State:        state,
-		StartDate:    startDate,
-		EndDate:      endDate,
-		ThumbnailURL: thumbnailURL,
-		SourceName:   "EstateSales.net",
-		SourceURL:    sourceURL,
-		ScrapedAt:    time.Now(),
-		CachedAt:     time.Now(),
+// recordRun stores a finished run (failures are logged, not fatal)
+func (s *ScraperService) recordRun(ctx context.Context, run *scrape.Run) {
+	if s.runs == nil {
+		return
+	}
+	if err := s.runs.RecordRun(ctx, run); err != nil {
+		log.Printf("Warning: Failed to record scrape run: %v", err)
 	}
 }
 
-// parseDates attempts to parse date strings (placeholder implementation)
-func (s *ScraperService) parseDates(dateText string) (time.Time, time.Time) {
-	// This is a simplified version - you'll need to parse actual date formats
-	now := time.Now()
+// geocodeListings fills coordinates for scraped sales that have none
+func (s *ScraperService) geocodeListings(sales []listing.ScrapedListing) {
+	if s.geocoder == nil {
+		return
+	}
 
-	// Look for patterns like "Feb 1-2, 2025"
-	// For now, return placeholder dates
-	startDate := now.AddDate(0, 0, 7)  // 7 days from now
-	endDate := now.AddDate(0, 0, 8)    // 8 days from now
+	failed := 0
+	for i := range sales {
+		if err := listing.GeocodeScraped(s.geocoder, &sales[i]); err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Printf("Warning: could not geocode %d/%d scraped sales", failed, len(sales))
+	}
+}
 
-	return startDate, endDate
+// cacheEnabled reports whether Redis is configured
+func (s *ScraperService) cacheEnabled() bool {
+	return s.cache != nil && s.cache.IsEnabled()
 }
 
 // getCacheKey generates a cache key for city/state
@@ -288,14 +582,16 @@ func (s *ScraperService) getCacheKey(city, state string) string {
 	return fmt.Sprintf("sales:%s:%s", strings.ToLower(city), strings.ToUpper(state))
 }
 
-// scrapeResult is used for request deduplication
-type scrapeResult struct {
-	sales []listing.ScrapedListing
-	err   error
+// DedupStats reports how many location scrapes ran and how many callers shared one
+func (s *ScraperService) DedupStats() scrape.DedupStats {
+	return s.inFlight.stats()
 }
 
 // InvalidateCache clears the cache for a city/state
 func (s *ScraperService) InvalidateCache(city, state string) error {
+	if !s.cacheEnabled() {
+		return nil
+	}
 	key := s.getCacheKey(city, state)
 	return s.cache.Delete(key)
 }
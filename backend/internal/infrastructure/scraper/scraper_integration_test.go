@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -20,6 +21,7 @@ import (
 // ScraperIntegrationTestSuite is the test suite for scraper integration tests
 type ScraperIntegrationTestSuite struct {
 	suite.Suite
+	ctx            context.Context
 	db             *sql.DB
 	repo           *postgres.ListingRepository
 	redisClient    *cache.RedisClient
@@ -28,6 +30,8 @@ type ScraperIntegrationTestSuite struct {
 
 // SetupSuite runs once before all tests
 func (suite *ScraperIntegrationTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
 	// Get database connection from environment
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -95,7 +99,7 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_InitialScrape() {
 	assert.Equal(suite.T(), 0, count, "Database should be empty before test")
 
 	// Make request - should trigger scrape
-	sales, err := suite.scraperService.GetListingsByLocation("Portland", "OR")
+	sales, err := suite.scraperService.GetListingsByLocation(suite.ctx, "Portland", "OR")
 	require.NoError(suite.T(), err, "Initial scrape should succeed")
 	assert.Greater(suite.T(), len(sales), 0, "Should return sales from scrape")
 
@@ -118,7 +122,7 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_RedisCacheHit() {
 	suite.T().Log("=== Test 2: Redis Cache Hit ===")
 
 	// First request to populate cache
-	sales1, err := suite.scraperService.GetListingsByLocation("Portland", "OR")
+	sales1, err := suite.scraperService.GetListingsByLocation(suite.ctx, "Portland", "OR")
 	require.NoError(suite.T(), err, "Initial scrape should succeed")
 	scrapedCount := len(sales1)
 	require.Greater(suite.T(), scrapedCount, 0, "Should scrape at least one listing")
@@ -134,7 +138,7 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_RedisCacheHit() {
 
 	// Second request should hit cache (fast) and return same results
 	start := time.Now()
-	sales2, err := suite.scraperService.GetListingsByLocation("Portland", "OR")
+	sales2, err := suite.scraperService.GetListingsByLocation(suite.ctx, "Portland", "OR")
 	elapsed := time.Since(start)
 
 	require.NoError(suite.T(), err, "Cached request should succeed")
@@ -151,7 +155,7 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_PostgreSQLFallback()
 	suite.T().Log("=== Test 3: PostgreSQL Fallback (Redis expired, DB fresh) ===")
 
 	// First request to populate database
-	sales1, err := suite.scraperService.GetListingsByLocation("Portland", "OR")
+	sales1, err := suite.scraperService.GetListingsByLocation(suite.ctx, "Portland", "OR")
 	require.NoError(suite.T(), err, "Initial scrape should succeed")
 	scrapedCount := len(sales1)
 	require.Greater(suite.T(), scrapedCount, 0, "Should scrape at least one listing")
@@ -173,7 +177,7 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_PostgreSQLFallback()
 	// Second request should load from PostgreSQL (not re-scrape)
 	// NOTE: This will only return listings with city="Portland", not all metro area cities
 	start := time.Now()
-	sales2, err := suite.scraperService.GetListingsByLocation("Portland", "OR")
+	sales2, err := suite.scraperService.GetListingsByLocation(suite.ctx, "Portland", "OR")
 	elapsed := time.Since(start)
 
 	require.NoError(suite.T(), err, "PostgreSQL fallback should succeed")
@@ -219,7 +223,7 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_SixHourRefresh() {
 	require.NoError(suite.T(), err)
 
 	// Request should trigger re-scrape (data is stale)
-	sales, err := suite.scraperService.GetListingsByLocation("Portland", "OR")
+	sales, err := suite.scraperService.GetListingsByLocation(suite.ctx, "Portland", "OR")
 	require.NoError(suite.T(), err, "Re-scrape should succeed")
 	assert.Greater(suite.T(), len(sales), 0, "Should return fresh sales")
 
@@ -258,8 +262,9 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_ExternalSaleUpsert()
 	}
 
 	// Insert
-	err := suite.repo.UpsertExternalSale(&sale1)
+	result, err := suite.repo.UpsertExternalSale(suite.ctx, &sale1)
 	require.NoError(suite.T(), err, "First upsert (insert) should succeed")
+	assert.Equal(suite.T(), listing.UpsertInserted, result)
 	assert.Greater(suite.T(), sale1.ID, 0, "Should assign ID")
 	insertedID := sale1.ID
 
@@ -285,18 +290,36 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_ExternalSaleUpsert()
 	}
 
 	// Upsert (should update, not insert)
-	err = suite.repo.UpsertExternalSale(&sale2)
+	result, err = suite.repo.UpsertExternalSale(suite.ctx, &sale2)
 	require.NoError(suite.T(), err, "Second upsert (update) should succeed")
+	assert.Equal(suite.T(), listing.UpsertUpdated, result)
 	assert.Equal(suite.T(), insertedID, sale2.ID, "Should reuse same ID (update, not insert)")
 
 	// Verify update worked
-	retrieved, err := suite.repo.GetByID(insertedID)
+	retrieved, err := suite.repo.GetByID(suite.ctx, insertedID)
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), "Updated Title", retrieved.Title)
 	assert.Equal(suite.T(), "456 New St", retrieved.AddressLine1)
 
 	suite.T().Logf("✓ Updated sale (same external_id)")
 
+	// Re-scraping identical content only bumps last_scraped_at
+	result, err = suite.repo.UpsertExternalSale(suite.ctx, &sale2)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), listing.UpsertUnchanged, result)
+	assert.Equal(suite.T(), insertedID, sale2.ID)
+
+	// Only the content-changing upsert left a revision
+	history, err := suite.repo.GetHistory(suite.ctx, insertedID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), history.Revisions, 1)
+	fields := []string{}
+	for _, c := range history.Revisions[0].Changes {
+		fields = append(fields, c.Field)
+	}
+	assert.Contains(suite.T(), fields, "title")
+	assert.Contains(suite.T(), fields, "address_line1")
+
 	// Verify count (should still be 1)
 	count := suite.countExternalSales()
 	assert.Equal(suite.T(), 1, count, "Should have exactly 1 sale (upserted, not duplicated)")
@@ -313,28 +336,71 @@ func (suite *ScraperIntegrationTestSuite) TestHybridStorage_GetExternalSalesByLo
 	portland2 := createTestExternalSale("portland-2", "Portland", "OR", now)
 	seattle1 := createTestExternalSale("seattle-1", "Seattle", "WA", now)
 
-	require.NoError(suite.T(), suite.repo.UpsertExternalSale(&portland1))
-	require.NoError(suite.T(), suite.repo.UpsertExternalSale(&portland2))
-	require.NoError(suite.T(), suite.repo.UpsertExternalSale(&seattle1))
+	for _, sale := range []*listing.Listing{&portland1, &portland2, &seattle1} {
+		_, err := suite.repo.UpsertExternalSale(suite.ctx, sale)
+		require.NoError(suite.T(), err)
+	}
 
 	// Query Portland sales
-	portlandSales, err := suite.repo.GetExternalSalesByLocation("Portland", "OR")
+	portlandSales, err := suite.repo.GetExternalSalesByLocation(suite.ctx, "Portland", "OR")
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 2, len(portlandSales), "Should return 2 Portland sales")
 
 	// Query Seattle sales
-	seattleSales, err := suite.repo.GetExternalSalesByLocation("Seattle", "WA")
+	seattleSales, err := suite.repo.GetExternalSalesByLocation(suite.ctx, "Seattle", "WA")
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 1, len(seattleSales), "Should return 1 Seattle sale")
 
 	// Query non-existent location
-	nySales, err := suite.repo.GetExternalSalesByLocation("New York", "NY")
+	nySales, err := suite.repo.GetExternalSalesByLocation(suite.ctx, "New York", "NY")
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 0, len(nySales), "Should return 0 sales for non-existent location")
 
 	suite.T().Logf("✓ Location filtering works correctly")
 }
 
+// TestHybridStorage_MarkMissingTombstones tests expiring sales that disappear from their source
+func (suite *ScraperIntegrationTestSuite) TestHybridStorage_MarkMissingTombstones() {
+	now := time.Now()
+	kept := createTestExternalSale("kept-1", "Portland", "OR", now)
+	cancelled := createTestExternalSale("cancelled-1", "Portland", "OR", now)
+	for _, sale := range []*listing.Listing{&kept, &cancelled} {
+		_, err := suite.repo.UpsertExternalSale(suite.ctx, sale)
+		require.NoError(suite.T(), err)
+	}
+
+	// First two misses: still listed, flagged missing
+	for i := 0; i < 2; i++ {
+		result, err := suite.repo.MarkMissing(suite.ctx, "TestSource", "portland", "or", []string{"kept-1"}, 3)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), listing.MissingResult{Missing: 1}, result)
+	}
+	sales, err := suite.repo.GetExternalSalesByLocation(suite.ctx, "Portland", "OR")
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), sales, 2)
+
+	// Third miss: tombstoned and hidden
+	result, err := suite.repo.MarkMissing(suite.ctx, "TestSource", "Portland", "OR", []string{"kept-1"}, 3)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), listing.MissingResult{Tombstoned: 1}, result)
+
+	sales, err = suite.repo.GetExternalSalesByLocation(suite.ctx, "Portland", "OR")
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), sales, 1)
+	assert.Equal(suite.T(), "kept-1", *sales[0].ExternalID)
+
+	detail, err := suite.repo.GetByID(suite.ctx, cancelled.ID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), listing.ScrapeStatusTombstoned, detail.ScrapeStatus)
+
+	// Seen again: back to active
+	_, err = suite.repo.UpsertExternalSale(suite.ctx, &cancelled)
+	require.NoError(suite.T(), err)
+	sales, err = suite.repo.GetExternalSalesByLocation(suite.ctx, "Portland", "OR")
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), sales, 2)
+}
+
 // Helper functions
 
 func (suite *ScraperIntegrationTestSuite) countExternalSales() int {
@@ -378,7 +444,7 @@ func (suite *ScraperIntegrationTestSuite) insertOldExternalSale(scrapedAt time.T
 		UpdatedAt:      scrapedAt,
 	}
 
-	err := suite.repo.UpsertExternalSale(&sale)
+	_, err := suite.repo.UpsertExternalSale(suite.ctx, &sale)
 	if err != nil {
 		suite.T().Fatalf("Failed to insert old sale: %v", err)
 	}
@@ -395,6 +461,8 @@ func createTestExternalSale(externalID, city, state string, scrapedAt time.Time)
 		AddressLine1:   "123 Test St",
 		City:           city,
 		State:          state,
+		ScrapeCity:     city,
+		ScrapeState:    state,
 		ZipCode:        "97201",
 		StartDate:      scrapedAt,
 		EndDate:        scrapedAt.Add(24 * time.Hour),
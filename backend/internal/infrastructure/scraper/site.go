@@ -0,0 +1,348 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteConfig defines an HTML list-page scraper by its selectors, so a site redesign
+// can be fixed by editing the sources file instead of shipping Go code
+type SiteConfig struct {
+	Name     string `yaml:"name"`     // Source name shown on listings
+	ListURL  string `yaml:"list_url"` // Page listing the sales; {city} and {state} are filled in
+	Coverage `yaml:",inline"`
+	TimeZone string     `yaml:"timezone"` // For the sale dates (default America/Los_Angeles)
+	Row      string     `yaml:"row"`      // Selector matching each sale on the list page
+	Fields   SiteFields `yaml:"fields"`
+}
+
+// SiteFields says where each listing field is found within a row. Title and either
+// Dates or Start are required; the rest are optional.
+type SiteFields struct {
+	ID          *Extractor `yaml:"id"` // Stable ID on the site (default: the URL)
+	Title       *Extractor `yaml:"title"`
+	URL         *Extractor `yaml:"url"`     // Link to the sale, resolved against the list URL
+	Address     *Extractor `yaml:"address"` // Free text, e.g. "1234 SE Main St, Portland, OR 97214"
+	City        *Extractor `yaml:"city"`
+	State       *Extractor `yaml:"state"`
+	ZipCode     *Extractor `yaml:"zip_code"`
+	Dates       *Extractor `yaml:"dates"` // Free text, e.g. "Opens 6th Jun 9:00am" (read like Hours)
+	Start       *Extractor `yaml:"start"` // With a layout, instead of Dates
+	End         *Extractor `yaml:"end"`
+	Hours       *Extractor `yaml:"hours"` // e.g. "Fri 9am-4pm, Sat 9am-2pm"
+	Description *Extractor `yaml:"description"`
+	Image       *Extractor `yaml:"image"`      // Thumbnail URL
+	EventType   *Extractor `yaml:"event_type"` // The site's sale category, e.g. "Moving Sale"
+}
+
+// Extractor reads one value from a row: the text (or Attr) of the first element
+// matching Selector whose value matches Regex. A plain string in the file is a selector.
+type Extractor struct {
+	Selector string `yaml:"selector"` // Relative to the row ("" is the row itself)
+	Attr     string `yaml:"attr"`     // Attribute to read instead of the text, e.g. "href"
+	Regex    string `yaml:"regex"`    // Keeps the first capture group (or the whole match)
+	Layout   string `yaml:"layout"`   // Go time layout for start/end, e.g. "Jan 2, 2006 3:04pm"
+
+	re *regexp.Regexp
+}
+
+// UnmarshalYAML accepts either a selector string or a full extractor
+func (e *Extractor) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Selector = node.Value
+		return nil
+	}
+	type plain Extractor
+	return node.Decode((*plain)(e))
+}
+
+// compile checks the extractor's regex
+func (e *Extractor) compile() error {
+	if e == nil || e.Regex == "" {
+		return nil
+	}
+	re, err := regexp.Compile(e.Regex)
+	if err != nil {
+		return fmt.Errorf("invalid regex %q: %w", e.Regex, err)
+	}
+	e.re = re
+	return nil
+}
+
+// extract returns the extractor's value in row ("" if nothing matches)
+func (e *Extractor) extract(row *goquery.Selection) string {
+	if e == nil {
+		return ""
+	}
+
+	matches := row
+	if e.Selector != "" {
+		matches = row.Find(e.Selector)
+	}
+
+	value := ""
+	matches.EachWithBreak(func(i int, sel *goquery.Selection) bool {
+		var text string
+		if e.Attr != "" {
+			text = sel.AttrOr(e.Attr, "")
+		} else {
+			text = strings.Join(strings.Fields(sel.Text()), " ")
+		}
+		if e.re != nil {
+			m := e.re.FindStringSubmatch(text)
+			if m == nil {
+				return true
+			}
+			text = m[0]
+			if len(m) > 1 {
+				text = m[1]
+			}
+		}
+		value = strings.TrimSpace(text)
+		return value == ""
+	})
+	return value
+}
+
+// validate checks the definition and compiles its regexes
+func (c *SiteConfig) validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if parsed, err := url.Parse(strings.NewReplacer("{city}", "city", "{state}", "state").Replace(c.ListURL)); err != nil || parsed.Host == "" {
+		return fmt.Errorf("%s: invalid list_url %q", c.Name, c.ListURL)
+	}
+	if err := c.Coverage.validate(); err != nil {
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	if strings.TrimSpace(c.Row) == "" {
+		return fmt.Errorf("%s: row selector is required", c.Name)
+	}
+
+	f := &c.Fields
+	if f.Title == nil {
+		return fmt.Errorf("%s: fields.title is required", c.Name)
+	}
+	switch {
+	case f.Dates == nil && f.Start == nil:
+		return fmt.Errorf("%s: fields.dates or fields.start is required", c.Name)
+	case f.Start != nil && f.Start.Layout == "":
+		return fmt.Errorf("%s: fields.start needs a layout (or use fields.dates for free text)", c.Name)
+	}
+	if f.Start != nil && f.End != nil && f.End.Layout == "" {
+		f.End.Layout = f.Start.Layout
+	}
+	for name, e := range map[string]*Extractor{
+		"id": f.ID, "title": f.Title, "url": f.URL, "address": f.Address, "city": f.City,
+		"state": f.State, "zip_code": f.ZipCode, "dates": f.Dates, "start": f.Start, "end": f.End,
+		"hours": f.Hours, "description": f.Description, "image": f.Image, "event_type": f.EventType,
+	} {
+		if err := e.compile(); err != nil {
+			return fmt.Errorf("%s: fields.%s: %w", c.Name, name, err)
+		}
+	}
+	return nil
+}
+
+// SiteSource scrapes a list page as described by a SiteConfig
+type SiteSource struct {
+	config  SiteConfig
+	fetcher *Fetcher
+	loc     *time.Location
+	now     func() time.Time
+}
+
+// NewSiteSource creates a source for config that fetches through fetcher
+func NewSiteSource(config SiteConfig, fetcher *Fetcher) (*SiteSource, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	loc, err := sourceLocation(config.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	return &SiteSource{config: config, fetcher: fetcher, loc: loc, now: time.Now}, nil
+}
+
+// Name implements Source
+func (s *SiteSource) Name() string {
+	return s.config.Name
+}
+
+// Covers implements Source
+func (s *SiteSource) Covers(city, state string) bool {
+	return s.config.Coverage.Covers(city, state)
+}
+
+// FetchListings implements Source
+func (s *SiteSource) FetchListings(ctx context.Context, city, state string) (*FetchResult, error) {
+	listURL := strings.NewReplacer("{city}", url.PathEscape(city), "{state}", url.PathEscape(state)).Replace(s.config.ListURL)
+
+	status, body, err := fetchFeed(ctx, s.fetcher, listURL)
+	result := &FetchResult{HTTPStatus: status}
+	if err != nil {
+		return result, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return result, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	base, _ := url.Parse(listURL)
+
+	now := s.now()
+	seen := map[string]bool{}
+	rows := doc.Find(s.config.Row)
+	result.RowsSeen = rows.Length()
+	rows.Each(func(i int, row *goquery.Selection) {
+		scraped, err := s.rowListing(row, base, now)
+		if err != nil {
+			log.Printf("✗ %s: skipping row: %v", s.Name(), err)
+			if key := s.rowKey(row, base); key != "" {
+				result.SkippedIDs = append(result.SkippedIDs, externalID(s.Name(), key))
+			}
+			return
+		}
+		if scraped == nil || seen[scraped.ExternalID] {
+			return
+		}
+		seen[scraped.ExternalID] = true
+		result.Listings = append(result.Listings, *scraped)
+	})
+
+	log.Printf("✓ Scraped %d sales from %s (%d rows, %d skipped)", len(result.Listings), s.Name(), result.RowsSeen, len(result.SkippedIDs))
+	return result, nil
+}
+
+// rowKey identifies a row across fetches: its ID field, else its URL ("" if neither)
+func (s *SiteSource) rowKey(row *goquery.Selection, base *url.URL) string {
+	if id := s.config.Fields.ID.extract(row); id != "" {
+		return id
+	}
+	return resolveURL(base, s.config.Fields.URL.extract(row))
+}
+
+// rowListing maps a row to a listing. Rows without a title (e.g. section headers)
+// and sales that are over return nil, nil.
+func (s *SiteSource) rowListing(row *goquery.Selection, base *url.URL, now time.Time) (*listing.ScrapedListing, error) {
+	f := s.config.Fields
+
+	title := f.Title.extract(row)
+	if title == "" {
+		return nil, nil
+	}
+
+	hours := f.Hours.extract(row)
+	start, end, err := s.rowDates(row, hours, now)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", title, err)
+	}
+	if end.Before(now) {
+		return nil, nil
+	}
+
+	sourceURL := resolveURL(base, f.URL.extract(row))
+	if sourceURL == "" {
+		sourceURL = base.String()
+	}
+	key := s.rowKey(row, base)
+	if key == "" {
+		key = fmt.Sprintf("%s|%s", title, start.Format(time.RFC3339))
+	}
+
+	scraped := &listing.ScrapedListing{
+		ExternalID:   externalID(s.Name(), key),
+		Title:        title,
+		Description:  f.Description.extract(row),
+		EventType:    listing.EventTypeFromCategory(f.EventType.extract(row)),
+		StartDate:    start,
+		EndDate:      end,
+		EventHours:   hours,
+		ThumbnailURL: resolveURL(base, f.Image.extract(row)),
+		SourceName:   s.Name(),
+		SourceURL:    sourceURL,
+		ScrapedAt:    now,
+		CachedAt:     now,
+	}
+	if hours != "" {
+		// Left empty rather than guessed when unreadable
+		if sessions, err := listing.ParseSessions(hours, start, end); err == nil {
+			scraped.Sessions = sessions
+		}
+	}
+	if scraped.ThumbnailURL != "" {
+		scraped.ImageURLs = []string{scraped.ThumbnailURL}
+	}
+
+	applyAddressText(scraped, f.Address.extract(row))
+	if city := f.City.extract(row); city != "" {
+		scraped.City = city
+	}
+	if state := f.State.extract(row); state != "" {
+		scraped.State = state
+	}
+	if zip := f.ZipCode.extract(row); zip != "" {
+		scraped.ZipCode = zip
+	}
+	applyDefaults(scraped, s.config.Coverage)
+
+	return scraped, nil
+}
+
+// rowDates reads a row's start and end, from free text (dates + hours) or laid-out fields
+func (s *SiteSource) rowDates(row *goquery.Selection, hours string, now time.Time) (time.Time, time.Time, error) {
+	f := s.config.Fields
+	if f.Start == nil {
+		dates, err := ParseSaleDates(f.Dates.extract(row), hours, now, s.loc)
+		return dates.Start, dates.End, err
+	}
+
+	start, err := s.layoutTime(f.Start, row, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start: %w", err)
+	}
+	end := time.Date(start.Year(), start.Month(), start.Day(), 23, 59, 59, 0, s.loc)
+	if f.End != nil {
+		if end, err = s.layoutTime(f.End, row, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end: %w", err)
+		}
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("ends (%s) before it starts (%s)", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
+
+// layoutTime parses an extracted value with the extractor's layout. Layouts without
+// a year get the nearest one, as free-text dates do (a January date seen in December is next year's).
+func (s *SiteSource) layoutTime(e *Extractor, row *goquery.Selection, now time.Time) (time.Time, error) {
+	text := e.extract(row)
+	if text == "" {
+		if e.Selector == "" {
+			return time.Time{}, fmt.Errorf("row has no text for layout %q", e.Layout)
+		}
+		return time.Time{}, fmt.Errorf("selector %q matched no text", e.Selector)
+	}
+	t, err := time.ParseInLocation(e.Layout, text, s.loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q doesn't match layout %q", text, e.Layout)
+	}
+	if t.Year() == 0 {
+		day, err := nearestDate(t.Month(), t.Day(), now.In(s.loc), s.loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+		t = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, s.loc)
+	}
+	return t, nil
+}
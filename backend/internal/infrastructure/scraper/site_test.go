@@ -0,0 +1,220 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const siteListPage = `<html><body>
+<h2>This Week's Sales</h2>
+<div class="salerow" id="sale301">
+  <h5><a href="/sale.php?id=301">Laurelhurst Estate Sale</a></h5>
+  <img src="/thumbs/301.jpg">
+  <span class="saletype">Moving/Downsizing Sale</span>
+  <div class="columns">
+    <p>1234 NE Glisan St, Portland, OR 97232</p>
+    <p>Opens 6th Jun 9:00am</p>
+    <p>Fri 9am-4pm, Sat 9am-2pm</p>
+  </div>
+  <a class="view" href="/sale.php?id=301">View</a>
+</div>
+<div class="salerow" id="sale302">
+  <h5><a href="/sale.php?id=302">Mystery Sale</a></h5>
+  <div class="columns"><p>Dates coming soon</p></div>
+  <a class="view" href="/sale.php?id=302">View</a>
+</div>
+<div class="salerow"><h5>Upcoming Sales</h5></div>
+</body></html>`
+
+// siteDefinition is the definition an ops person would write for siteListPage
+const siteDefinition = `
+name: Example List Site
+list_url: %s/sales?city={city}&state={state}
+state: OR
+row: .salerow
+fields:
+  id: { attr: id, regex: 'sale(\d+)' }
+  title: h5 a
+  url: { selector: a.view, attr: href }
+  address: { selector: .columns p, regex: '.*\b\d{5}$' }
+  dates: { selector: .columns p, regex: 'Opens.*' }
+  hours: { selector: .columns p, regex: '^(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun).*[ap]m' }
+  image: { selector: img, attr: src }
+  event_type: .saletype
+`
+
+// TestSiteSourceFetchListings tests scraping a list page from a selector definition
+func TestSiteSourceFetchListings(t *testing.T) {
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		requested = r.URL.String()
+		w.Write([]byte(siteListPage))
+	}))
+	defer srv.Close()
+
+	var config SiteConfig
+	require.NoError(t, yaml.Unmarshal([]byte(fmt.Sprintf(siteDefinition, srv.URL)), &config))
+	fetcher, _ := testFetcher(srv)
+	src, err := NewSiteSource(config, fetcher)
+	require.NoError(t, err)
+	src.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, saleLocation) }
+
+	result, err := src.FetchListings(context.Background(), "Lake Oswego", "OR")
+	require.NoError(t, err)
+	assert.Equal(t, "/sales?city=Lake%20Oswego&state=OR", requested)
+	assert.Equal(t, 3, result.RowsSeen)
+	assert.Equal(t, []string{externalID("Example List Site", "302")}, result.SkippedIDs, "unreadable dates")
+	require.Len(t, result.Listings, 1, "rows without a title are headers")
+
+	sale := result.Listings[0]
+	assert.Equal(t, externalID("Example List Site", "301"), sale.ExternalID)
+	assert.Equal(t, "Laurelhurst Estate Sale", sale.Title)
+	assert.Equal(t, listing.EventMovingSale, sale.EventType, "the site's category, not the title")
+	assert.Equal(t, srv.URL+"/sale.php?id=301", sale.SourceURL)
+	assert.Equal(t, srv.URL+"/thumbs/301.jpg", sale.ThumbnailURL)
+	assert.Equal(t, "1234 NE Glisan St", sale.Address)
+	assert.Equal(t, "Portland", sale.City)
+	assert.Equal(t, "97232", sale.ZipCode)
+	assert.Equal(t, "Fri 9am-4pm, Sat 9am-2pm", sale.EventHours)
+	assert.Equal(t, "2025-06-06T09:00:00-07:00", sale.StartDate.Format(time.RFC3339))
+	assert.Equal(t, "2025-06-07T14:00:00-07:00", sale.EndDate.Format(time.RFC3339))
+	assert.Len(t, sale.Sessions, 2)
+}
+
+// TestSiteSourceLayoutDates tests start/end fields read with a time layout
+func TestSiteSourceLayoutDates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`<ul>
+<li class="sale"><b>Garage Sale</b><time class="from">Jan 3 9:00am</time><time class="to">Jan 4 3:00pm</time></li>
+</ul>`))
+	}))
+	defer srv.Close()
+
+	config := SiteConfig{
+		Name: "Layout Site", ListURL: srv.URL + "/sales", Coverage: Coverage{State: "OR"}, Row: "li.sale",
+		Fields: SiteFields{
+			Title: &Extractor{Selector: "b"},
+			Start: &Extractor{Selector: "time.from", Layout: "Jan 2 3:04pm"},
+			End:   &Extractor{Selector: "time.to"},
+		},
+	}
+	fetcher, _ := testFetcher(srv)
+	src, err := NewSiteSource(config, fetcher)
+	require.NoError(t, err)
+	src.now = func() time.Time { return time.Date(2025, 12, 20, 12, 0, 0, 0, saleLocation) }
+
+	result, err := src.FetchListings(context.Background(), "Portland", "OR")
+	require.NoError(t, err)
+	require.Len(t, result.Listings, 1)
+
+	sale := result.Listings[0]
+	assert.Equal(t, "2026-01-03T09:00:00-08:00", sale.StartDate.Format(time.RFC3339), "January seen in December is next year")
+	assert.Equal(t, "2026-01-04T15:00:00-08:00", sale.EndDate.Format(time.RFC3339), "end uses the start's layout")
+	assert.Equal(t, "TBA", sale.Address)
+}
+
+// TestSiteSourceLayoutTime tests that yearless layouts use the free-text year rule
+// and that a missing field names its selector
+func TestSiteSourceLayoutTime(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<li><time class="from">Aug 1 9:00am</time></li>`))
+	require.NoError(t, err)
+	row := doc.Find("li")
+	src := &SiteSource{loc: saleLocation}
+	now := time.Date(2025, 12, 20, 12, 0, 0, 0, saleLocation)
+
+	start, err := src.layoutTime(&Extractor{Selector: "time.from", Layout: "Jan 2 3:04pm"}, row, now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-08-01T09:00:00-07:00", start.Format(time.RFC3339), "more than two months past is next year")
+
+	_, err = src.layoutTime(&Extractor{Selector: "time.to", Layout: "Jan 2 3:04pm"}, row, now)
+	assert.EqualError(t, err, `selector "time.to" matched no text`)
+}
+
+// TestSiteConfigValidate tests that broken definitions are rejected
+func TestSiteConfigValidate(t *testing.T) {
+	valid := func() SiteConfig {
+		return SiteConfig{
+			Name: "A", ListURL: "https://a.example.com/{city}", Coverage: Coverage{State: "OR"}, Row: ".row",
+			Fields: SiteFields{Title: &Extractor{Selector: "h2"}, Dates: &Extractor{Selector: ".when"}},
+		}
+	}
+	c := valid()
+	require.NoError(t, c.validate())
+
+	tests := map[string]func(c *SiteConfig){
+		"no row":            func(c *SiteConfig) { c.Row = "" },
+		"no title":          func(c *SiteConfig) { c.Fields.Title = nil },
+		"no dates":          func(c *SiteConfig) { c.Fields.Dates = nil },
+		"start no layout":   func(c *SiteConfig) { c.Fields.Dates, c.Fields.Start = nil, &Extractor{Selector: ".start"} },
+		"bad regex":         func(c *SiteConfig) { c.Fields.Title.Regex = "(" },
+		"relative list URL": func(c *SiteConfig) { c.ListURL = "/sales" },
+	}
+	for name, breakIt := range tests {
+		c := valid()
+		breakIt(&c)
+		assert.Error(t, c.validate(), name)
+	}
+}
+
+// TestWatchSourcesFile tests that edits to the sources file are picked up
+func TestWatchSourcesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sources.yaml")
+	write := func(content string, mod time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, mod, mod))
+	}
+	names := func(s *ScraperService) []string {
+		var out []string
+		for _, src := range s.Sources() {
+			out = append(out, src.Name())
+		}
+		return out
+	}
+
+	base := time.Now().Add(-time.Hour)
+	write("ical:\n  - name: A\n    url: https://a.example.com/a.ics\n    state: OR\n", base)
+	s := &ScraperService{fetcher: NewFetcher()}
+	s.RegisterSource(&fakeSource{name: "Built In", state: "OR"})
+	config, err := LoadSourcesConfig(path)
+	require.NoError(t, err)
+	s.RegisterConfiguredSources(config)
+	assert.Equal(t, []string{"Built In", "A"}, names(s))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.WatchSourcesFile(ctx, path, time.Millisecond)
+
+	// A broken file keeps the current sources
+	write("ical: [", base.Add(time.Minute))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, []string{"Built In", "A"}, names(s))
+
+	// Renamed: B replaces A
+	write("rss:\n  - name: B\n    url: https://b.example.com/feed\n    state: OR\n", base.Add(2*time.Minute))
+	waitFor(t, func() bool {
+		n := names(s)
+		return len(n) == 2 && n[1] == "B"
+	})
+	assert.Equal(t, []string{"Built In", "B"}, names(s))
+}
@@ -0,0 +1,231 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+)
+
+// Source is a website (or feed) we can pull estate sale listings from
+type Source interface {
+	// Name identifies the source (also used as listing.ScrapedListing.SourceName)
+	Name() string
+
+	// Covers reports whether the source has listings for a city/state
+	Covers(city, state string) bool
+
+	// FetchListings scrapes the source for a city/state, stopping early when ctx is done.
+	// The result may be non-nil alongside an error (e.g. to report the HTTP status).
+	FetchListings(ctx context.Context, city, state string) (*FetchResult, error)
+}
+
+// FetchResult is what a source returned for one fetch
+type FetchResult struct {
+	Listings   []listing.ScrapedListing
+	RowsSeen   int // Rows/entries on the page before parsing (0 usually means the markup changed)
+	HTTPStatus int // Status of the listing page request (0 if none was made)
+
+	// External IDs of rows that were on the page but couldn't be parsed.
+	// They're still on the source, so they aren't marked missing.
+	SkippedIDs []string
+}
+
+// RegisterSource adds a source to the registry (replaces any source with the same name)
+func (s *ScraperService) RegisterSource(src Source) {
+	s.sourcesMu.Lock()
+	defer s.sourcesMu.Unlock()
+
+	for i, existing := range s.sources {
+		if existing.Name() == src.Name() {
+			s.sources[i] = src
+			return
+		}
+	}
+	s.sources = append(s.sources, src)
+}
+
+// UnregisterSource removes a source from the registry
+func (s *ScraperService) UnregisterSource(name string) {
+	s.sourcesMu.Lock()
+	defer s.sourcesMu.Unlock()
+
+	for i, existing := range s.sources {
+		if existing.Name() == name {
+			s.sources = append(s.sources[:i], s.sources[i+1:]...)
+			return
+		}
+	}
+}
+
+// Sources returns all registered sources
+func (s *ScraperService) Sources() []Source {
+	s.sourcesMu.RLock()
+	defer s.sourcesMu.RUnlock()
+
+	sources := make([]Source, len(s.sources))
+	copy(sources, s.sources)
+	return sources
+}
+
+// sourcesFor returns the registered sources that cover a city/state
+func (s *ScraperService) sourcesFor(city, state string) []Source {
+	var covering []Source
+	for _, src := range s.Sources() {
+		if src.Covers(city, state) {
+			covering = append(covering, src)
+		}
+	}
+	return covering
+}
+
+// breakerFor returns a source's circuit breaker, creating it on first use
+func (s *ScraperService) breakerFor(name string) *circuitBreaker {
+	s.breakersMu.Lock()
+	defer s.breakersMu.Unlock()
+
+	if s.breakers == nil {
+		s.breakers = map[string]*circuitBreaker{}
+	}
+	b, ok := s.breakers[name]
+	if !ok {
+		failures, cooldown := s.breakerFailures, s.breakerCooldown
+		if failures <= 0 {
+			failures = DefaultBreakerFailures
+		}
+		if cooldown <= 0 {
+			cooldown = DefaultBreakerCooldown
+		}
+		b = newCircuitBreaker(failures, cooldown)
+		s.breakers[name] = b
+	}
+	return b
+}
+
+// resetBreaker forgets a source's failures (e.g. after its definition changed)
+func (s *ScraperService) resetBreaker(name string) {
+	s.breakersMu.Lock()
+	defer s.breakersMu.Unlock()
+
+	delete(s.breakers, name)
+}
+
+// sourceFetch is one source's fetch for a location
+type sourceFetch struct {
+	source     Source
+	startedAt  time.Time
+	finishedAt time.Time
+	result     *FetchResult
+	err        error
+}
+
+// fetchSources runs every covering source concurrently
+func (s *ScraperService) fetchSources(ctx context.Context, city, state string) []sourceFetch {
+	sources := s.sourcesFor(city, state)
+	fetches := make([]sourceFetch, len(sources))
+
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			f := sourceFetch{source: src, startedAt: time.Now()}
+			breaker := s.breakerFor(src.Name())
+			if !breaker.allow() {
+				f.err = ErrCircuitOpen
+				f.finishedAt = f.startedAt
+				fetches[i] = f
+				return
+			}
+
+			f.result, f.err = src.FetchListings(ctx, city, state)
+			if f.err == nil && f.result == nil {
+				f.result = &FetchResult{}
+			}
+			f.finishedAt = time.Now()
+			switch {
+			case f.err != nil && ctx.Err() != nil:
+				// Cancelled by the caller, not the source's fault
+				breaker.abandon()
+			case f.err != nil:
+				if breaker.failure() {
+					log.Printf("⚠ Circuit opened for %s; skipping it for %v", src.Name(), s.breakerCooldown)
+				}
+			default:
+				breaker.success()
+			}
+			fetches[i] = f
+		}(i, src)
+	}
+	wg.Wait()
+
+	return fetches
+}
+
+// ScrapeSource fetches one named source for a city/state, whether or not it covers the
+// location and regardless of its circuit breaker (for cmd/scrape). Nothing is written
+// unless commit is set: the run then goes through the same drift check, persisting and
+// run recording as RefreshLocation, and the location's cached results are invalidated.
+// The returned run has the fill rates either way.
+func (s *ScraperService) ScrapeSource(ctx context.Context, name, city, state string, commit bool) (*FetchResult, *scrape.Run, error) {
+	var src Source
+	for _, registered := range s.Sources() {
+		if registered.Name() == name {
+			src = registered
+			break
+		}
+	}
+	if src == nil {
+		return nil, nil, fmt.Errorf("unknown source %q", name)
+	}
+	if !src.Covers(city, state) {
+		log.Printf("Note: %s doesn't cover %s, %s; fetching anyway", name, city, state)
+	}
+
+	f := sourceFetch{source: src, startedAt: time.Now()}
+	f.result, f.err = src.FetchListings(ctx, city, state)
+	if f.err == nil && f.result == nil {
+		f.result = &FetchResult{}
+	}
+	f.finishedAt = time.Now()
+
+	if !commit {
+		run := &scrape.Run{Source: name, City: city, State: state, Trigger: scrape.TriggerManual, StartedAt: f.startedAt}
+		found := 0
+		if f.result != nil {
+			run.RowsSeen, found = f.result.RowsSeen, len(f.result.Listings)
+			rates := fillRates(f.result)
+			run.FillRates = &rates
+		}
+		run.Finish(found, f.err)
+		return f.result, run, f.err
+	}
+
+	run, err := s.saveFetch(ctx, f, city, state, scrape.TriggerManual)
+	if cacheErr := s.InvalidateCache(city, state); cacheErr != nil {
+		log.Printf("Warning: Failed to invalidate cache for %s, %s: %v", city, state, cacheErr)
+	}
+	return f.result, run, err
+}
+
+// mergeListings concatenates per-source results, dropping repeated external IDs
+func mergeListings(results ...[]listing.ScrapedListing) []listing.ScrapedListing {
+	merged := []listing.ScrapedListing{}
+	seen := make(map[string]bool)
+
+	for _, listings := range results {
+		for _, l := range listings {
+			if seen[l.ExternalID] {
+				continue
+			}
+			seen[l.ExternalID] = true
+			merged = append(merged, l)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,352 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/company"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/listing"
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/scrape"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource is an in-memory Source for registry tests
+type fakeSource struct {
+	name     string
+	state    string
+	listings []listing.ScrapedListing
+	err      error
+	calls    int
+}
+
+func (f *fakeSource) Name() string { return f.name }
+
+func (f *fakeSource) Covers(city, state string) bool { return state == f.state }
+
+func (f *fakeSource) FetchListings(ctx context.Context, city, state string) (*FetchResult, error) {
+	f.calls++
+	if f.err != nil {
+		return &FetchResult{HTTPStatus: 503}, f.err
+	}
+	return &FetchResult{Listings: f.listings, RowsSeen: len(f.listings), HTTPStatus: 200}, nil
+}
+
+// fakeRunRepo records runs in memory
+type fakeRunRepo struct {
+	runs        []scrape.Run
+	quarantined []scrape.QuarantinedListing
+}
+
+func (f *fakeRunRepo) RecordRun(ctx context.Context, run *scrape.Run) error {
+	run.ID = len(f.runs) + 1
+	f.runs = append(f.runs, *run)
+	return nil
+}
+
+func (f *fakeRunRepo) ListRuns(ctx context.Context, limit int) ([]scrape.Run, error) { return f.runs, nil }
+
+func (f *fakeRunRepo) RecentRunsBySource(ctx context.Context, perSource int) ([]scrape.Run, error) { return f.runs, nil }
+
+func (f *fakeRunRepo) RecentRuns(ctx context.Context, source, city, state string, limit int) ([]scrape.Run, error) {
+	var runs []scrape.Run
+	for i := len(f.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if f.runs[i].Source == source {
+			runs = append(runs, f.runs[i])
+		}
+	}
+	return runs, nil
+}
+
+func (f *fakeRunRepo) QuarantineListings(ctx context.Context, listings []scrape.QuarantinedListing) error {
+	f.quarantined = append(f.quarantined, listings...)
+	return nil
+}
+
+func (f *fakeRunRepo) ListQuarantined(ctx context.Context, runID int) ([]scrape.QuarantinedListing, error) {
+	return f.quarantined, nil
+}
+
+// fakeListingRepo serves a location's last known sales and accepts scrape writes
+// (other methods aren't used)
+type fakeListingRepo struct {
+	listing.Repository
+	lastScrapedAt *time.Time
+	sales         []listing.Listing
+	upserted      []string       // External IDs written by scrapes
+	companyIDs    map[string]int // External ID → linked company
+	result        listing.UpsertResult
+	sessionWrites int
+	imageWrites   int
+}
+
+func (f *fakeListingRepo) UpsertExternalSale(ctx context.Context, l *listing.Listing) (listing.UpsertResult, error) {
+	f.upserted = append(f.upserted, *l.ExternalID)
+	if l.CompanyID != nil {
+		if f.companyIDs == nil {
+			f.companyIDs = make(map[string]int)
+		}
+		f.companyIDs[*l.ExternalID] = *l.CompanyID
+	}
+	if f.result != "" {
+		return f.result, nil
+	}
+	return listing.UpsertInserted, nil
+}
+
+func (f *fakeListingRepo) ReplaceSessions(ctx context.Context, listingID int, sessions []listing.Session) error {
+	f.sessionWrites++
+	return nil
+}
+
+func (f *fakeListingRepo) ReplaceExternalImages(ctx context.Context, listingID int, imageURLs []string) error {
+	f.imageWrites++
+	return nil
+}
+
+func (f *fakeListingRepo) MarkMissing(ctx context.Context, source, city, state string, seenExternalIDs []string, tombstoneAfter int) (listing.MissingResult, error) {
+	return listing.MissingResult{}, nil
+}
+
+func (f *fakeListingRepo) GetLastScrapedTime(ctx context.Context, city, state string) (*listing.Listing, error) {
+	if f.lastScrapedAt == nil {
+		return nil, nil
+	}
+	return &listing.Listing{LastScrapedAt: f.lastScrapedAt}, nil
+}
+
+func (f *fakeListingRepo) GetExternalSalesByLocation(ctx context.Context, city, state string) ([]listing.Listing, error) {
+	return f.sales, nil
+}
+
+// fakeCompanyRepo stores scraped companies by normalized name (other methods aren't used)
+type fakeCompanyRepo struct {
+	company.Repository
+	saved   map[string]company.Company
+	upserts int
+	err     error
+}
+
+func (f *fakeCompanyRepo) UpsertScraped(ctx context.Context, c *company.Company) error {
+	f.upserts++
+	if f.err != nil {
+		return f.err
+	}
+	if f.saved == nil {
+		f.saved = make(map[string]company.Company)
+	}
+	key := company.NormalizeName(c.Name)
+	if existing, ok := f.saved[key]; ok {
+		existing.MergeScraped(*c)
+		*c = existing
+	} else {
+		c.ID = len(f.saved) + 1
+	}
+	f.saved[key] = *c
+	return nil
+}
+
+// TestRegisterSourceReplacesByName tests that re-registering a name replaces the source
+func TestRegisterSourceReplacesByName(t *testing.T) {
+	s := &ScraperService{}
+	s.RegisterSource(&fakeSource{name: "a", state: "OR"})
+	s.RegisterSource(&fakeSource{name: "b", state: "WA"})
+	s.RegisterSource(&fakeSource{name: "a", state: "WA"})
+
+	sources := s.Sources()
+	require.Len(t, sources, 2)
+	assert.Len(t, s.sourcesFor("Seattle", "WA"), 2)
+	assert.Len(t, s.sourcesFor("Portland", "OR"), 0)
+}
+
+// TestRefreshLocationMergesCoveringSources tests concurrent fetch + merge
+func TestRefreshLocationMergesCoveringSources(t *testing.T) {
+	s := &ScraperService{}
+	s.RegisterSource(&fakeSource{name: "a", state: "OR", listings: []listing.ScrapedListing{
+		{ExternalID: "a-1"}, {ExternalID: "shared-1"},
+	}})
+	s.RegisterSource(&fakeSource{name: "b", state: "OR", listings: []listing.ScrapedListing{
+		{ExternalID: "b-1"}, {ExternalID: "shared-1"},
+	}})
+	s.RegisterSource(&fakeSource{name: "c", state: "WA", listings: []listing.ScrapedListing{
+		{ExternalID: "c-1"},
+	}})
+
+	sales, err := s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	require.NoError(t, err)
+
+	ids := []string{}
+	for _, sale := range sales {
+		ids = append(ids, sale.ExternalID)
+	}
+	assert.ElementsMatch(t, []string{"a-1", "b-1", "shared-1"}, ids)
+}
+
+// TestRefreshLocationPartialFailure tests that one failing source doesn't fail the location
+func TestRefreshLocationPartialFailure(t *testing.T) {
+	runs := &fakeRunRepo{}
+	s := &ScraperService{}
+	s.SetRunRepository(runs)
+	s.RegisterSource(&fakeSource{name: "ok", state: "OR", listings: []listing.ScrapedListing{{ExternalID: "ok-1"}}})
+	s.RegisterSource(&fakeSource{name: "down", state: "OR", err: fmt.Errorf("timeout")})
+
+	sales, err := s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	require.NoError(t, err)
+	assert.Len(t, sales, 1)
+
+	// One run per source
+	require.Len(t, runs.runs, 2)
+	bySource := map[string]scrape.Run{}
+	for _, run := range runs.runs {
+		bySource[run.Source] = run
+	}
+	assert.Equal(t, 1, bySource["ok"].RowsSeen)
+	assert.Equal(t, 1, bySource["ok"].ListingsFound)
+	assert.Nil(t, bySource["ok"].Error)
+	require.NotNil(t, bySource["down"].Error)
+	assert.Equal(t, "timeout", *bySource["down"].Error)
+	assert.Equal(t, 503, *bySource["down"].HTTPStatus)
+
+	s = &ScraperService{}
+	s.RegisterSource(&fakeSource{name: "down", state: "OR", err: fmt.Errorf("timeout")})
+
+	_, err = s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	assert.Error(t, err)
+}
+
+// TestRefreshLocationSkipsOpenCircuit tests that a failing source stops being called
+func TestRefreshLocationSkipsOpenCircuit(t *testing.T) {
+	runs := &fakeRunRepo{}
+	down := &fakeSource{name: "down", state: "OR", err: fmt.Errorf("timeout")}
+	s := &ScraperService{breakerFailures: 2, breakerCooldown: time.Hour}
+	s.SetRunRepository(runs)
+	s.RegisterSource(down)
+
+	for i := 0; i < 4; i++ {
+		_, err := s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, down.calls, "skipped once the circuit opened")
+	assert.Len(t, runs.runs, 2, "skipped fetches aren't recorded as runs")
+}
+
+// TestGetFeedByLocationServesLastKnown tests the stale fallback when every source fails
+func TestGetFeedByLocationServesLastKnown(t *testing.T) {
+	scrapedAt := time.Now().Add(-30 * time.Hour)
+	repo := &fakeListingRepo{lastScrapedAt: &scrapedAt, sales: []listing.Listing{
+		{Title: "Estate sale", LastScrapedAt: &scrapedAt},
+	}}
+	s := &ScraperService{repo: repo, cacheTTL: 6 * time.Hour}
+	s.RegisterSource(&fakeSource{name: "down", state: "OR", err: fmt.Errorf("timeout")})
+
+	feed, err := s.GetFeedByLocation(context.Background(), "Portland", "OR")
+	require.NoError(t, err)
+	assert.True(t, feed.Stale)
+	assert.Equal(t, &scrapedAt, feed.LastScrapedAt)
+	require.Len(t, feed.Listings, 1)
+	assert.Equal(t, "Estate sale", feed.Listings[0].Title)
+
+	// Nothing to fall back on
+	s.repo = &fakeListingRepo{}
+	_, err = s.GetFeedByLocation(context.Background(), "Portland", "OR")
+	assert.Error(t, err)
+}
+
+// TestScrapeSource tests one-off scrapes: dry runs write nothing, -commit saves
+func TestScrapeSource(t *testing.T) {
+	runs := &fakeRunRepo{}
+	repo := &fakeListingRepo{}
+	s := &ScraperService{repo: repo}
+	s.SetRunRepository(runs)
+	s.RegisterSource(&fakeSource{name: "a", state: "WA", listings: []listing.ScrapedListing{
+		{ExternalID: "a-1", City: "Portland"}, {ExternalID: "a-2"},
+	}})
+
+	result, run, err := s.ScrapeSource(context.Background(), "a", "Portland", "OR", false)
+	require.NoError(t, err, "fetched even though it doesn't cover OR")
+	assert.Len(t, result.Listings, 2)
+	assert.Equal(t, 2, run.ListingsFound)
+	assert.InDelta(t, 0.5, run.FillRates.City, 0.001)
+	assert.Empty(t, repo.upserted, "dry run")
+	assert.Empty(t, runs.runs, "dry run")
+
+	_, run, err = s.ScrapeSource(context.Background(), "a", "Portland", "OR", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a-1", "a-2"}, repo.upserted)
+	require.Len(t, runs.runs, 1)
+	assert.Equal(t, scrape.TriggerManual, runs.runs[0].Trigger)
+	assert.Equal(t, 2, run.Inserted)
+
+	_, _, err = s.ScrapeSource(context.Background(), "b", "Portland", "OR", false)
+	assert.EqualError(t, err, `unknown source "b"`)
+}
+
+// TestRefreshLocationLinksCompanies tests that scraped sales are linked to one company
+// per provider, and that a company that can't be saved doesn't fail its sales
+func TestRefreshLocationLinksCompanies(t *testing.T) {
+	grace := &listing.CompanyContact{Name: "Grace Estate Sales", ProfileURL: "https://example.com/company.php?id=87"}
+	s := &ScraperService{}
+	s.RegisterSource(&fakeSource{name: "a", state: "OR", listings: []listing.ScrapedListing{
+		{ExternalID: "a-1", Title: "One", Company: grace},
+		{ExternalID: "a-2", Title: "Two", Company: grace},
+		{ExternalID: "a-3", Title: "Three", Company: &listing.CompanyContact{Name: "Rose City Liquidators, LLC", Phone: "503-555-0100"}},
+		{ExternalID: "a-4", Title: "Four"},
+	}})
+	repo := &fakeListingRepo{}
+	companies := &fakeCompanyRepo{}
+	s.repo = repo
+	s.SetCompanyRepository(companies)
+
+	sales, err := s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	require.NoError(t, err)
+	assert.Equal(t, 2, companies.upserts, "each company saved once per run")
+	assert.Equal(t, "a", companies.saved["grace estate sales"].Source)
+	assert.Equal(t, map[string]int{"a-1": 1, "a-2": 1, "a-3": 2}, repo.companyIDs)
+	assert.Zero(t, grace.ID, "the source's contact isn't modified")
+
+	byID := map[string]*listing.CompanyContact{}
+	for _, sale := range sales {
+		byID[sale.ExternalID] = sale.Company
+	}
+	require.NotNil(t, byID["a-3"])
+	assert.Equal(t, 2, byID["a-3"].ID)
+	assert.Nil(t, byID["a-4"])
+
+	// Company failures leave the sales unlinked
+	repo = &fakeListingRepo{}
+	companies.err = fmt.Errorf("connection refused")
+	companies.upserts = 0
+	s.repo = repo
+	_, err = s.RefreshLocation(context.Background(), "Portland", "OR", scrape.TriggerScheduler)
+	require.NoError(t, err)
+	assert.Len(t, repo.upserted, 4)
+	assert.Empty(t, repo.companyIDs)
+	assert.Equal(t, 2, companies.upserts, "a failed company isn't retried for each sale")
+}
+
+// TestPersistListingsSkipsUnchanged tests that an unchanged listing's sessions and photos
+// aren't rewritten
+func TestPersistListingsSkipsUnchanged(t *testing.T) {
+	sales := []listing.ScrapedListing{
+		{ExternalID: "a-1", ImageURLs: []string{"https://example.com/1.jpg"}},
+		{ExternalID: "a-2"},
+	}
+
+	repo := &fakeListingRepo{result: listing.UpsertUnchanged}
+	s := &ScraperService{repo: repo}
+	run := &scrape.Run{Source: "a", City: "Portland", State: "OR"}
+	s.persistListings(context.Background(), sales, run)
+	assert.Equal(t, 2, run.Unchanged)
+	assert.Zero(t, repo.sessionWrites)
+	assert.Zero(t, repo.imageWrites)
+
+	repo = &fakeListingRepo{result: listing.UpsertUpdated}
+	s.repo = repo
+	run = &scrape.Run{Source: "a", City: "Portland", State: "OR"}
+	s.persistListings(context.Background(), sales, run)
+	assert.Equal(t, 2, run.Updated)
+	assert.Equal(t, 2, repo.sessionWrites)
+	assert.Equal(t, 1, repo.imageWrites, "a listing without photos keeps its old ones")
+}
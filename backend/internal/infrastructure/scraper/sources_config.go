@@ -0,0 +1,195 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattadlerpdx/estatesalefinder-ai/backend/internal/domain/address"
+	"gopkg.in/yaml.v3"
+)
+
+// SourcesConfig lists the sources defined by configuration rather than Go code.
+// It's read from the YAML (or JSON) file named by SCRAPE_SOURCES_FILE.
+type SourcesConfig struct {
+	JSONLD []JSONLDConfig `yaml:"jsonld"` // Sites publishing schema.org Event JSON-LD
+	ICal   []FeedConfig   `yaml:"ical"`   // Company calendars (.ics)
+	RSS    []FeedConfig   `yaml:"rss"`    // Company RSS/Atom feeds
+	Sites  []SiteConfig   `yaml:"sites"`  // HTML list pages described by selectors
+}
+
+// Coverage is where a configured source has sales
+type Coverage struct {
+	State  string   `yaml:"state"`  // Two-letter code or name
+	Cities []string `yaml:"cities"` // Empty means the whole state
+}
+
+// Covers reports whether city/state is in the coverage area
+func (c Coverage) Covers(city, state string) bool {
+	if address.StateCode(state) != address.StateCode(c.State) {
+		return false
+	}
+	if len(c.Cities) == 0 {
+		return true
+	}
+	for _, covered := range c.Cities {
+		if strings.EqualFold(strings.TrimSpace(covered), strings.TrimSpace(city)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the coverage names a real state
+func (c Coverage) validate() error {
+	if address.StateCode(c.State) == "" {
+		return fmt.Errorf("unknown state %q", c.State)
+	}
+	return nil
+}
+
+// LoadSourcesConfig reads and validates a sources file
+func LoadSourcesConfig(path string) (*SourcesConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sources file: %w", err)
+	}
+
+	config := &SourcesConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse sources file %s: %w", path, err)
+	}
+
+	// Source names must be unique across kinds (registering replaces by name)
+	names := map[string]bool{}
+	check := func(kind string, i int, name string, err error) error {
+		if err != nil {
+			return fmt.Errorf("%s: %s source %d: %w", path, kind, i+1, err)
+		}
+		if names[name] {
+			return fmt.Errorf("%s: duplicate source name %q", path, name)
+		}
+		names[name] = true
+		return nil
+	}
+	for i, src := range config.JSONLD {
+		if err := check("jsonld", i, src.Name, src.validate()); err != nil {
+			return nil, err
+		}
+	}
+	for i, src := range config.ICal {
+		if err := check("ical", i, src.Name, src.validate()); err != nil {
+			return nil, err
+		}
+	}
+	for i, src := range config.RSS {
+		if err := check("rss", i, src.Name, src.validate()); err != nil {
+			return nil, err
+		}
+	}
+	for i := range config.Sites {
+		if err := check("sites", i, config.Sites[i].Name, config.Sites[i].validate()); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// sourceLocation loads a configured source's timezone (saleLocation if unset)
+func sourceLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return saleLocation, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// RegisterConfiguredSources registers every source in config, replacing the sources
+// registered from an earlier config; ones no longer in it are removed. Sources that
+// can't be built (e.g. an unknown timezone) are logged and skipped.
+func (s *ScraperService) RegisterConfiguredSources(config *SourcesConfig) {
+	registered := map[string]bool{}
+	register := func(kind, name string, src Source, err error) {
+		if err != nil {
+			log.Printf("Warning: skipping %s source %q: %v", kind, name, err)
+			return
+		}
+		s.RegisterSource(src)
+		s.resetBreaker(name) // A fixed definition is tried right away
+		registered[name] = true
+	}
+	for _, c := range config.JSONLD {
+		src, err := NewJSONLDSource(c, s.fetcher)
+		register("JSON-LD", c.Name, src, err)
+	}
+	for _, c := range config.ICal {
+		src, err := NewICalSource(c, s.fetcher)
+		register("iCalendar", c.Name, src, err)
+	}
+	for _, c := range config.RSS {
+		src, err := NewRSSSource(c, s.fetcher)
+		register("RSS", c.Name, src, err)
+	}
+	for _, c := range config.Sites {
+		src, err := NewSiteSource(c, s.fetcher)
+		register("site", c.Name, src, err)
+	}
+
+	s.configuredMu.Lock()
+	defer s.configuredMu.Unlock()
+	for name := range s.configuredSources {
+		if !registered[name] {
+			s.UnregisterSource(name)
+			log.Printf("✓ Removed configured source %q", name)
+		}
+	}
+	s.configuredSources = registered
+	log.Printf("✓ Registered %d configured sources", len(registered))
+}
+
+// WatchSourcesFile reloads the sources file whenever it changes (checked every
+// interval) until ctx is done. A file that fails to load leaves the current sources
+// in place, so a typo can't take every configured source down.
+func (s *ScraperService) WatchSourcesFile(ctx context.Context, path string, interval time.Duration) {
+	lastMod := fileModTime(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		mod := fileModTime(path)
+		if mod.Equal(lastMod) {
+			continue
+		}
+		lastMod = mod
+
+		config, err := LoadSourcesConfig(path)
+		if err != nil {
+			log.Printf("Warning: keeping current sources, reload failed: %v", err)
+			continue
+		}
+		log.Printf("→ Sources file %s changed, reloading", path)
+		s.RegisterConfiguredSources(config)
+	}
+}
+
+// fileModTime returns when a file was last modified (zero if it can't be read)
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,21 @@
+-- Migration 008: Structured event hours
+-- Each listing can have several open sessions (e.g. Fri 9am-5pm, Sat 9am-3pm)
+-- listings.event_hours keeps the original free text
+
+CREATE TABLE IF NOT EXISTS listing_sessions (
+    id SERIAL PRIMARY KEY,
+    listing_id INTEGER NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT listing_sessions_order_check CHECK (ends_at > starts_at)
+);
+
+-- Load sessions per listing
+CREATE INDEX IF NOT EXISTS idx_listing_sessions_listing_id
+  ON listing_sessions(listing_id);
+
+-- "Open right now" / "open Saturday morning" lookups
+CREATE INDEX IF NOT EXISTS idx_listing_sessions_window
+  ON listing_sessions(starts_at, ends_at);
+
+COMMENT ON TABLE listing_sessions IS 'Structured open hours per listing, parsed from seller input or scraped hours text';
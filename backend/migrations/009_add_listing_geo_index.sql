@@ -0,0 +1,6 @@
+-- Migration 009: Radius search support
+-- Bounding-box prefilter on latitude/longitude before the haversine distance
+
+CREATE INDEX IF NOT EXISTS idx_listings_lat_lng
+  ON listings(latitude, longitude)
+  WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
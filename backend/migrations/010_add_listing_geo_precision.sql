@@ -0,0 +1,14 @@
+-- Migration 010: Geocode precision
+-- Coordinates may come from a street-level geocoder or a ZIP/city centroid
+
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS geo_precision VARCHAR(20);
+
+ALTER TABLE listings DROP CONSTRAINT IF EXISTS listings_geo_precision_check;
+ALTER TABLE listings ADD CONSTRAINT listings_geo_precision_check
+  CHECK (geo_precision IS NULL OR geo_precision IN ('rooftop', 'zip', 'city'));
+
+-- Earlier scrapes stored 0,0 for "no coordinates"
+UPDATE listings SET latitude = NULL, longitude = NULL
+  WHERE latitude = 0 AND longitude = 0;
+
+COMMENT ON COLUMN listings.geo_precision IS 'How precise latitude/longitude are: rooftop (street address), zip or city centroid';
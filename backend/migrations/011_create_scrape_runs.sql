@@ -0,0 +1,19 @@
+-- Migration 011: Scrape run log
+-- One row per refresh of a location, written by the scheduler and by request-path revalidation
+
+CREATE TABLE IF NOT EXISTS scrape_runs (
+    id SERIAL PRIMARY KEY,
+    city VARCHAR(100) NOT NULL,
+    state VARCHAR(50) NOT NULL,
+    trigger VARCHAR(20) NOT NULL, -- 'scheduler', 'request'
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    listings_found INTEGER NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+-- Recent runs first
+CREATE INDEX IF NOT EXISTS idx_scrape_runs_started_at
+  ON scrape_runs(started_at DESC);
+
+COMMENT ON TABLE scrape_runs IS 'Log of scraper refreshes per location';
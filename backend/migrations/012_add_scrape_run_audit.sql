@@ -0,0 +1,18 @@
+-- Migration 012: Per-source scrape audit
+-- scrape_runs becomes one row per source per location, with what the source returned
+-- and what persisting it did
+
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS source VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS http_status INTEGER;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS rows_seen INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS inserted INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS updated INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS unchanged INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS failed INTEGER NOT NULL DEFAULT 0;
+
+-- Latest runs per source/location (health summary)
+CREATE INDEX IF NOT EXISTS idx_scrape_runs_source_location
+  ON scrape_runs(source, LOWER(city), UPPER(state), started_at DESC);
+
+COMMENT ON COLUMN scrape_runs.rows_seen IS 'Rows found on the source page before parsing; 0 after a healthy history usually means the markup changed';
+COMMENT ON COLUMN scrape_runs.unchanged IS 'Listings whose content matched what we had (only last_scraped_at was bumped)';
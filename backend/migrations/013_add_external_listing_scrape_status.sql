@@ -0,0 +1,34 @@
+-- Migration 013: Expire external listings that disappear from their source
+-- Each scrape marks the source's listings for that location it didn't see as missing;
+-- after N misses in a row they're tombstoned and hidden from public queries
+
+-- 1. Scrape status (NULL for owned listings)
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS scrape_status VARCHAR(20);
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS missed_scrapes INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE listings DROP CONSTRAINT IF EXISTS listings_scrape_status_check;
+ALTER TABLE listings ADD CONSTRAINT listings_scrape_status_check
+  CHECK (scrape_status IS NULL OR scrape_status IN ('active', 'missing', 'tombstoned'));
+
+-- 2. The location whose scrape last saw the listing (a Portland scrape also returns Beaverton sales)
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS scrape_city VARCHAR(100);
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS scrape_state VARCHAR(50);
+
+-- 3. Backfill existing external listings
+UPDATE listings SET
+  scrape_status = 'active',
+  scrape_city = city,
+  scrape_state = state
+WHERE listing_type = 'external' AND scrape_status IS NULL;
+
+-- 4. Missing-listing sweep per source and location
+CREATE INDEX IF NOT EXISTS idx_listings_scrape_location
+  ON listings(external_source, LOWER(scrape_city), UPPER(scrape_state))
+  WHERE listing_type = 'external';
+
+-- 5. Per-run counts
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS missing INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS tombstoned INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN listings.scrape_status IS 'External listings: active, missing (not seen in latest scrape) or tombstoned (hidden)';
+COMMENT ON COLUMN listings.missed_scrapes IS 'Consecutive scrapes of scrape_city/scrape_state that did not see this listing';
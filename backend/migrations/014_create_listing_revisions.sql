@@ -0,0 +1,22 @@
+-- Migration 014: Change history for external listings
+-- Each scrape that changes a listing's content records a field-level diff,
+-- so buyers can see e.g. an address going from "TBA" to a real street
+
+-- 1. Revisions (one row per content-changing upsert)
+CREATE TABLE IF NOT EXISTS listing_revisions (
+  id SERIAL PRIMARY KEY,
+  listing_id INTEGER NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+  changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  changes JSONB NOT NULL
+);
+
+-- 2. Timeline lookups
+CREATE INDEX IF NOT EXISTS idx_listing_revisions_listing
+  ON listing_revisions(listing_id, changed_at);
+
+-- 3. When a withheld address was first published
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS address_released_at TIMESTAMPTZ;
+
+COMMENT ON TABLE listing_revisions IS 'Field-level diffs recorded when a scrape changes an external listing';
+COMMENT ON COLUMN listing_revisions.changes IS 'JSON array of {field, old, new}';
+COMMENT ON COLUMN listings.address_released_at IS 'When address_line1 went from TBA/blank to a street address';
@@ -0,0 +1,15 @@
+-- Migration 015: Rename sale_images to listing_images
+-- Migration 003 renamed sale_images.sale_id but not the table, while the repository
+-- has always used listing_images. Scraped listings now store their detail-page photos here.
+
+-- 1. Rename the table and its indexes (skipped if already done)
+DO $$
+BEGIN
+  IF to_regclass('sale_images') IS NOT NULL AND to_regclass('listing_images') IS NULL THEN
+    ALTER TABLE sale_images RENAME TO listing_images;
+    ALTER INDEX IF EXISTS idx_sale_images_sale_id RENAME TO idx_listing_images_listing_id;
+    ALTER INDEX IF EXISTS idx_sale_images_is_primary RENAME TO idx_listing_images_is_primary;
+  END IF;
+END $$;
+
+COMMENT ON TABLE listing_images IS 'Photos for owned listings (uploaded) and external listings (source URLs, replaced each scrape)';
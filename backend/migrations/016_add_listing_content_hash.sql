@@ -0,0 +1,8 @@
+-- Migration 016: Content hash for external listings
+-- Scrapes compare a listing's hash before loading and diffing the stored row,
+-- so unchanged listings only get last_scraped_at bumped
+
+-- 1. SHA-256 of the scraped content fields (see Listing.ContentHash)
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+
+COMMENT ON COLUMN listings.content_hash IS 'Hex SHA-256 of the scraped content; NULL until the next scrape rewrites or confirms the row';
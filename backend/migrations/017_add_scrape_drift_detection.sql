@@ -0,0 +1,27 @@
+-- Migration 017: Selector drift detection
+-- Each run records the share of sale rows that yielded a date, address, ZIP and city.
+-- A run whose rates collapse against the source's recent runs is flagged as degraded
+-- and its listings are quarantined instead of overwriting the stored ones
+
+-- 1. Fill rates and degraded flag per run
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS fill_rates JSONB;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS degraded_reason TEXT;
+ALTER TABLE scrape_runs ADD COLUMN IF NOT EXISTS quarantined INTEGER NOT NULL DEFAULT 0;
+
+-- 2. Listings held back from degraded runs
+CREATE TABLE IF NOT EXISTS scrape_quarantine (
+  id SERIAL PRIMARY KEY,
+  run_id INTEGER NOT NULL REFERENCES scrape_runs(id) ON DELETE CASCADE,
+  source VARCHAR(100) NOT NULL,
+  external_id VARCHAR(255) NOT NULL,
+  listing JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scrape_quarantine_run ON scrape_quarantine(run_id);
+
+COMMENT ON COLUMN scrape_runs.fill_rates IS 'JSON {rows, date, address, zip_code, city}: share of sale rows that yielded each field';
+COMMENT ON COLUMN scrape_runs.degraded IS 'Fill rates collapsed against the baseline; listings were quarantined, not upserted';
+COMMENT ON COLUMN scrape_runs.degraded_reason IS 'Fields that dropped, e.g. "address 4% (usually 96%)"';
+COMMENT ON TABLE scrape_quarantine IS 'Listings from degraded scrape runs, kept for inspection instead of being upserted';
@@ -0,0 +1,35 @@
+-- Migration 018: Estate sale companies
+-- The company running a sale becomes its own entity, created by scrapers from the
+-- sale's provider details and by sellers for their account, and linked from listings
+
+-- 1. Companies
+CREATE TABLE IF NOT EXISTS companies (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(200) NOT NULL,
+  name_norm VARCHAR(200) NOT NULL,
+  website TEXT,
+  phone VARCHAR(50),
+  email VARCHAR(255),
+  source VARCHAR(100),
+  profile_url TEXT,
+  seller_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 2. Matching scraped companies (profile URL first, then name) and one company per seller
+CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_profile_url
+  ON companies(profile_url) WHERE profile_url IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_companies_name_norm ON companies(name_norm);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_seller
+  ON companies(seller_id) WHERE seller_id IS NOT NULL;
+
+-- 3. Listings link to the company running them
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_listings_company ON listings(company_id, start_date);
+
+COMMENT ON TABLE companies IS 'Estate sale companies, from scraped provider details or seller accounts';
+COMMENT ON COLUMN companies.name_norm IS 'Lower-cased name without punctuation or business suffixes (see company.NormalizeName)';
+COMMENT ON COLUMN companies.profile_url IS 'The company''s page on the source it was scraped from';
+COMMENT ON COLUMN companies.seller_id IS 'Seller account managing the company; scraped companies are never matched to these';
+COMMENT ON COLUMN listings.company_id IS 'Company running the sale (scraped provider or the seller''s company)';